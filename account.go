@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/supabase-community/gotrue-go/types"
+)
+
+// Los correos de estas funciones los envía Supabase Auth. Con un proyecto local
+// (supabase start) llegan a la bandeja de captura de Inbucket en vez de salir a
+// internet, así que el flujo completo se puede probar sin cuentas reales. Con
+// MemoryBackend los códigos quedan en MemoryBackend.Outbox.
+
+// AuthResult es la respuesta de los métodos de cuenta: login, registro, refresco,
+// confirmaciones por código y cambios de perfil
+type AuthResult struct {
+	Result
+	Token                     string       `json:"token,omitempty"`
+	ExpiresAt                 int64        `json:"expiresAt,omitempty"`
+	User                      *UserProfile `json:"user,omitempty"`
+	FieldErrors               []FieldError `json:"fieldErrors,omitempty"`
+	RetryAfter                int          `json:"retryAfter,omitempty"`                // Segundos hasta poder reintentar el login
+	EmailVerificationRequired bool         `json:"emailVerificationRequired,omitempty"` // Registro sin sesión hasta confirmar el email
+	PendingEmail              string       `json:"pendingEmail,omitempty"`              // Email nuevo a la espera de confirmación
+}
+
+// RequestPasswordReset envía el código para restablecer la contraseña. La
+// respuesta es la misma exista o no la cuenta, para no revelar qué emails están registrados.
+func (a *App) RequestPasswordReset(email string) AuthResult {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return AuthResult{Result: failResult(ErrInvalidArgument, "Email is required", nil)}
+	}
+	if err := a.backend.Auth().Recover(email); err != nil {
+		a.logger(LogSubsystemAuth).Warningf("RequestPasswordReset: %v", err)
+	}
+	return AuthResult{Result: okResult(MsgPasswordResetSent)}
+}
+
+// ConfirmPasswordReset canjea el código del correo, fija la contraseña nueva e inicia sesión
+func (a *App) ConfirmPasswordReset(email, code, newPassword string) AuthResult {
+	email = strings.TrimSpace(email)
+	code = strings.TrimSpace(code)
+	if email == "" || code == "" {
+		return AuthResult{Result: failResult(ErrInvalidArgument, "Email and code are required", nil)}
+	}
+	if fieldErr := validatePassword("newPassword", newPassword, email, ""); fieldErr != nil {
+		return fieldErrorsResult([]FieldError{*fieldErr})
+	}
+
+	session, err := a.backend.Auth().VerifyOTP(types.VerificationTypeRecovery, email, code)
+	if err != nil {
+		a.logger(LogSubsystemAuth).Warningf("ConfirmPasswordReset: %v", err)
+		return AuthResult{Result: failResult(ErrAuthInvalidToken, "Invalid or expired code", nil)}
+	}
+	if _, err := a.backend.Auth().UpdateUser(session.AccessToken, types.UpdateUserRequest{Password: &newPassword}); err != nil {
+		if fieldErr := fieldErrorFromAuth(err, "email", "newPassword"); fieldErr != nil {
+			return fieldErrorsResult([]FieldError{*fieldErr})
+		}
+		return AuthResult{Result: failResult(ErrAuthUnavailable, "Could not update password", err)}
+	}
+	return a.startVerifiedSession(session, MsgPasswordUpdated)
+}
+
+// ResendVerification vuelve a enviar el correo de confirmación del registro
+func (a *App) ResendVerification(email string) AuthResult {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return AuthResult{Result: failResult(ErrInvalidArgument, "Email is required", nil)}
+	}
+	if err := a.backend.Auth().Resend(types.VerificationTypeSignup, email); err != nil {
+		var authErr *AuthError
+		if errors.As(err, &authErr) && authErr.Status == 429 {
+			return AuthResult{Result: failResult(ErrAuthRateLimited, "Please wait before requesting another email", nil)}
+		}
+		a.logger(LogSubsystemAuth).Warningf("ResendVerification: %v", err)
+	}
+	return AuthResult{Result: okResult(MsgVerificationSent)}
+}
+
+// VerifyEmail confirma el email con el código del correo de registro e inicia sesión
+func (a *App) VerifyEmail(email, code string) AuthResult {
+	email = strings.TrimSpace(email)
+	code = strings.TrimSpace(code)
+	if email == "" || code == "" {
+		return AuthResult{Result: failResult(ErrInvalidArgument, "Email and code are required", nil)}
+	}
+	session, err := a.backend.Auth().VerifyOTP(types.VerificationTypeSignup, email, code)
+	if err != nil {
+		a.logger(LogSubsystemAuth).Warningf("VerifyEmail: %v", err)
+		return AuthResult{Result: failResult(ErrAuthInvalidToken, "Invalid or expired code", nil)}
+	}
+	return a.startVerifiedSession(session, MsgEmailVerified)
+}
+
+// startVerifiedSession guarda la sesión obtenida con un código y responde como Login
+func (a *App) startVerifiedSession(session *AuthSession, message MessageKey) AuthResult {
+	if err := a.session.Start(session); err != nil {
+		a.logger(LogSubsystemAuth).Warningf("Could not persist session: %v", err)
+	}
+	result := AuthResult{
+		Result:    okResult(message),
+		Token:     session.AccessToken,
+		ExpiresAt: session.ExpiresAt,
+	}
+	if user, err := a.backend.Users().FindByID(session.AccessToken, session.UserID); err == nil {
+		user.Email = session.Email
+		result.User = user
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/supabase-community/gotrue-go/types"
+)
+
+// lastCode devuelve el código del último correo de tipo kind en la bandeja de backend
+func lastCode(t *testing.T, backend *MemoryBackend, kind types.VerificationType) string {
+	t.Helper()
+	outbox := backend.Outbox()
+	for i := len(outbox) - 1; i >= 0; i-- {
+		if outbox[i].Kind == kind {
+			return outbox[i].Code
+		}
+	}
+	t.Fatalf("no %s mail in outbox %+v", kind, outbox)
+	return ""
+}
+
+func TestPasswordResetWithMemoryBackend(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 0)
+	const newPassword = "green-leaf-73"
+
+	// Una cuenta inexistente recibe la misma respuesta, pero ningún correo
+	if result := a.RequestPasswordReset("jinx@example.com"); !result.Success {
+		t.Fatalf("RequestPasswordReset for an unknown email = %+v", result)
+	}
+	if outbox := backend.Outbox(); len(outbox) != 0 {
+		t.Fatalf("outbox = %+v, want empty", outbox)
+	}
+
+	if result := a.RequestPasswordReset("AHRI@example.com"); !result.Success {
+		t.Fatalf("RequestPasswordReset = %+v", result)
+	}
+	first := lastCode(t, backend, types.VerificationTypeRecovery)
+	if result := a.RequestPasswordReset("ahri@example.com"); !result.Success {
+		t.Fatalf("second RequestPasswordReset = %+v", result)
+	}
+	code := lastCode(t, backend, types.VerificationTypeRecovery)
+	if outbox := backend.Outbox(); len(outbox) != 2 || outbox[1].To != "ahri@example.com" {
+		t.Fatalf("outbox = %+v", outbox)
+	}
+
+	// Un código nuevo invalida el anterior
+	if first != code {
+		if result := a.ConfirmPasswordReset("ahri@example.com", first, newPassword); result.Code != ErrAuthInvalidToken {
+			t.Errorf("ConfirmPasswordReset with the old code = %+v", result)
+		}
+	}
+	if result := a.ConfirmPasswordReset("ahri@example.com", "wrong", newPassword); result.Code != ErrAuthInvalidToken {
+		t.Errorf("ConfirmPasswordReset with a wrong code = %+v", result)
+	}
+	if result := a.ConfirmPasswordReset("ahri@example.com", code, "short"); len(result.FieldErrors) != 1 {
+		t.Errorf("ConfirmPasswordReset with a weak password = %+v", result)
+	}
+
+	result := a.ConfirmPasswordReset("ahri@example.com", code, newPassword)
+	if !result.Success || result.Token == "" || result.User == nil || result.User.Login != "ahri" {
+		t.Fatalf("ConfirmPasswordReset = %+v", result)
+	}
+	if session := a.session.Current(); session == nil || session.UserID != testUserID {
+		t.Errorf("session after reset = %+v", session)
+	}
+	// El código se canjea una sola vez
+	if result := a.ConfirmPasswordReset("ahri@example.com", code, "other-leaf-74"); result.Code != ErrAuthInvalidToken {
+		t.Errorf("reusing the code = %+v", result)
+	}
+
+	if result := a.Login("ahri", testPassword); result.Code != ErrAuthInvalidCredentials {
+		t.Errorf("Login with the old password = %+v", result)
+	}
+	if result := a.Login("ahri", newPassword); !result.Success {
+		t.Errorf("Login with the new password = %+v", result)
+	}
+}
+
+// Sin confirmar el email no se gastan fichas; el código reenviado lo confirma
+func TestVerifyEmailUnlocksDownload(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 1)
+	backend.SetEmailConfirmed(testUserID, false)
+	login(t, a)
+
+	if result := downloadDynastyAhri(a); result.Success || result.Reason != DenialEmailUnverified {
+		t.Fatalf("download before verifying = %+v", result)
+	}
+	if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != 1 {
+		t.Errorf("tokens left = %d, want 1", profile.FichasPorSkin)
+	}
+
+	if result := a.ResendVerification("ahri@example.com"); !result.Success {
+		t.Fatalf("ResendVerification = %+v", result)
+	}
+	code := lastCode(t, backend, types.VerificationTypeSignup)
+
+	// Un código de otro tipo o de otro email no sirve
+	if result := a.VerifyEmail("jinx@example.com", code); result.Code != ErrAuthInvalidToken {
+		t.Errorf("VerifyEmail with another email = %+v", result)
+	}
+	if result := a.ConfirmPasswordReset("ahri@example.com", code, "green-leaf-73"); result.Code != ErrAuthInvalidToken {
+		t.Errorf("signup code accepted as a recovery code: %+v", result)
+	}
+
+	result := a.VerifyEmail("ahri@example.com", code)
+	if !result.Success || result.Token == "" {
+		t.Fatalf("VerifyEmail = %+v", result)
+	}
+	if result := downloadDynastyAhri(a); !result.Success || result.Remaining != 0 {
+		t.Errorf("download after verifying = %+v", result)
+	}
+
+	// Ya confirmado, reenviar responde igual pero no manda otro correo
+	if result := a.ResendVerification("ahri@example.com"); !result.Success {
+		t.Errorf("ResendVerification after verifying = %+v", result)
+	}
+	if outbox := backend.Outbox(); len(outbox) != 1 {
+		t.Errorf("outbox = %+v, want a single mail", outbox)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MetadataBackupCount es el número de copias buenas que se conservan de cada
+// archivo de metadatos (installed.json, mod-status.json) como .bak1..N
+const MetadataBackupCount = 3
+
+// writeFileAtomic escribe data en path de forma segura ante cortes:
+// escribe a un temporal en el mismo directorio, hace fsync, rota las copias
+// de seguridad existentes y finalmente renombra el temporal sobre path.
+// Un crash en cualquier punto deja o bien el archivo anterior o el nuevo, nunca uno truncado.
+func writeFileAtomic(path string, data []byte, perm os.FileMode, backups int) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for %s: %w", path, err)
+	}
+	tmpPath := tmp.Name()
+	// Si algo falla antes del rename, no dejar basura
+	committed := false
+	defer func() {
+		if !committed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing temp file %s: %w", tmpPath, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error syncing temp file %s: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing temp file %s: %w", tmpPath, err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("error setting permissions on %s: %w", tmpPath, err)
+	}
+
+	if backups > 0 {
+		if err := rotateBackups(path, backups); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error replacing %s: %w", path, err)
+	}
+	committed = true
+	syncDir(dir)
+	return nil
+}
+
+// rotateBackups desplaza path.bak1..N una posición y copia el archivo actual a path.bak1.
+// Si el actual no es JSON válido no se rota: una copia dañada no debe desplazar
+// a la última buena. Los archivos con copias de seguridad son todos JSON.
+func rotateBackups(path string, backups int) error {
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil // Nada que respaldar todavía
+	}
+	if err != nil || validateJSON(current) != nil {
+		return nil // Se reemplaza sin respaldar; .bak1..N quedan intactas
+	}
+	for i := backups - 1; i >= 1; i-- {
+		from := backupPath(path, i)
+		if _, err := os.Stat(from); err == nil {
+			if err := os.Rename(from, backupPath(path, i+1)); err != nil {
+				return fmt.Errorf("error rotating backup %s: %w", from, err)
+			}
+		}
+	}
+	if err := copyFile(path, backupPath(path, 1)); err != nil {
+		return fmt.Errorf("error backing up %s: %w", path, err)
+	}
+	return nil
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak%d", path, n)
+}
+
+// syncDir intenta persistir la entrada de directorio tras el rename.
+// En Windows no se puede abrir un directorio para fsync, así que el error se ignora.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	d.Close()
+}
+
+// readFileWithBackups lee path y lo valida con validate. Si el archivo falta,
+// no se puede leer o no pasa la validación, prueba las copias .bak1..N de la más
+// nueva a la más vieja. Devuelve los datos, la ruta de donde salieron y un error
+// solo si no hay ninguna copia válida. Si el principal no existe y tampoco hay copias,
+// devuelve el error os.ErrNotExist original.
+func readFileWithBackups(path string, backups int, validate func([]byte) error) ([]byte, string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err = validate(data); err == nil {
+			return data, path, nil
+		}
+		err = fmt.Errorf("invalid %s: %w", path, err)
+	}
+	primaryErr := err
+
+	for i := 1; i <= backups; i++ {
+		bak := backupPath(path, i)
+		bakData, bakErr := os.ReadFile(bak)
+		if bakErr != nil {
+			continue
+		}
+		if validate(bakData) == nil {
+			return bakData, bak, nil
+		}
+	}
+	return nil, "", primaryErr
+}
+
+// validateJSON acepta cualquier documento JSON bien formado
+func validateJSON(data []byte) error {
+	var v interface{}
+	return json.Unmarshal(data, &v)
+}
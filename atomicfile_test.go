@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomicRotatesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "installed.json")
+	for _, content := range []string{`{"v":1}`, `{"v":2}`, `{"v":3}`} {
+		if err := writeFileAtomic(path, []byte(content), 0644, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for file, want := range map[string]string{
+		path:                `{"v":3}`,
+		backupPath(path, 1): `{"v":2}`,
+		backupPath(path, 2): `{"v":1}`,
+	} {
+		got, err := os.ReadFile(file)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(file), got, err, want)
+		}
+	}
+}
+
+func TestWriteFileAtomicKeepsBackupsWhenCurrentIsCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "installed.json")
+	if err := writeFileAtomic(path, []byte(`{"v":1}`), 0644, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte(`{"v":2}`), 0644, 2); err != nil {
+		t.Fatal(err)
+	}
+	// Un corte a medio escribir por fuera de writeFileAtomic
+	if err := os.WriteFile(path, []byte(`{"v":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte(`{"v":3}`), 0644, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(backupPath(path, 1)); string(got) != `{"v":1}` {
+		t.Errorf(".bak1 = %q; the corrupt file must not be rotated in", got)
+	}
+	if _, err := os.Stat(backupPath(path, 2)); !os.IsNotExist(err) {
+		t.Errorf(".bak2 should not exist, got %v", err)
+	}
+}
+
+func TestReadFileWithBackupsFallsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mod-status.json")
+	os.WriteFile(path, []byte(`not json`), 0644)
+	os.WriteFile(backupPath(path, 1), []byte(`{`), 0644)
+	os.WriteFile(backupPath(path, 2), []byte(`{"status":"stopped"}`), 0644)
+
+	data, source, err := readFileWithBackups(path, 3, validateJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != backupPath(path, 2) || string(data) != `{"status":"stopped"}` {
+		t.Errorf("got %q from %s", data, source)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	if _, _, err := readFileWithBackups(missing, 3, validateJSON); !os.IsNotExist(err) {
+		t.Errorf("missing file: got %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/supabase-community/gotrue-go"
+	"github.com/supabase-community/gotrue-go/types"
+)
+
+// AuthSession son los tokens de una sesión de Supabase Auth
+type AuthSession struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresAt    int64  `json:"expiresAt"` // Unix, segundos
+	UserID       string `json:"userId"`
+	Email        string `json:"email"`
+}
+
+// AuthClaims son los datos de un access token ya verificado
+type AuthClaims struct {
+	UserID    string `json:"userId"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// AuthError es un error de GoTrue con el código HTTP y el código de error del servidor
+type AuthError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("auth error %d (%s): %s", e.Status, e.Code, e.Message)
+	}
+	return fmt.Sprintf("auth error %d: %s", e.Status, e.Message)
+}
+
+// EmailNotConfirmed indica que la contraseña es correcta pero falta confirmar el
+// email. Las versiones viejas de GoTrue lo mandan como invalid_grant con ese texto.
+func (e *AuthError) EmailNotConfirmed() bool {
+	return e.Code == "email_not_confirmed" || strings.EqualFold(e.Message, "Email not confirmed")
+}
+
+// InvalidCredentials indica que el servidor rechazó el email o la contraseña
+func (e *AuthError) InvalidCredentials() bool {
+	return e.Status == 400 && !e.EmailNotConfirmed() && (e.Code == "invalid_credentials" || e.Code == "invalid_grant")
+}
+
+// AuthProvider es lo que la app necesita de un servidor de auth. AuthService lo
+// implementa con Supabase Auth y MemoryBackend con usuarios en memoria.
+type AuthProvider interface {
+	SignIn(email, password string) (*AuthSession, error)
+	SignInWithLogin(login, password string) (*AuthSession, error)
+	SignUp(email, password string, data map[string]interface{}) (userID string, session *AuthSession, err error)
+	Refresh(refreshToken string) (*AuthSession, error)
+	SignOut(accessToken string) error
+	RevokeSession(accessToken string) error
+	UpdateUser(accessToken string, req types.UpdateUserRequest) (*types.User, error)
+	HealthCheck() error
+	Recover(email string) error
+	VerifyOTP(kind types.VerificationType, email, code string) (*AuthSession, error)
+	Resend(kind types.VerificationType, email string) error
+	IsEmailVerified(accessToken string) (bool, error)
+	VerifyAccessToken(accessToken string) (*AuthClaims, error)
+}
+
+// AuthService habla con Supabase Auth (GoTrue). Login, registro y refresco de
+// sesión ocurren en el servidor; la app solo guarda tokens y los verifica con el JWKS.
+type AuthService struct {
+	projectURL string
+	apiKey     string
+	authURL    string
+	httpClient *http.Client
+	client     gotrue.Client
+	verifier   *JWKSVerifier
+}
+
+// NewAuthService crea el servicio para el proyecto en projectURL. Pasar la URL de un
+// servidor local permite probar el flujo completo sin tocar Supabase.
+func NewAuthService(projectURL, apiKey string, httpClient *http.Client) *AuthService {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	projectURL = strings.TrimRight(projectURL, "/")
+	authURL := projectURL + "/auth/v1"
+	return &AuthService{
+		projectURL: projectURL,
+		apiKey:     apiKey,
+		authURL:    authURL,
+		httpClient: httpClient,
+		client:     gotrue.New("", apiKey).WithCustomGoTrueURL(authURL).WithClient(*httpClient),
+		verifier:   NewJWKSVerifier(authURL, httpClient),
+	}
+}
+
+// SignIn inicia sesión con email y contraseña
+func (s *AuthService) SignIn(email, password string) (*AuthSession, error) {
+	resp, err := s.client.SignInWithEmailPassword(email, password)
+	if err != nil {
+		return nil, parseAuthError(err)
+	}
+	return sessionFromGoTrue(resp.Session), nil
+}
+
+// SignInWithLoginFunction es la función de Supabase (supabase/functions) que inicia
+// sesión con el nombre de usuario
+const SignInWithLoginFunction = "sign-in-with-login"
+
+// SignInWithLogin inicia sesión con el nombre de usuario en vez del email. La función
+// del servidor resuelve el email con la clave de servicio, así que nunca llega al
+// cliente, y responde como POST /token: un login inexistente da el mismo error que
+// una contraseña incorrecta.
+func (s *AuthService) SignInWithLogin(login, password string) (*AuthSession, error) {
+	payload, _ := json.Marshal(map[string]string{"login": login, "password": password})
+	req, err := http.NewRequest("POST", s.projectURL+"/functions/v1/"+SignInWithLoginFunction, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("apikey", s.apiKey)
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling %s: %v", SignInWithLoginFunction, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s response: %v", SignInWithLoginFunction, err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, parseAuthError(fmt.Errorf("response status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+	var session types.Session
+	if err := json.Unmarshal(body, &session); err != nil || session.AccessToken == "" {
+		return nil, fmt.Errorf("invalid %s response", SignInWithLoginFunction)
+	}
+	return sessionFromGoTrue(session), nil
+}
+
+// SignUp registra un usuario. data se guarda como user_metadata (p. ej. el login),
+// de donde lo toma el trigger que crea la fila en public.users. Si el proyecto
+// exige confirmar el email, la sesión devuelta es nil.
+func (s *AuthService) SignUp(email, password string, data map[string]interface{}) (userID string, session *AuthSession, err error) {
+	resp, err := s.client.Signup(types.SignupRequest{Email: email, Password: password, Data: data})
+	if err != nil {
+		return "", nil, parseAuthError(err)
+	}
+	if resp.AccessToken != "" {
+		session = sessionFromGoTrue(resp.Session)
+		return session.UserID, session, nil
+	}
+	return resp.User.ID.String(), nil, nil
+}
+
+// Refresh cambia un refresh token por una sesión nueva
+func (s *AuthService) Refresh(refreshToken string) (*AuthSession, error) {
+	resp, err := s.client.RefreshToken(refreshToken)
+	if err != nil {
+		return nil, parseAuthError(err)
+	}
+	return sessionFromGoTrue(resp.Session), nil
+}
+
+// SignOut revoca la sesión en el servidor (incluido su refresh token)
+func (s *AuthService) SignOut(accessToken string) error {
+	if err := s.client.WithToken(accessToken).Logout(); err != nil {
+		return parseAuthError(err)
+	}
+	return nil
+}
+
+// RevokeSession revoca solo la sesión de accessToken (scope=local); las demás
+// sesiones del usuario siguen abiertas. gotrue-go no permite elegir el scope.
+func (s *AuthService) RevokeSession(accessToken string) error {
+	req, err := http.NewRequest("POST", s.authURL+"/logout?scope=local", nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("apikey", s.apiKey)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling logout: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return parseAuthError(fmt.Errorf("response status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+	return nil
+}
+
+// GetUser pide al servidor el usuario dueño del token
+func (s *AuthService) GetUser(accessToken string) (*types.User, error) {
+	resp, err := s.client.WithToken(accessToken).GetUser()
+	if err != nil {
+		return nil, parseAuthError(err)
+	}
+	return &resp.User, nil
+}
+
+// UpdateUser cambia email o contraseña del usuario dueño del token. Un email nuevo
+// queda en User.EmailChange hasta que se confirma con el enlace que envía GoTrue.
+func (s *AuthService) UpdateUser(accessToken string, req types.UpdateUserRequest) (*types.User, error) {
+	resp, err := s.client.WithToken(accessToken).UpdateUser(req)
+	if err != nil {
+		return nil, parseAuthError(err)
+	}
+	return &resp.User, nil
+}
+
+// HealthCheck comprueba que el servidor de auth responda
+func (s *AuthService) HealthCheck() error {
+	_, err := s.client.HealthCheck()
+	return err
+}
+
+// Recover pide a GoTrue que envíe el código para restablecer la contraseña de email
+func (s *AuthService) Recover(email string) error {
+	if err := s.client.Recover(types.RecoverRequest{Email: email}); err != nil {
+		return parseAuthError(err)
+	}
+	return nil
+}
+
+// VerifyOTP canjea el código de un correo (signup, recovery, email_change) por una sesión
+func (s *AuthService) VerifyOTP(kind types.VerificationType, email, code string) (*AuthSession, error) {
+	resp, err := s.client.VerifyForUser(types.VerifyForUserRequest{Type: kind, Email: email, Token: code})
+	if err != nil {
+		return nil, parseAuthError(err)
+	}
+	return sessionFromGoTrue(resp.Session), nil
+}
+
+// Resend vuelve a enviar el correo de confirmación de kind (signup o email_change).
+// gotrue-go no cubre POST /resend, así que se llama directo.
+func (s *AuthService) Resend(kind types.VerificationType, email string) error {
+	payload, _ := json.Marshal(map[string]string{"type": string(kind), "email": email})
+	req, err := http.NewRequest("POST", s.authURL+"/resend", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("apikey", s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling resend: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return parseAuthError(fmt.Errorf("response status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+	return nil
+}
+
+// IsEmailVerified pregunta al servidor si el dueño del token confirmó su email
+func (s *AuthService) IsEmailVerified(accessToken string) (bool, error) {
+	user, err := s.GetUser(accessToken)
+	if err != nil {
+		return false, err
+	}
+	return user.EmailConfirmedAt != nil, nil
+}
+
+// VerifyAccessToken valida un access token localmente con el JWKS del proyecto.
+// Si el proyecto todavía firma con un secreto compartido (HS256), la verificación
+// se delega a GoTrue con GET /user, porque la app nunca tiene ese secreto.
+func (s *AuthService) VerifyAccessToken(accessToken string) (*AuthClaims, error) {
+	if accessToken == "" {
+		return nil, fmt.Errorf("missing token")
+	}
+	claims, err := s.verifier.Verify(accessToken)
+	if err == errUnverifiableToken {
+		user, userErr := s.GetUser(accessToken)
+		if userErr != nil {
+			return nil, userErr
+		}
+		// El servidor ya validó firma y expiración; solo leemos exp sin verificar
+		unverified := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(accessToken, unverified); err != nil {
+			return nil, err
+		}
+		return &AuthClaims{UserID: user.ID.String(), Email: user.Email, ExpiresAt: claimInt64(unverified, "exp")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sub, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	return &AuthClaims{UserID: sub, Email: email, ExpiresAt: claimInt64(claims, "exp")}, nil
+}
+
+func sessionFromGoTrue(session types.Session) *AuthSession {
+	expiresAt := session.ExpiresAt
+	if expiresAt == 0 && session.ExpiresIn > 0 {
+		expiresAt = time.Now().Add(time.Duration(session.ExpiresIn) * time.Second).Unix()
+	}
+	return &AuthSession{
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
+		ExpiresAt:    expiresAt,
+		UserID:       session.User.ID.String(),
+		Email:        session.User.Email,
+	}
+}
+
+func claimInt64(claims jwt.MapClaims, key string) int64 {
+	switch v := claims[key].(type) {
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	}
+	return 0
+}
+
+var regexGoTrueStatus = regexp.MustCompile(`^response status code (\d+)(?:: (.*))?$`)
+
+// parseAuthError convierte el error de texto de gotrue-go ("response status code
+// 400: {json}") en un AuthError con el código del servidor.
+func parseAuthError(err error) error {
+	m := regexGoTrueStatus.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	status, _ := strconv.Atoi(m[1])
+	authErr := &AuthError{Status: status, Message: m[2]}
+	var body struct {
+		ErrorCode        string `json:"error_code"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		Msg              string `json:"msg"`
+		Message          string `json:"message"`
+	}
+	if json.Unmarshal([]byte(m[2]), &body) == nil {
+		authErr.Code = body.ErrorCode
+		if authErr.Code == "" {
+			authErr.Code = body.Error
+		}
+		for _, msg := range []string{body.Msg, body.ErrorDescription, body.Message} {
+			if msg != "" {
+				authErr.Message = msg
+				break
+			}
+		}
+	}
+	return authErr
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newTestJWKS sirve un JWKS con una clave RSA y devuelve el verificador y la clave privada
+func newTestJWKS(t *testing.T) (*JWKSVerifier, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := map[string][]jwk{"keys": {{
+		Kid: "k1",
+		Kty: "RSA",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(doc)
+	}))
+	t.Cleanup(server.Close)
+	return NewJWKSVerifier(server.URL+"/auth/v1", server.Client()), key
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWKSVerifier(t *testing.T) {
+	v, key := newTestJWKS(t)
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "user-1",
+			"email": "ahri@example.com",
+			"iss":   v.issuer,
+			"aud":   "authenticated",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	claims, err := v.Verify(signTestToken(t, jwt.SigningMethodRS256, key, valid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub, _ := claims.GetSubject(); sub != "user-1" {
+		t.Errorf("sub = %q", sub)
+	}
+
+	tests := []struct {
+		name   string
+		change func(jwt.MapClaims)
+	}{
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{"no expiry", func(c jwt.MapClaims) { delete(c, "exp") }},
+		{"other issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com/auth/v1" }},
+		{"other audience", func(c jwt.MapClaims) { c["aud"] = "anon" }},
+		{"no subject", func(c jwt.MapClaims) { delete(c, "sub") }},
+	}
+	for _, tt := range tests {
+		claims := valid()
+		tt.change(claims)
+		if _, err := v.Verify(signTestToken(t, jwt.SigningMethodRS256, key, claims)); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := v.Verify(signTestToken(t, jwt.SigningMethodRS256, other, valid())); err == nil {
+		t.Error("token signed with another key: expected an error")
+	}
+	if _, err := v.Verify(signTestToken(t, jwt.SigningMethodHS256, []byte("secret"), valid())); !errors.Is(err, errUnverifiableToken) {
+		t.Errorf("HS256 token: got %v, want errUnverifiableToken", err)
+	}
+}
+
+func TestSignInWithLogin(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/functions/v1/"+SignInWithLoginFunction || r.Header.Get("apikey") != "anon" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		if got["password"] != "hunter22" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_code":"invalid_credentials","error_description":"Invalid login credentials"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"at","refresh_token":"rt","expires_in":3600,"user":{"id":"6f1c7d1e-0000-4000-8000-000000000001","email":"ahri@example.com"}}`))
+	}))
+	defer server.Close()
+	s := NewAuthService(server.URL, "anon", server.Client())
+
+	session, err := s.SignInWithLogin("ahri", "hunter22")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["login"] != "ahri" || session.AccessToken != "at" || session.RefreshToken != "rt" || session.ExpiresAt == 0 {
+		t.Errorf("got request %v, session %+v", got, session)
+	}
+
+	_, err = s.SignInWithLogin("ahri", "wrong")
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || authErr.Status != http.StatusBadRequest || authErr.Code != "invalid_credentials" {
+		t.Errorf("wrong password: got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+// Backend agrupa el acceso a datos remotos de la app. Los métodos enlazados lo
+// usan en vez de crear clientes de Supabase por su cuenta, así se puede cambiar
+// por MemoryBackend (o cualquier otra implementación) sin tocar la red.
+type Backend interface {
+	Auth() AuthProvider
+	Users() UserStore
+	Entitlements() EntitlementService
+	Tokens() TokenAccounting
+	Storage() ObjectStore
+	Catalog() CatalogStore
+}
+
+// UserProfile es la fila de public.users que la app puede leer
+type UserProfile struct {
+	ID            string `json:"id"`
+	Login         string `json:"login"`
+	FichasPorSkin int    `json:"fichasporskin"`
+	EsComprador   bool   `json:"escomprador"`
+	Email         string `json:"email,omitempty"` // Viene de Supabase Auth, no de la fila
+}
+
+// UserStore lee y cambia perfiles. accessToken es el del usuario: las políticas
+// del servidor solo le dejan ver y tocar su propia fila.
+type UserStore interface {
+	FindByID(accessToken, userId string) (*UserProfile, error)
+	UpdateLogin(accessToken, userId, login string) error
+	// LoginTaken indica si login pertenece a una cuenta distinta de userId ("" sin
+	// sesión). El email de esa cuenta nunca sale del servidor.
+	LoginTaken(accessToken, userId, login string) (bool, error)
+}
+
+// ObjectStore descarga archivos públicos por bucket y ruta
+type ObjectStore interface {
+	Download(bucket, objectPath string) ([]byte, error)
+}
+
+// CatalogStore da acceso a los datos del catálogo de campeones
+type CatalogStore interface {
+	ChampionJSON(championId string) ([]byte, error)
+}
+
+// Buckets del catálogo
+const (
+	ChampionJSONBucket = "api_json"
+)
@@ -0,0 +1,492 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/gotrue-go/types"
+)
+
+// MemoryBackend implementa Backend en memoria, para pruebas y para usar la app sin red.
+// Repite las reglas del servidor (contraseñas, fichas, acceso a skins) con datos
+// locales; los tokens son opacos y solo valen en este backend.
+type MemoryBackend struct {
+	mu           sync.Mutex
+	users        map[string]*memoryUser        // por ID
+	sessions     map[string]string             // access token -> ID del usuario
+	refresh      map[string]string             // refresh token -> ID del usuario
+	reservations map[string]*memoryReservation // por ID
+	objects      map[string][]byte             // "bucket/ruta" -> contenido
+	outbox       []MemoryMail                  // Correos enviados, en orden
+	auth         AuthProvider                  // nil: auth en memoria
+}
+
+// MemoryMail es un correo con código que MemoryBackend habría enviado. Hace de
+// bandeja de salida, como Inbucket con un proyecto local de Supabase.
+type MemoryMail struct {
+	To   string
+	Kind types.VerificationType // signup o recovery
+	Code string
+	used bool
+}
+
+// memoryUser es una cuenta de MemoryBackend: el perfil más lo que guarda el servidor de auth
+type memoryUser struct {
+	profile        UserProfile
+	email          string
+	password       string
+	emailConfirmed bool
+}
+
+// memoryReservation es una reserva de ficha de MemoryBackend
+type memoryReservation struct {
+	id, userId, key  string
+	championId, skin string
+	status           string // reserved, committed o refunded
+}
+
+// memorySessionTTL es la vida de los access tokens de MemoryBackend
+const memorySessionTTL = time.Hour
+
+// NewMemoryBackend crea un backend vacío
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		users:        map[string]*memoryUser{},
+		sessions:     map[string]string{},
+		refresh:      map[string]string{},
+		reservations: map[string]*memoryReservation{},
+		objects:      map[string][]byte{},
+	}
+}
+
+// WithAuth usa auth en vez del auth en memoria, p. ej. un AuthService contra un servidor de prueba
+func (m *MemoryBackend) WithAuth(auth AuthProvider) *MemoryBackend {
+	m.auth = auth
+	return m
+}
+
+// AddUser agrega o reemplaza una cuenta con el email ya confirmado
+func (m *MemoryBackend) AddUser(profile UserProfile, email, password string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[profile.ID] = &memoryUser{profile: profile, email: email, password: password, emailConfirmed: true}
+}
+
+// SetEmailConfirmed marca el email de userId como confirmado o pendiente
+func (m *MemoryBackend) SetEmailConfirmed(userId string, confirmed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if user, ok := m.users[userId]; ok {
+		user.emailConfirmed = confirmed
+	}
+}
+
+// Profile devuelve una copia del perfil de userId
+func (m *MemoryBackend) Profile(userId string) (UserProfile, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[userId]
+	if !ok {
+		return UserProfile{}, false
+	}
+	return user.profile, true
+}
+
+// Outbox devuelve los correos enviados, del más viejo al más nuevo
+func (m *MemoryBackend) Outbox() []MemoryMail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MemoryMail(nil), m.outbox...)
+}
+
+// sendCode anota un correo con un código nuevo para el usuario con email, si
+// existe. Como GoTrue, invalida los códigos anteriores del mismo tipo. Debe
+// llamarse con m.mu tomado.
+func (m *MemoryBackend) sendCode(kind types.VerificationType, email string) {
+	user := m.userByEmail(email)
+	if user == nil {
+		return
+	}
+	for i := range m.outbox {
+		if m.outbox[i].Kind == kind && strings.EqualFold(m.outbox[i].To, user.email) {
+			m.outbox[i].used = true
+		}
+	}
+	n, _ := rand.Int(rand.Reader, big.NewInt(1000000))
+	m.outbox = append(m.outbox, MemoryMail{To: user.email, Kind: kind, Code: fmt.Sprintf("%06d", n.Int64())})
+}
+
+// userByEmail busca una cuenta por email. Debe llamarse con m.mu tomado.
+func (m *MemoryBackend) userByEmail(email string) *memoryUser {
+	for _, user := range m.users {
+		if strings.EqualFold(user.email, email) {
+			return user
+		}
+	}
+	return nil
+}
+
+// PutObject guarda un objeto en bucket/objectPath
+func (m *MemoryBackend) PutObject(bucket, objectPath string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[bucket+"/"+objectPath] = append([]byte(nil), data...)
+}
+
+func (m *MemoryBackend) Auth() AuthProvider {
+	if m.auth != nil {
+		return m.auth
+	}
+	return memoryAuth{m}
+}
+
+func (m *MemoryBackend) Users() UserStore                 { return memoryUsers{m} }
+func (m *MemoryBackend) Entitlements() EntitlementService { return memoryEntitlements{m} }
+func (m *MemoryBackend) Tokens() TokenAccounting          { return memoryTokens{m} }
+func (m *MemoryBackend) Storage() ObjectStore             { return memoryStorage{m} }
+func (m *MemoryBackend) Catalog() CatalogStore {
+	return objectCatalog{storage: m.Storage(), bucket: ChampionJSONBucket}
+}
+
+// userByToken devuelve el usuario dueño de accessToken. Debe llamarse con m.mu tomado.
+func (m *MemoryBackend) userByToken(accessToken string) (*memoryUser, error) {
+	if user, ok := m.users[m.sessions[accessToken]]; ok {
+		return user, nil
+	}
+	return nil, &AuthError{Status: 401, Code: "bad_jwt", Message: "invalid token"}
+}
+
+// randomToken genera un token opaco con prefix
+func randomToken(prefix string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+type memoryAuth struct{ m *MemoryBackend }
+
+// newSession abre una sesión para user. Debe llamarse con m.mu tomado.
+func (a memoryAuth) newSession(user *memoryUser) *AuthSession {
+	session := &AuthSession{
+		AccessToken:  randomToken("mem-at-"),
+		RefreshToken: randomToken("mem-rt-"),
+		ExpiresAt:    time.Now().Add(memorySessionTTL).Unix(),
+		UserID:       user.profile.ID,
+		Email:        user.email,
+	}
+	a.m.sessions[session.AccessToken] = user.profile.ID
+	a.m.refresh[session.RefreshToken] = user.profile.ID
+	return session
+}
+
+// signIn valida la contraseña del primer usuario que cumple match. Sin coincidencia
+// responde como GoTrue, igual exista o no la cuenta.
+func (a memoryAuth) signIn(password string, match func(*memoryUser) bool) (*AuthSession, error) {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	for _, user := range a.m.users {
+		if match(user) && user.password != "" && user.password == password {
+			return a.newSession(user), nil
+		}
+	}
+	return nil, &AuthError{Status: 400, Code: "invalid_credentials", Message: "Invalid login credentials"}
+}
+
+func (a memoryAuth) SignIn(email, password string) (*AuthSession, error) {
+	return a.signIn(password, func(u *memoryUser) bool { return strings.EqualFold(u.email, email) })
+}
+
+func (a memoryAuth) SignInWithLogin(login, password string) (*AuthSession, error) {
+	return a.signIn(password, func(u *memoryUser) bool { return strings.EqualFold(u.profile.Login, login) })
+}
+
+// SignUp crea la cuenta ya confirmada y abre su sesión
+func (a memoryAuth) SignUp(email, password string, data map[string]interface{}) (string, *AuthSession, error) {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	if a.m.userByEmail(email) != nil {
+		return "", nil, &AuthError{Status: 422, Code: "user_already_exists", Message: "User already registered"}
+	}
+	login, _ := data["login"].(string)
+	user := &memoryUser{
+		profile:        UserProfile{ID: uuid.NewString(), Login: login},
+		email:          email,
+		password:       password,
+		emailConfirmed: true,
+	}
+	a.m.users[user.profile.ID] = user
+	session := a.newSession(user)
+	return user.profile.ID, session, nil
+}
+
+func (a memoryAuth) Refresh(refreshToken string) (*AuthSession, error) {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	user, ok := a.m.users[a.m.refresh[refreshToken]]
+	if !ok {
+		return nil, &AuthError{Status: 400, Code: "refresh_token_not_found", Message: "Invalid Refresh Token"}
+	}
+	delete(a.m.refresh, refreshToken)
+	return a.newSession(user), nil
+}
+
+// SignOut cierra todas las sesiones del dueño de accessToken
+func (a memoryAuth) SignOut(accessToken string) error {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	userId, ok := a.m.sessions[accessToken]
+	if !ok {
+		return nil
+	}
+	for _, tokens := range []map[string]string{a.m.sessions, a.m.refresh} {
+		for token, id := range tokens {
+			if id == userId {
+				delete(tokens, token)
+			}
+		}
+	}
+	return nil
+}
+
+func (a memoryAuth) RevokeSession(accessToken string) error {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	delete(a.m.sessions, accessToken)
+	return nil
+}
+
+// UpdateUser aplica el cambio al instante: no hay correo que confirmar
+func (a memoryAuth) UpdateUser(accessToken string, req types.UpdateUserRequest) (*types.User, error) {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	user, err := a.m.userByToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if req.Password != nil {
+		if *req.Password == user.password {
+			return nil, &AuthError{Status: 422, Code: "same_password", Message: "New password should be different from the old password."}
+		}
+		user.password = *req.Password
+	}
+	if req.Email != "" {
+		user.email = req.Email
+	}
+	id, _ := uuid.Parse(user.profile.ID)
+	return &types.User{ID: id, Email: user.email}, nil
+}
+
+func (a memoryAuth) HealthCheck() error { return nil }
+
+// Recover deja el código en Outbox; sin cuenta no envía nada y responde igual
+func (a memoryAuth) Recover(email string) error {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	a.m.sendCode(types.VerificationTypeRecovery, email)
+	return nil
+}
+
+// Resend deja un código de registro nuevo en Outbox si el email falta confirmar
+func (a memoryAuth) Resend(kind types.VerificationType, email string) error {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	if user := a.m.userByEmail(email); user != nil && !user.emailConfirmed {
+		a.m.sendCode(kind, email)
+	}
+	return nil
+}
+
+// VerifyOTP canjea el último código enviado a email, una sola vez. Confirma el
+// email (también al restablecer la contraseña, como GoTrue) y abre una sesión.
+func (a memoryAuth) VerifyOTP(kind types.VerificationType, email, code string) (*AuthSession, error) {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	for i := range a.m.outbox {
+		mail := &a.m.outbox[i]
+		if mail.used || mail.Kind != kind || mail.Code != code || !strings.EqualFold(mail.To, email) {
+			continue
+		}
+		user := a.m.userByEmail(email)
+		if user == nil {
+			break
+		}
+		mail.used = true
+		user.emailConfirmed = true
+		return a.newSession(user), nil
+	}
+	return nil, &AuthError{Status: 403, Code: "otp_expired", Message: "Token has expired or is invalid"}
+}
+
+func (a memoryAuth) IsEmailVerified(accessToken string) (bool, error) {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	user, err := a.m.userByToken(accessToken)
+	if err != nil {
+		return false, err
+	}
+	return user.emailConfirmed, nil
+}
+
+func (a memoryAuth) VerifyAccessToken(accessToken string) (*AuthClaims, error) {
+	a.m.mu.Lock()
+	defer a.m.mu.Unlock()
+	user, err := a.m.userByToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthClaims{UserID: user.profile.ID, Email: user.email, ExpiresAt: time.Now().Add(memorySessionTTL).Unix()}, nil
+}
+
+type memoryUsers struct{ m *MemoryBackend }
+
+func (u memoryUsers) FindByID(accessToken, userId string) (*UserProfile, error) {
+	u.m.mu.Lock()
+	defer u.m.mu.Unlock()
+	user, ok := u.m.users[userId]
+	if !ok {
+		return nil, fmt.Errorf("user %s not found", userId)
+	}
+	profile := user.profile
+	return &profile, nil
+}
+
+func (u memoryUsers) UpdateLogin(accessToken, userId, login string) error {
+	u.m.mu.Lock()
+	defer u.m.mu.Unlock()
+	user, ok := u.m.users[userId]
+	if !ok {
+		return fmt.Errorf("user %s not found", userId)
+	}
+	user.profile.Login = login
+	return nil
+}
+
+func (u memoryUsers) LoginTaken(accessToken, userId, login string) (bool, error) {
+	u.m.mu.Lock()
+	defer u.m.mu.Unlock()
+	login = strings.TrimSpace(login)
+	for id, user := range u.m.users {
+		if id != userId && strings.EqualFold(user.profile.Login, login) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memoryEntitlements repite authorize_skin_download: email confirmado, cuenta
+// compradora y una ficha reservada o gastada en esa skin, que queda confirmada.
+// No firma URLs; el archivo se descarga de Storage().
+type memoryEntitlements struct{ m *MemoryBackend }
+
+func (e memoryEntitlements) AuthorizeSkinDownload(accessToken, championId, skinNum string) (*SkinGrant, error) {
+	e.m.mu.Lock()
+	defer e.m.mu.Unlock()
+	user, err := e.m.userByToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !user.emailConfirmed:
+		return nil, &EntitlementDeniedError{Reason: DenialEmailUnverified}
+	case !user.profile.EsComprador:
+		return nil, &EntitlementDeniedError{Reason: DenialNotPurchased}
+	}
+	for _, r := range e.m.reservations {
+		if r.userId == user.profile.ID && r.championId == championId && r.skin == skinNum && r.status != LedgerRefunded {
+			r.status = LedgerCommitted
+			return &SkinGrant{}, nil
+		}
+	}
+	return nil, &EntitlementDeniedError{Reason: DenialOutOfTokens}
+}
+
+// memoryTokens repite reserve/commit/refund_skin_token sobre FichasPorSkin
+type memoryTokens struct{ m *MemoryBackend }
+
+func (t memoryTokens) Reserve(accessToken, idempotencyKey, championId, skinNum string) (*TokenReservation, error) {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	user, err := t.m.userByToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	// La misma clave, o una skin ya pagada, devuelve la reserva existente sin cobrar
+	for _, r := range t.m.reservations {
+		if r.userId != user.profile.ID {
+			continue
+		}
+		if (r.key == idempotencyKey && r.status != LedgerRefunded) ||
+			(r.championId == championId && r.skin == skinNum && r.status == LedgerCommitted) {
+			return &TokenReservation{ID: r.id, Remaining: user.profile.FichasPorSkin}, nil
+		}
+	}
+	if user.profile.FichasPorSkin <= 0 {
+		return nil, &EntitlementDeniedError{Reason: DenialOutOfTokens}
+	}
+	user.profile.FichasPorSkin--
+	r := &memoryReservation{id: uuid.NewString(), userId: user.profile.ID, key: idempotencyKey, championId: championId, skin: skinNum, status: LedgerReserved}
+	t.m.reservations[r.id] = r
+	return &TokenReservation{ID: r.id, Remaining: user.profile.FichasPorSkin}, nil
+}
+
+func (t memoryTokens) Commit(accessToken, reservationId string) error {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	r, err := t.reservation(accessToken, reservationId)
+	if err != nil {
+		return err
+	}
+	if r.status == LedgerRefunded {
+		return fmt.Errorf("reservation %s was refunded", reservationId)
+	}
+	r.status = LedgerCommitted
+	return nil
+}
+
+func (t memoryTokens) Refund(accessToken, reservationId string) error {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	r, err := t.reservation(accessToken, reservationId)
+	if err != nil {
+		return err
+	}
+	if r.status == LedgerCommitted {
+		return ErrReservationConsumed
+	}
+	if r.status == LedgerReserved {
+		r.status = LedgerRefunded
+		t.m.users[r.userId].profile.FichasPorSkin++
+	}
+	return nil
+}
+
+// reservation busca una reserva del dueño de accessToken. Debe llamarse con m.mu tomado.
+func (t memoryTokens) reservation(accessToken, reservationId string) (*memoryReservation, error) {
+	user, err := t.m.userByToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := t.m.reservations[reservationId]
+	if !ok || r.userId != user.profile.ID {
+		return nil, fmt.Errorf("reservation %s not found", reservationId)
+	}
+	return r, nil
+}
+
+type memoryStorage struct{ m *MemoryBackend }
+
+func (s memoryStorage) Download(bucket, objectPath string) ([]byte, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	data, ok := s.m.objects[bucket+"/"+objectPath]
+	if !ok {
+		return nil, fmt.Errorf("error downloading file: %s/%s not found", bucket, objectPath)
+	}
+	return append([]byte(nil), data...), nil
+}
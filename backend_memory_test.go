@@ -0,0 +1,213 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPassword = "blue-sky-42"
+
+// newMemoryTestApp crea una App sin red: auth, fichas, acceso y archivos salen de un
+// MemoryBackend con una cuenta compradora de tokens fichas y la skin 103/1 publicada
+func newMemoryTestApp(t *testing.T, tokens int) (*App, *MemoryBackend) {
+	t.Helper()
+	useTempPaths(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // Clave de cifrado de la sesión
+	a := newTestApp(t)
+	backend := NewMemoryBackend()
+	backend.AddUser(UserProfile{ID: testUserID, Login: "ahri", FichasPorSkin: tokens, EsComprador: true}, "ahri@example.com", testPassword)
+	backend.PutObject(a.currentSettings().Backend.SkinsBucket, catalogSkinPath("103", "1"), []byte("dynasty ahri"))
+	a.backend = backend
+	a.ledger = newTestLedger(t)
+	a.loginThrottle, _ = NewLoginThrottle(filepath.Join(t.TempDir(), "throttle.json"))
+	a.session = NewSessionManager(backend.Auth(), filepath.Join(t.TempDir(), SessionFileName), func(string, interface{}) {})
+	t.Cleanup(func() { a.session.Logout() })
+	return a, backend
+}
+
+// login inicia sesión en a o falla el test
+func login(t *testing.T, a *App) {
+	t.Helper()
+	if result := a.Login("ahri", testPassword); !result.Success {
+		t.Fatalf("Login: %+v", result)
+	}
+}
+
+func downloadDynastyAhri(a *App) DownloadResult {
+	return a.DownloadSkin("103", "1", "", "", "Dynasty Ahri", "dynasty-ahri.fantome", "", "", "Dynasty Ahri")
+}
+
+func TestLoginWithMemoryBackend(t *testing.T) {
+	a, _ := newMemoryTestApp(t, 0)
+
+	for _, identifier := range []string{"ahri", "AHRI", "ahri@example.com"} {
+		result := a.Login(identifier, testPassword)
+		if !result.Success || result.Token == "" || result.User == nil || result.User.Email != "ahri@example.com" {
+			t.Errorf("Login(%q) = %+v", identifier, result)
+		}
+	}
+
+	// Contraseña incorrecta y usuario inexistente responden igual
+	for _, identifier := range []string{"ahri", "jinx"} {
+		if result := a.Login(identifier, "wrong-password-1"); result.Code != ErrAuthInvalidCredentials {
+			t.Errorf("Login(%q, wrong) code = %q", identifier, result.Code)
+		}
+	}
+
+	// Sin token, los bindings usan la sesión guardada
+	if result := a.GetUserData(""); !result.Success || result.User.Login != "ahri" {
+		t.Errorf("GetUserData with the saved session = %+v", result)
+	}
+	a.Logout()
+	if result := a.GetUserData(""); result.Code != ErrAuthInvalidToken {
+		t.Errorf("GetUserData after logout = %+v", result)
+	}
+}
+
+// Una descarga gasta una ficha; volver a descargar la misma skin no gasta otra
+func TestDownloadSkinSpendsOneToken(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 2)
+	login(t, a)
+
+	result := downloadDynastyAhri(a)
+	if !result.Success || result.Remaining != 1 || result.AlreadyOwned {
+		t.Fatalf("first download = %+v", result)
+	}
+	if content, _ := os.ReadFile(filepath.Join(absInstalledPath, "dynasty-ahri.fantome")); string(content) != "dynasty ahri" {
+		t.Errorf("installed file = %q", content)
+	}
+	if owned := a.ledger.Owned(testUserID, "103", "1"); owned == nil || owned.Status != LedgerCommitted {
+		t.Errorf("ledger entry = %+v", owned)
+	}
+
+	result = downloadDynastyAhri(a)
+	if !result.Success || !result.AlreadyOwned {
+		t.Fatalf("second download = %+v", result)
+	}
+	if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != 1 {
+		t.Errorf("tokens left = %d, want 1", profile.FichasPorSkin)
+	}
+}
+
+func TestDownloadSkinDenied(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens int
+		setup  func(*MemoryBackend)
+		reason DenialReason
+	}{
+		{"out of tokens", 0, nil, DenialOutOfTokens},
+		{"not purchased", 1, func(b *MemoryBackend) {
+			b.AddUser(UserProfile{ID: testUserID, Login: "ahri", FichasPorSkin: 1}, "ahri@example.com", testPassword)
+		}, DenialNotPurchased},
+		{"email unverified", 1, func(b *MemoryBackend) { b.SetEmailConfirmed(testUserID, false) }, DenialEmailUnverified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, backend := newMemoryTestApp(t, tt.tokens)
+			if tt.setup != nil {
+				tt.setup(backend)
+			}
+			login(t, a)
+
+			result := downloadDynastyAhri(a)
+			if result.Success || result.Reason != tt.reason {
+				t.Fatalf("download = %+v, want reason %q", result, tt.reason)
+			}
+			// Una reserva rechazada por el servidor devuelve la ficha
+			if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != tt.tokens {
+				t.Errorf("tokens left = %d, want %d", profile.FichasPorSkin, tt.tokens)
+			}
+			if _, err := os.Stat(filepath.Join(absInstalledPath, "dynasty-ahri.fantome")); !os.IsNotExist(err) {
+				t.Errorf("skin file was written: %v", err)
+			}
+		})
+	}
+}
+
+// Autorizar la descarga gasta la ficha: si después el archivo falla no se
+// devuelve, y reintentar la misma skin no cobra otra
+func TestDownloadSkinFailureAfterAuthorizationKeepsToken(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 2)
+	login(t, a)
+
+	midnightAhri := func() DownloadResult {
+		return a.DownloadSkin("103", "2", "", "", "Midnight Ahri", "midnight-ahri.fantome", "", "", "Midnight Ahri")
+	}
+	if result := midnightAhri(); result.Success || result.Code != ErrDownloadFailed {
+		t.Fatalf("download = %+v", result)
+	}
+	if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != 1 {
+		t.Errorf("tokens left = %d, want 1", profile.FichasPorSkin)
+	}
+	if owned := a.ledger.Owned(testUserID, "103", "2"); owned == nil {
+		t.Errorf("ledger = %+v", a.ledger.History(testUserID))
+	}
+
+	backend.PutObject(a.currentSettings().Backend.SkinsBucket, catalogSkinPath("103", "2"), []byte("midnight ahri"))
+	if result := midnightAhri(); !result.Success || !result.AlreadyOwned {
+		t.Fatalf("retry = %+v", result)
+	}
+	if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != 1 {
+		t.Errorf("tokens left after retry = %d, want 1", profile.FichasPorSkin)
+	}
+}
+
+// Un cliente modificado no puede reservar, autorizar y después devolver la ficha
+func TestRefundRejectedAfterAuthorization(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 2)
+	login(t, a)
+	token, _ := a.session.AccessToken()
+
+	reservation, err := backend.Tokens().Reserve(token, "key-1", "103", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backend.Entitlements().AuthorizeSkinDownload(token, "103", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Tokens().Refund(token, reservation.ID); err != ErrReservationConsumed {
+		t.Errorf("refund after authorization = %v, want ErrReservationConsumed", err)
+	}
+
+	// Una reserva que nunca autorizó una descarga sí se devuelve
+	pending, err := backend.Tokens().Reserve(token, "key-2", "103", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Tokens().Refund(token, pending.ID); err != nil {
+		t.Errorf("refund before authorization = %v", err)
+	}
+	if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != 1 {
+		t.Errorf("tokens left = %d, want 1", profile.FichasPorSkin)
+	}
+}
+
+// Al iniciar, una reserva pendiente que el servidor ya autorizó queda gastada en el libro
+func TestSettleTokenLedgerKeepsConsumedReservations(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 1)
+	login(t, a)
+	token, _ := a.session.AccessToken()
+
+	reservation, err := backend.Tokens().Reserve(token, "key-1", "103", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := TokenLedgerEntry{IdempotencyKey: "key-1", ReservationId: reservation.ID, UserId: testUserID,
+		ChampionId: "103", SkinId: "1", FileName: "dynasty-ahri.fantome", Status: LedgerReserved}
+	if err := a.ledger.Record(entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backend.Entitlements().AuthorizeSkinDownload(token, "103", "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	a.settleTokenLedger()
+	if got := a.ledger.Status("key-1"); got != LedgerCommitted {
+		t.Errorf("ledger status = %q, want committed", got)
+	}
+	if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != 0 {
+		t.Errorf("tokens left = %d, want 0", profile.FichasPorSkin)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+)
+
+// userProfileColumns son las columnas de public.users que la app puede leer
+const userProfileColumns = "id,login,fichasporskin,escomprador"
+
+// SupabaseBackend implementa Backend con PostgREST y Storage de un proyecto de Supabase
+type SupabaseBackend struct {
+	projectURL string
+	apiKey     string
+	httpClient *http.Client
+
+	auth         *AuthService
+	entitlements *SupabaseEntitlements
+	tokens       *SupabaseTokenAccounting
+
+	storage            ObjectStore // nil: Supabase Storage del mismo proyecto
+	championJSONBucket string
+}
+
+// NewSupabaseBackend crea el backend para el proyecto en projectURL
+func NewSupabaseBackend(projectURL, apiKey string, httpClient *http.Client) *SupabaseBackend {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &SupabaseBackend{
+		projectURL:         strings.TrimRight(projectURL, "/"),
+		apiKey:             apiKey,
+		httpClient:         httpClient,
+		auth:               NewAuthService(projectURL, apiKey, httpClient),
+		entitlements:       NewSupabaseEntitlements(projectURL, apiKey, httpClient),
+		tokens:             NewSupabaseTokenAccounting(projectURL, apiKey, httpClient),
+		championJSONBucket: ChampionJSONBucket,
+	}
+}
+
+// WithStorage usa store para los archivos del catálogo en vez de Supabase Storage.
+// El servidor sigue decidiendo el acceso a las skins, pero ya no firma URLs: los
+// .fantome se descargan de store.
+func (b *SupabaseBackend) WithStorage(store ObjectStore, championJSONBucket string) *SupabaseBackend {
+	b.storage = store
+	b.championJSONBucket = championJSONBucket
+	b.entitlements.WithStorage(b.entitlements.bucket, false)
+	return b
+}
+
+// WithSkinsBucket cambia el bucket con los .fantome del catálogo
+func (b *SupabaseBackend) WithSkinsBucket(bucket string) *SupabaseBackend {
+	b.entitlements.WithStorage(bucket, b.entitlements.signURLs)
+	return b
+}
+
+func (b *SupabaseBackend) Auth() AuthProvider               { return b.auth }
+func (b *SupabaseBackend) Users() UserStore                 { return supabaseUsers{b} }
+func (b *SupabaseBackend) Entitlements() EntitlementService { return b.entitlements }
+func (b *SupabaseBackend) Tokens() TokenAccounting          { return b.tokens }
+
+func (b *SupabaseBackend) Storage() ObjectStore {
+	if b.storage != nil {
+		return b.storage
+	}
+	return supabaseStorage{b}
+}
+
+func (b *SupabaseBackend) Catalog() CatalogStore {
+	return objectCatalog{storage: b.Storage(), bucket: b.championJSONBucket}
+}
+
+// userClient crea un cliente de Supabase que actúa con el token del usuario,
+// para que las políticas RLS se apliquen a sus lecturas y escrituras
+func (b *SupabaseBackend) userClient(accessToken string) (*supabase.Client, error) {
+	return supabase.NewClient(b.projectURL, b.apiKey, &supabase.ClientOptions{
+		Headers: map[string]string{"Authorization": "Bearer " + accessToken},
+	})
+}
+
+type supabaseUsers struct{ b *SupabaseBackend }
+
+// FindByID lee el perfil con el token del usuario, para que RLS solo deje ver su propia fila
+func (u supabaseUsers) FindByID(accessToken, userId string) (*UserProfile, error) {
+	client, err := u.b.userClient(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := client.From("users").Select(userProfileColumns, "", false).Eq("id", userId).Single().Execute()
+	if err != nil {
+		return nil, err
+	}
+	var user UserProfile
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateLogin cambia el login filtrando por userId; RLS impide tocar otras filas
+func (u supabaseUsers) UpdateLogin(accessToken, userId, login string) error {
+	client, err := u.b.userClient(accessToken)
+	if err != nil {
+		return err
+	}
+	_, _, err = client.From("users").Update(map[string]interface{}{"login": login}, "minimal", "").Eq("id", userId).Execute()
+	return err
+}
+
+// LoginTaken usa la función login_taken de la base, que es SECURITY DEFINER y
+// solo responde si el login existe. El servidor toma userId del token.
+func (u supabaseUsers) LoginTaken(accessToken, userId, login string) (bool, error) {
+	var taken bool
+	if err := callRPC(u.b.httpClient, u.b.projectURL, u.b.apiKey, accessToken, "login_taken", map[string]string{"p_login": login}, &taken); err != nil {
+		return false, err
+	}
+	return taken, nil
+}
+
+type supabaseStorage struct{ b *SupabaseBackend }
+
+// Download descarga un objeto de un bucket público de Supabase Storage
+func (s supabaseStorage) Download(bucket, objectPath string) ([]byte, error) {
+	downloadURL := fmt.Sprintf("%s/storage/v1/object/public/%s/%s", s.b.projectURL, escapeObjectPath(bucket), escapeObjectPath(objectPath))
+	req, err := http.NewRequest("GET", downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("apikey", s.b.apiKey)
+	req.Header.Set("Authorization", "Bearer "+s.b.apiKey)
+
+	resp, err := s.b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading file: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading file: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// objectCatalog lee el catálogo desde los buckets de un ObjectStore
+type objectCatalog struct {
+	storage ObjectStore
+	bucket  string // Bucket con <campeón>.json
+}
+
+func (c objectCatalog) ChampionJSON(championId string) ([]byte, error) {
+	return c.storage.Download(c.bucket, championId+".json")
+}
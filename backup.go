@@ -0,0 +1,527 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Formato de los snapshots de LoLModInstaller
+const (
+	BackupFormatVersion = 1
+	BackupManifestName  = "manifest.json"
+	BackupFilePrefix    = "skinhunter-backup-"
+	BackupFileExtension = ".zip"
+	backupTimeLayout    = "20060102-150405"
+)
+
+// BackupFile es un archivo dentro de un snapshot, con su checksum
+type BackupFile struct {
+	Path   string `json:"path"` // Relativa a LoLModInstaller, con "/"
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupManifest es el manifest.json de un snapshot
+type BackupManifest struct {
+	FormatVersion int          `json:"formatVersion"`
+	CreatedAt     string       `json:"createdAt"`
+	Note          string       `json:"note"`
+	Automatic     bool         `json:"automatic,omitempty"` // Lo hizo la app antes de restaurar otro
+	Files         []BackupFile `json:"files"`
+}
+
+// BackupInfo resume un snapshot para listarlo en el frontend
+type BackupInfo struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	Note      string `json:"note"`
+	Automatic bool   `json:"automatic,omitempty"`
+	FileCount int    `json:"fileCount"`
+	Size      int64  `json:"size"` // Tamaño comprimido en disco
+}
+
+// BackupResult es la respuesta de CreateBackup y RestoreBackup
+type BackupResult struct {
+	Result
+	Backup         *BackupInfo `json:"backup,omitempty"`
+	PreviousDataAt string      `json:"previousDataAt,omitempty"` // Carpeta con los datos anteriores a la restauración
+}
+
+// CreateBackup guarda un snapshot comprimido de todo LoLModInstaller (mods
+// instalados, perfiles, mod-status.json, settings) y aplica la retención configurada.
+// El libro de fichas y el contador de intentos de login quedan fuera (ver keptOnRestore).
+func (a *App) CreateBackup(note string) BackupResult {
+	info, err := a.createBackup(note, false)
+	if err != nil {
+		a.logger(LogSubsystemStorage).Errorf("CreateBackup: %v", err)
+		return BackupResult{Result: failResult(ErrStorageFailed, "Could not create backup", err)}
+	}
+	a.pruneBackups()
+	return BackupResult{Result: okResult(MsgBackupCreated), Backup: info}
+}
+
+func (a *App) createBackup(note string, automatic bool) (*BackupInfo, error) {
+	if err := os.MkdirAll(absBackupsPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", absBackupsPath, err)
+	}
+
+	now := time.Now()
+	name := BackupFilePrefix + now.Format(backupTimeLayout) + BackupFileExtension
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(absBackupsPath, name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s%s-%d%s", BackupFilePrefix, now.Format(backupTimeLayout), i, BackupFileExtension)
+	}
+	destPath := filepath.Join(absBackupsPath, name)
+	a.logger(LogSubsystemStorage).Infof("Creating backup of %s at %s", absInstallerPath, destPath)
+
+	var files []string
+	err := filepath.WalkDir(absInstallerPath, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(d.Name(), ".tmp") || (filepath.Dir(p) == absInstallerPath && keptOnRestore(d.Name())) {
+			return nil
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", absInstallerPath, err)
+	}
+
+	tmp, err := os.CreateTemp(absBackupsPath, ".backup-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op tras el rename
+
+	manifest := BackupManifest{
+		FormatVersion: BackupFormatVersion,
+		CreatedAt:     now.Format(time.RFC3339),
+		Note:          note,
+		Automatic:     automatic,
+		Files:         []BackupFile{},
+	}
+	zw := zip.NewWriter(tmp)
+	for _, p := range files {
+		rel, err := filepath.Rel(absInstallerPath, p)
+		if err != nil {
+			tmp.Close()
+			return nil, err
+		}
+		entry, err := addFileToBackup(zw, filepath.ToSlash(rel), p)
+		if err != nil {
+			tmp.Close()
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+	w, err := zw.Create(BackupManifestName)
+	if err == nil {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(manifest)
+	}
+	if err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("error writing %s: %w", BackupManifestName, err)
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("error finishing backup: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("error syncing backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("error closing backup: %w", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return nil, fmt.Errorf("error moving backup to %s: %w", destPath, err)
+	}
+
+	stat, _ := os.Stat(destPath)
+	info := &BackupInfo{Name: name, CreatedAt: manifest.CreatedAt, Note: note, Automatic: automatic, FileCount: len(manifest.Files)}
+	if stat != nil {
+		info.Size = stat.Size()
+	}
+	a.logger(LogSubsystemStorage).Infof("Backup %s created with %d files.", name, info.FileCount)
+	return info, nil
+}
+
+// keptOnRestore indica si name, en la raíz de LoLModInstaller, es del libro de
+// fichas o del contador de intentos de login (o sus copias .bakN). Son historiales
+// que solo avanzan: no se guardan en los snapshots y restaurar conserva los actuales,
+// así que TokenLedger y LoginThrottle siguen coincidiendo con lo que hay en disco.
+func keptOnRestore(name string) bool {
+	for _, kept := range []string{path.Base(RelativeTokenLedger), path.Base(RelativeLoginThrottle)} {
+		if name == kept || strings.HasPrefix(name, kept+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// addFileToBackup comprime un archivo dentro del snapshot calculando su checksum al vuelo
+func addFileToBackup(zw *zip.Writer, rel, absPath string) (BackupFile, error) {
+	f, err := os.Open(absPath)
+	if err != nil {
+		return BackupFile{}, fmt.Errorf("error reading %s: %w", absPath, err)
+	}
+	defer f.Close()
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: path.Join(RelativeInstallerDir, rel), Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return BackupFile{}, fmt.Errorf("error adding %s to backup: %w", rel, err)
+	}
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, hasher), f)
+	if err != nil {
+		return BackupFile{}, fmt.Errorf("error adding %s to backup: %w", rel, err)
+	}
+	return BackupFile{Path: rel, Size: size, SHA256: hex.EncodeToString(hasher.Sum(nil))}, nil
+}
+
+// BackupListResult es la respuesta de ListBackups
+type BackupListResult struct {
+	Result
+	Backups []BackupInfo `json:"backups"`
+}
+
+// ListBackups devuelve los snapshots disponibles, del más nuevo al más viejo
+func (a *App) ListBackups() BackupListResult {
+	backups, err := a.listBackups()
+	if err != nil {
+		return BackupListResult{Result: failResult(ErrStorageFailed, "Could not list backups", err), Backups: []BackupInfo{}}
+	}
+	return BackupListResult{Result: okResult(""), Backups: backups}
+}
+
+func (a *App) listBackups() ([]BackupInfo, error) {
+	entries, err := os.ReadDir(absBackupsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []BackupInfo{}, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", absBackupsPath, err)
+	}
+	backups := []BackupInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, BackupFilePrefix) || !strings.HasSuffix(name, BackupFileExtension) {
+			continue
+		}
+		info := BackupInfo{Name: name}
+		if stat, err := entry.Info(); err == nil {
+			info.Size = stat.Size()
+		}
+		if zr, manifest, err := openBackup(filepath.Join(absBackupsPath, name)); err == nil {
+			info.CreatedAt = manifest.CreatedAt
+			info.Note = manifest.Note
+			info.Automatic = manifest.Automatic
+			info.FileCount = len(manifest.Files)
+			zr.Close()
+		} else {
+			info.Note = fmt.Sprintf("Unreadable backup: %v", err)
+		}
+		backups = append(backups, info)
+	}
+	// El nombre lleva la fecha, así que el orden alfabético es cronológico
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+	return backups, nil
+}
+
+// pruneBackups borra los snapshots más viejos que exceden settings.BackupRetention.
+// Los automáticos se cuentan aparte, para que restaurar no desplace los del usuario.
+func (a *App) pruneBackups() {
+	backups, err := a.listBackups()
+	if err != nil {
+		a.logger(LogSubsystemStorage).Warningf("Could not list backups for pruning: %v", err)
+		return
+	}
+	retention := a.currentSettings().BackupRetention
+	kept := map[bool]int{} // Automático -> snapshots conservados
+	for _, backup := range backups {
+		if kept[backup.Automatic] < retention {
+			kept[backup.Automatic]++
+			continue
+		}
+		p := filepath.Join(absBackupsPath, backup.Name)
+		if err := os.Remove(p); err != nil {
+			a.logger(LogSubsystemStorage).Warningf("Failed to prune backup %s: %v", p, err)
+		} else {
+			a.logger(LogSubsystemStorage).Infof("Pruned old backup %s", backup.Name)
+		}
+	}
+}
+
+// DeleteBackup borra un snapshot por nombre
+func (a *App) DeleteBackup(name string) Result {
+	if !isBackupName(name) {
+		return failResult(ErrInvalidArgument, "Invalid backup name", nil)
+	}
+	if err := os.Remove(filepath.Join(absBackupsPath, name)); err != nil {
+		if os.IsNotExist(err) {
+			return failResult(ErrNotFound, "Backup not found", nil)
+		}
+		return failResult(ErrStorageFailed, "Could not delete backup", err)
+	}
+	return okResult("")
+}
+
+func isBackupName(name string) bool {
+	return isPlainFileName(name) && strings.HasPrefix(name, BackupFilePrefix) && strings.HasSuffix(name, BackupFileExtension)
+}
+
+// openBackup abre un snapshot y lee su manifiesto. El llamador debe cerrar el lector.
+func openBackup(p string) (*zip.ReadCloser, *BackupManifest, error) {
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range zr.File {
+		if f.Name != BackupManifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			zr.Close()
+			return nil, nil, err
+		}
+		var manifest BackupManifest
+		err = json.NewDecoder(rc).Decode(&manifest)
+		rc.Close()
+		if err != nil {
+			zr.Close()
+			return nil, nil, fmt.Errorf("invalid %s: %w", BackupManifestName, err)
+		}
+		if manifest.FormatVersion > BackupFormatVersion {
+			zr.Close()
+			return nil, nil, fmt.Errorf("backup format version %d is newer than supported (%d)", manifest.FormatVersion, BackupFormatVersion)
+		}
+		return zr, &manifest, nil
+	}
+	zr.Close()
+	return nil, nil, fmt.Errorf("%s not found in backup", BackupManifestName)
+}
+
+// validateBackup comprueba que cada archivo del manifiesto esté en el zip con el
+// tamaño y checksum esperados, y que ninguna ruta salga de LoLModInstaller.
+func validateBackup(zr *zip.ReadCloser, manifest *BackupManifest) (map[string]*zip.File, error) {
+	byName := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		byName[f.Name] = f
+	}
+	files := make(map[string]*zip.File)
+	for _, entry := range manifest.Files {
+		clean := path.Clean(entry.Path)
+		if clean != entry.Path || strings.HasPrefix(clean, "../") || path.IsAbs(clean) || clean == ".." {
+			return nil, fmt.Errorf("invalid path in backup: %s", entry.Path)
+		}
+		f := byName[path.Join(RelativeInstallerDir, entry.Path)]
+		if f == nil {
+			return nil, fmt.Errorf("file %s listed in manifest is missing", entry.Path)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", entry.Path, err)
+		}
+		hasher := sha256.New()
+		size, err := io.Copy(hasher, rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", entry.Path, err)
+		}
+		if size != entry.Size || hex.EncodeToString(hasher.Sum(nil)) != entry.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", entry.Path)
+		}
+		files[entry.Path] = f
+	}
+	return files, nil
+}
+
+// RestoreBackup valida un snapshot y reemplaza LoLModInstaller con su contenido.
+// Antes de tocar nada se guarda un snapshot automático del estado actual, y la
+// carpeta actual se conserva como LoLModInstaller.pre-restore-* para volver atrás
+// si la restauración falla a mitad de camino.
+func (a *App) RestoreBackup(name string) BackupResult {
+	if !isBackupName(name) {
+		return BackupResult{Result: failResult(ErrInvalidArgument, "Invalid backup name", nil)}
+	}
+	backupPath := filepath.Join(absBackupsPath, name)
+	a.logger(LogSubsystemStorage).Infof("RestoreBackup: Validating %s", backupPath)
+
+	zr, manifest, err := openBackup(backupPath)
+	if err != nil {
+		return BackupResult{Result: failResult(ErrBackupInvalid, "Invalid backup", err)}
+	}
+	defer zr.Close()
+	files, err := validateBackup(zr, manifest)
+	if err != nil {
+		return BackupResult{Result: failResult(ErrBackupInvalid, "Backup failed validation", err)}
+	}
+
+	if err := a.checkOverlayNotInGame(); err != nil {
+		return BackupResult{Result: errorResult(err)}
+	}
+	a.logger(LogSubsystemStorage).Info("RestoreBackup: Stopping overlay before restore...")
+	if killed, killErr := a.killModTools(); !killed {
+		a.logger(LogSubsystemStorage).Warningf("Failed to stop overlay before restore: %v. Proceeding anyway.", killErr)
+	}
+
+	if _, err := a.createBackup("Automatic backup before restoring "+name, true); err != nil {
+		return BackupResult{Result: failResult(ErrStorageFailed, "Could not back up current state before restore", err)}
+	}
+
+	stamp := time.Now().Format(backupTimeLayout)
+	for i := 2; ; i++ {
+		_, err := os.Stat(absInstallerPath + ".pre-restore-" + stamp)
+		if os.IsNotExist(err) {
+			break
+		}
+		stamp = fmt.Sprintf("%s-%d", time.Now().Format(backupTimeLayout), i) // Dos restauraciones en el mismo segundo
+	}
+	stagingPath := absInstallerPath + ".restore-" + stamp
+	preRestorePath := absInstallerPath + ".pre-restore-" + stamp
+	// Los snapshots anteriores a keptOnRestore pueden traer el libro de fichas: se ignora
+	for rel := range files {
+		if !strings.Contains(rel, "/") && keptOnRestore(rel) {
+			delete(files, rel)
+		}
+	}
+	if err := extractBackup(files, stagingPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return BackupResult{Result: failResult(ErrStorageFailed, "Failed to extract backup", err)}
+	}
+	if err := copyKeptFiles(absInstallerPath, stagingPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return BackupResult{Result: failResult(ErrStorageFailed, "Failed to keep the token ledger", err)}
+	}
+
+	// Cambio de carpetas: dos renames, con vuelta atrás si el segundo falla
+	if err := os.Rename(absInstallerPath, preRestorePath); err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(stagingPath)
+		return BackupResult{Result: failResult(ErrStorageFailed, "Failed to move current data aside", err)}
+	}
+	if err := os.Rename(stagingPath, absInstallerPath); err != nil {
+		a.logger(LogSubsystemStorage).Errorf("RestoreBackup: Failed to move restored data into place, rolling back: %v", err)
+		if rbErr := os.Rename(preRestorePath, absInstallerPath); rbErr != nil {
+			a.logger(LogSubsystemStorage).Errorf("RestoreBackup: Rollback failed, previous data is at %s: %v", preRestorePath, rbErr)
+		}
+		os.RemoveAll(stagingPath)
+		return BackupResult{Result: failResult(ErrStorageFailed, "Failed to restore backup", err)}
+	}
+
+	// Carpetas que quizás no venían en el snapshot
+	if err := EnsureDirectoriesAbs([]string{absInstalledPath, absProfilesPath}); err != nil {
+		a.logger(LogSubsystemStorage).Warningf("RestoreBackup: %v", err)
+	}
+	a.reloadSettings()
+	if err := a.loadInstalledSkins(); err != nil {
+		a.logger(LogSubsystemStorage).Errorf("RestoreBackup: Restored data is unreadable, rolling back: %v", err)
+		a.rollbackRestore(preRestorePath)
+		return BackupResult{Result: failResult(ErrStorageFailed, "Restored installed.json is unreadable", err)}
+	}
+	a.removeOldPreRestoreDirs(preRestorePath)
+	a.reconcileAtStartup()
+	a.pruneBackups()
+
+	a.logger(LogSubsystemStorage).Infof("RestoreBackup: Restored %s (%d files). Previous data kept at %s", name, len(files), preRestorePath)
+	return BackupResult{Result: okResult(MsgBackupRestored), PreviousDataAt: preRestorePath}
+}
+
+// copyKeptFiles copia a destDir los archivos de srcDir que restaurar conserva (keptOnRestore)
+func copyKeptFiles(srcDir, destDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !keptOnRestore(entry.Name()) {
+			continue
+		}
+		if err := copyFile(filepath.Join(srcDir, entry.Name()), filepath.Join(destDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractBackup escribe los archivos validados bajo destDir
+func extractBackup(files map[string]*zip.File, destDir string) error {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+	for rel, f := range files {
+		target := filepath.Join(destDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			rc.Close()
+			return err
+		}
+		_, err = io.Copy(out, rc)
+		rc.Close()
+		if err == nil {
+			err = out.Sync()
+		}
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// rollbackRestore devuelve LoLModInstaller al estado anterior a RestoreBackup
+func (a *App) rollbackRestore(preRestorePath string) {
+	failedPath := absInstallerPath + ".failed-restore-" + time.Now().Format(backupTimeLayout)
+	if err := os.Rename(absInstallerPath, failedPath); err != nil {
+		a.logger(LogSubsystemStorage).Errorf("Rollback failed, previous data is at %s: %v", preRestorePath, err)
+		return
+	}
+	if err := os.Rename(preRestorePath, absInstallerPath); err != nil {
+		a.logger(LogSubsystemStorage).Errorf("Rollback failed, previous data is at %s: %v", preRestorePath, err)
+		return
+	}
+	os.RemoveAll(failedPath)
+	a.reloadSettings()
+	a.loadInstalledSkins()
+}
+
+// removeOldPreRestoreDirs conserva solo la carpeta pre-restore más reciente
+func (a *App) removeOldPreRestoreDirs(keep string) {
+	matches, _ := filepath.Glob(absInstallerPath + ".pre-restore-*")
+	for _, m := range matches {
+		if m == keep {
+			continue
+		}
+		if err := os.RemoveAll(m); err != nil {
+			a.logger(LogSubsystemStorage).Warningf("Failed to remove old pre-restore data %s: %v", m, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useTempPaths apunta las rutas globales de la app a una carpeta temporal y crea
+// las carpetas como initPaths
+func useTempPaths(t *testing.T) {
+	t.Helper()
+	saved := []*string{&absBasePath, &absModToolsPath, &absInstalledPath, &absProfilesPath, &absModStatusPath,
+		&absInstallerPath, &absSettingsPath, &absBackupsPath, &absTokenLedgerPath, &absLoginThrottlePath}
+	values := make([]string, len(saved))
+	for i, p := range saved {
+		values[i] = *p
+	}
+	t.Cleanup(func() {
+		for i, p := range saved {
+			*p = values[i]
+		}
+	})
+
+	absBasePath = t.TempDir()
+	absModToolsPath = filepath.Join(absBasePath, RelativeModToolsDir, ModToolsExeName)
+	absInstalledPath = filepath.Join(absBasePath, RelativeInstalledPath)
+	absProfilesPath = filepath.Join(absBasePath, RelativeProfilesPath)
+	absModStatusPath = filepath.Join(absBasePath, RelativeModStatusFile)
+	absInstallerPath = filepath.Join(absBasePath, RelativeInstallerDir)
+	absSettingsPath = filepath.Join(absBasePath, RelativeSettingsFile)
+	absBackupsPath = filepath.Join(absBasePath, RelativeBackupsDir)
+	absTokenLedgerPath = filepath.Join(absBasePath, RelativeTokenLedger)
+	absLoginThrottlePath = filepath.Join(absBasePath, RelativeLoginThrottle)
+	if err := EnsureDirectoriesAbs([]string{absInstalledPath, absProfilesPath, absBackupsPath}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreKeepsUserBackupsWithRetentionOne(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.installedPath = absInstalledPath
+	settings := a.currentSettings()
+	settings.BackupRetention = 1
+	a.setSettings(settings)
+	if err := a.saveSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(absInstalledPath, "installed.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	created := a.CreateBackup("mine")
+	if !created.Success {
+		t.Fatalf("CreateBackup: %+v", created.Result)
+	}
+	for i := 0; i < 2; i++ {
+		if result := a.RestoreBackup(created.Backup.Name); !result.Success {
+			t.Fatalf("RestoreBackup %d: %+v", i, result.Result)
+		}
+	}
+
+	list := a.ListBackups()
+	if !list.Success {
+		t.Fatalf("ListBackups = %+v", list.Result)
+	}
+	user, automatic := 0, 0
+	for _, b := range list.Backups {
+		if b.Automatic {
+			automatic++
+		} else if b.Name == created.Backup.Name {
+			user++
+		}
+	}
+	if user != 1 {
+		t.Errorf("restored backup was pruned: %+v", list.Backups)
+	}
+	if automatic != 1 {
+		t.Errorf("got %d automatic backups, want 1 with retention 1", automatic)
+	}
+}
+
+func TestRestoreReloadsSettings(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.installedPath = absInstalledPath
+	settings := a.currentSettings()
+	settings.HideOwnedSkins = true
+	a.setSettings(settings)
+	a.saveSettings()
+	created := a.CreateBackup("")
+	if !created.Success {
+		t.Fatalf("CreateBackup: %+v", created.Result)
+	}
+
+	settings.HideOwnedSkins = false
+	a.setSettings(settings)
+	a.saveSettings()
+	if result := a.RestoreBackup(created.Backup.Name); !result.Success {
+		t.Fatalf("RestoreBackup: %+v", result.Result)
+	}
+	if !a.currentSettings().HideOwnedSkins {
+		t.Error("settings were not reloaded from the restored snapshot")
+	}
+}
+
+// El libro de fichas y el contador de login no vuelven atrás al restaurar, y lo
+// que queda en memoria sigue coincidiendo con el disco
+func TestRestoreKeepsTokenLedgerAndLoginThrottle(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.installedPath = absInstalledPath
+	a.ledger, _ = NewTokenLedger(absTokenLedgerPath)
+	a.loginThrottle, _ = NewLoginThrottle(absLoginThrottlePath)
+	a.ledger.Record(TokenLedgerEntry{IdempotencyKey: "k1", UserId: "u1", ChampionId: "103", SkinId: "15", Status: LedgerReserved})
+	a.ledger.Record(TokenLedgerEntry{IdempotencyKey: "k1", UserId: "u1", ChampionId: "103", SkinId: "15", Status: LedgerCommitted})
+
+	created := a.CreateBackup("")
+	if !created.Success {
+		t.Fatalf("CreateBackup: %+v", created.Result)
+	}
+	zr, manifest, err := openBackup(filepath.Join(absBackupsPath, created.Backup.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr.Close()
+	for _, f := range manifest.Files {
+		if keptOnRestore(f.Path) {
+			t.Errorf("snapshot includes %s", f.Path)
+		}
+	}
+
+	a.ledger.Record(TokenLedgerEntry{IdempotencyKey: "k2", UserId: "u1", ChampionId: "157", SkinId: "1", Status: LedgerCommitted})
+	for i := 0; i < 3; i++ {
+		a.loginThrottle.RecordFailure("ahri")
+	}
+	if result := a.RestoreBackup(created.Backup.Name); !result.Success {
+		t.Fatalf("RestoreBackup: %+v", result.Result)
+	}
+
+	onDisk, err := NewTokenLedger(absTokenLedgerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(onDisk.History("u1")); got != 3 {
+		t.Errorf("ledger on disk has %d entries after restore, want 3", got)
+	}
+	if onDisk.Owned("u1", "157", "1") == nil || a.ledger.Owned("u1", "157", "1") == nil {
+		t.Error("spend recorded after the snapshot was rolled back")
+	}
+	throttle, err := NewLoginThrottle(absLoginThrottlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(throttle.AuditLog()), len(a.loginThrottle.AuditLog()); got != want || got == 0 {
+		t.Errorf("login audit log on disk has %d entries, in memory %d", got, want)
+	}
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Códigos de salida de la CLI
+const (
+	ExitOK             = 0
+	ExitFailure        = 1 // El comando falló
+	ExitUsage          = 2 // Comando o argumentos inválidos
+	ExitNotSignedIn    = 3 // Hace falta una sesión iniciada en la app
+	ExitVerifyFailed   = 4 // verify encontró inconsistencias
+	ExitGameInProgress = 5 // El overlay está protegido durante la partida
+	ExitDoctorFailed   = 6 // doctor encontró fallos
+)
+
+const cliUsage = `Usage: skinhunter <command> [options]
+
+Commands:
+  list                          List installed skins
+  install <skinId>              Download and install a catalog skin or chroma (needs a signed-in session)
+  install --local <path>        Import a local mod (.fantome, .zip, .wad.client or folder)
+  uninstall <championId>...     Uninstall the skins of the given champions
+  profile [build]               Show the overlay profile, or rebuild it from the installed skins
+  overlay start|stop|status     Control the mod-tools overlay
+  verify                        Check installed/ against installed.json
+  export <path>                 Export the installed skins as a loadout (--include-custom to bundle local mods)
+  doctor                        Check mod-tools, the game folder, permissions, the backend and running processes
+
+Global options:
+  --json                        Print the result as JSON
+  --verbose                     Print log messages to stderr
+
+Exit codes: 0 ok, 1 failure, 2 usage, 3 not signed in, 4 verify found problems, 5 game in progress,
+            6 doctor found failures
+`
+
+// cliResult es lo que devuelve un comando: Data se imprime con --json y Text sin él
+type cliResult struct {
+	Code int
+	Data interface{}
+	Text string
+}
+
+var cliCommands = map[string]func(a *App, args []string) cliResult{
+	"list":      cliList,
+	"install":   cliInstall,
+	"uninstall": cliUninstall,
+	"profile":   cliProfile,
+	"overlay":   cliOverlay,
+	"verify":    cliVerify,
+	"export":    cliExport,
+	"doctor":    cliDoctor,
+}
+
+// cliArgs es la línea de comandos separada en opciones globales, comando y sus argumentos
+type cliArgs struct {
+	Command string
+	Args    []string
+	JSON    bool
+	Verbose bool
+}
+
+// parseCLIArgs separa las opciones globales, que pueden ir antes o después del comando
+func parseCLIArgs(args []string) cliArgs {
+	parsed := cliArgs{Args: []string{}}
+	for _, arg := range args {
+		switch {
+		case arg == "--json" || arg == "-json":
+			parsed.JSON = true
+		case arg == "--verbose" || arg == "-verbose" || arg == "-v":
+			parsed.Verbose = true
+		case parsed.Command == "":
+			parsed.Command = arg
+		default:
+			parsed.Args = append(parsed.Args, arg)
+		}
+	}
+	return parsed
+}
+
+func isCLIHelp(command string) bool {
+	return command == "help" || command == "--help" || command == "-h"
+}
+
+// isCLIInvocation indica si el ejecutable se lanzó como CLI: con un comando
+// (también después de --json o --verbose) o solo con opciones globales
+func isCLIInvocation(args []string) bool {
+	parsed := parseCLIArgs(args)
+	_, ok := cliCommands[parsed.Command]
+	return ok || isCLIHelp(parsed.Command) || parsed.JSON || parsed.Verbose
+}
+
+// lookupCLICommand devuelve la función del comando. Si no hay que ejecutar
+// ninguno (ayuda o comando desconocido) imprime el uso y devuelve nil con el código de salida.
+func lookupCLICommand(parsed cliArgs, stdout, stderr io.Writer) (func(a *App, args []string) cliResult, int) {
+	if parsed.Command == "" || isCLIHelp(parsed.Command) {
+		fmt.Fprint(stdout, cliUsage)
+		return nil, ExitOK
+	}
+	command, ok := cliCommands[parsed.Command]
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command %q\n\n%s", parsed.Command, cliUsage)
+		return nil, ExitUsage
+	}
+	return command, ExitOK
+}
+
+// runCLI ejecuta un comando sin webview, con los mismos servicios que la app, y
+// devuelve el código de salida
+func runCLI(args []string) int {
+	attachParentConsole()
+
+	parsed := parseCLIArgs(args)
+	command, code := lookupCLICommand(parsed, os.Stdout, os.Stderr)
+	if command == nil {
+		return code
+	}
+
+	app := NewApp()
+	app.headless = true
+	app.cliLogLevel = LogLevelWarning
+	if parsed.Verbose {
+		app.cliLogLevel = LogLevelDebug
+	}
+	app.ctx = context.Background()
+	app.initPaths()
+	app.initServices()
+	app.loadInstalledSkins()
+
+	result := command(app, parsed.Args)
+	writeCLIResult(os.Stdout, result, parsed.JSON)
+	return result.Code
+}
+
+// writeCLIResult imprime el resultado como JSON o como texto
+func writeCLIResult(w io.Writer, result cliResult, jsonOutput bool) {
+	if jsonOutput {
+		data := result.Data
+		if data == nil {
+			data = map[string]interface{}{"success": result.Code == ExitOK}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(data)
+		return
+	}
+	if result.Text != "" {
+		fmt.Fprintln(w, strings.TrimRight(result.Text, "\n"))
+	}
+}
+
+// parseCLIFlags acepta opciones antes, entre o después de los argumentos posicionales
+func parseCLIFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func cliUsageError(format string, args ...interface{}) cliResult {
+	message := fmt.Sprintf(format, args...)
+	return cliResult{
+		Code: ExitUsage,
+		Data: failResult(ErrInvalidArgument, message, nil),
+		Text: message + "\n\n" + cliUsage,
+	}
+}
+
+// cliFromResult convierte una respuesta de la app en un resultado de la CLI. data es
+// la respuesta completa que se imprime con --json; result, su parte común.
+func cliFromResult(data interface{}, result Result) cliResult {
+	if result.Success {
+		message := result.Message
+		if message == "" {
+			message = "OK"
+		}
+		return cliResult{Code: ExitOK, Data: data, Text: message}
+	}
+	code := ExitFailure
+	switch result.Code {
+	case ErrOverlayInGame:
+		code = ExitGameInProgress
+	case ErrAuthInvalidToken, ErrAuthNotSignedIn:
+		code = ExitNotSignedIn
+	}
+	text := "Error: " + result.Error
+	if result.Detail != "" {
+		text += "\n  " + result.Detail
+	}
+	return cliResult{Code: code, Data: data, Text: text}
+}
+
+// cliFromError es cliFromResult para un error de la app
+func cliFromError(err error) cliResult {
+	result := errorResult(err)
+	return cliFromResult(result, result)
+}
+
+func cliList(a *App, args []string) cliResult {
+	if len(args) > 0 {
+		return cliUsageError("list takes no arguments")
+	}
+	installed := a.installedSkins.Snapshot()
+	championIds := make([]string, 0, len(installed))
+	for championId := range installed {
+		championIds = append(championIds, championId)
+	}
+	sort.Strings(championIds)
+
+	skins := make([]map[string]interface{}, 0, len(championIds))
+	var text strings.Builder
+	for _, championId := range championIds {
+		skin := installed[championId]
+		skins = append(skins, map[string]interface{}{"championId": championId, "skin": skin})
+		name := skin.SkinName
+		if skin.ChromaName != "" {
+			name += " (" + skin.ChromaName + ")"
+		}
+		fmt.Fprintf(&text, "%-20s %-40s %s [%s]\n", championId, name, skin.FileName, skin.Source)
+	}
+	if len(skins) == 0 {
+		text.WriteString("No skins installed")
+	}
+	return cliResult{
+		Code: ExitOK,
+		Data: map[string]interface{}{"success": true, "skins": skins},
+		Text: text.String(),
+	}
+}
+
+func cliInstall(a *App, args []string) cliResult {
+	fs := flag.NewFlagSet("install", flag.ContinueOnError)
+	local := fs.String("local", "", "")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return cliUsageError("install: %v", err)
+	}
+	if *local != "" {
+		if len(positional) > 0 {
+			return cliUsageError("install --local takes no skin id")
+		}
+		result := a.ImportLocalMod(*local)
+		return cliFromResult(result, result.Result)
+	}
+	if len(positional) != 1 {
+		return cliUsageError("install needs exactly one skin or chroma id (e.g. 103015)")
+	}
+
+	skinId, err := strconv.Atoi(positional[0])
+	if err != nil || skinId < 1000 {
+		return cliUsageError("invalid skin id %q", positional[0])
+	}
+	if _, err := a.accessToken(""); err != nil {
+		return cliFromError(err)
+	}
+
+	championId := strconv.Itoa(skinId / 1000)
+	data, err := a.backend.Catalog().ChampionJSON(championId)
+	if err != nil {
+		return cliFromError(newAppError(ErrCatalogUnavailable, "Error fetching champion data", err))
+	}
+	candidates, err := parseCatalogCandidates(championId, data, true)
+	if err != nil {
+		return cliFromError(newAppError(ErrCatalogUnavailable, "Invalid champion data format", err))
+	}
+	var skin *RouletteCandidate
+	for i := range candidates {
+		if candidates[i].SkinId == positional[0] {
+			skin = &candidates[i]
+			break
+		}
+	}
+	if skin == nil {
+		return cliFromError(newAppError(ErrNotFound, fmt.Sprintf("Skin %d not found in the catalog", skinId), nil))
+	}
+
+	download := a.DownloadSkin(championId, strconv.Itoa(skinId%1000), "", "", skin.SkinName, skin.FileName, skin.ChromaName, skin.ImageUrl, skin.SkinName)
+	if !download.Success {
+		return cliFromResult(download, download.Result)
+	}
+	result := a.InstallSkin(championId, skin.SkinId, skin.FileName, skin.ChromaName, skin.ImageUrl, skin.SkinName)
+	return cliFromResult(result, result)
+}
+
+func cliUninstall(a *App, args []string) cliResult {
+	if len(args) == 0 {
+		return cliUsageError("uninstall needs at least one champion id")
+	}
+	for _, championId := range args {
+		if _, ok := a.installedSkins.Get(championId); !ok {
+			return cliFromError(newAppError(ErrNotFound, fmt.Sprintf("No skin installed for champion %s", championId), nil))
+		}
+	}
+	result := a.UninstallMultipleSkins(args)
+	return cliFromResult(result, result)
+}
+
+func cliProfile(a *App, args []string) cliResult {
+	switch {
+	case len(args) == 0:
+		files := a.installedSkins.Files()
+		sort.Strings(files)
+		_, statErr := os.Stat(absProfilesPath)
+		text := fmt.Sprintf("Profile: %s\nMods (%d):\n  %s", absProfilesPath, len(files), strings.Join(files, "\n  "))
+		return cliResult{
+			Code: ExitOK,
+			Data: map[string]interface{}{"success": true, "path": absProfilesPath, "exists": statErr == nil, "mods": files},
+			Text: text,
+		}
+	case len(args) == 1 && args[0] == "build":
+		if a.CheckModToolsRunning() {
+			return cliFromError(newAppError(ErrInvalidArgument, "The overlay is running; stop it before rebuilding the profile", nil))
+		}
+		if err := a.buildOverlay(); err != nil {
+			return cliFromError(err)
+		}
+		result := okResult(MsgOverlayRebuilt)
+		return cliFromResult(map[string]interface{}{"success": true, "message": result.Message, "path": absProfilesPath}, result)
+	}
+	return cliUsageError("usage: profile [build]")
+}
+
+func cliOverlay(a *App, args []string) cliResult {
+	if len(args) != 1 {
+		return cliUsageError("usage: overlay start|stop|status")
+	}
+	switch args[0] {
+	case "start":
+		if a.installedSkins.Len() == 0 {
+			return cliFromError(newAppError(ErrNotFound, "No skins installed", nil))
+		}
+		if err := a.checkOverlayNotInGame(); err != nil {
+			return cliFromError(err)
+		}
+		if err := a.buildOverlay(); err != nil {
+			return cliFromError(err)
+		}
+		result := a.StartOverlay()
+		return cliFromResult(result, result.Result)
+	case "stop":
+		result := a.StopOverlay()
+		return cliFromResult(result, result.Result)
+	case "status":
+		running := a.CheckModToolsRunning()
+		status := map[string]interface{}{
+			"success":   true,
+			"running":   running,
+			"modStatus": readModStatus(),
+		}
+		text := "Overlay is not running"
+		if running {
+			text = "Overlay is running"
+		}
+		return cliResult{Code: ExitOK, Data: status, Text: text}
+	}
+	return cliUsageError("unknown overlay command %q", args[0])
+}
+
+func cliVerify(a *App, args []string) cliResult {
+	if len(args) > 0 {
+		return cliUsageError("verify takes no arguments")
+	}
+	report, err := a.reconcileInstalled()
+	if err != nil {
+		return cliFromError(newAppError(ErrStorageFailed, "Could not check installed skins", err))
+	}
+	data := map[string]interface{}{"success": report.Consistent, "report": report}
+	if report.Consistent {
+		return cliResult{Code: ExitOK, Data: data, Text: "installed/ is consistent"}
+	}
+	var text strings.Builder
+	for _, missing := range report.MissingFiles {
+		fmt.Fprintf(&text, "missing file:   %s (champion %s)\n", missing.FileName, missing.ChampionId)
+	}
+	for _, stray := range report.UntrackedFiles {
+		fmt.Fprintf(&text, "untracked file: %s\n", stray.Name)
+	}
+	for _, stray := range report.LeftoverDirs {
+		fmt.Fprintf(&text, "leftover dir:   %s\n", stray.Name)
+	}
+	return cliResult{Code: ExitVerifyFailed, Data: data, Text: text.String()}
+}
+
+func cliExport(a *App, args []string) cliResult {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	includeCustom := fs.Bool("include-custom", false, "")
+	positional, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return cliUsageError("export: %v", err)
+	}
+	if len(positional) != 1 {
+		return cliUsageError("export needs a destination path")
+	}
+	result := a.ExportLoadout(positional[0], *includeCustom)
+	return cliFromResult(result, result.Result)
+}
+
+func cliDoctor(a *App, args []string) cliResult {
+	if len(args) > 0 {
+		return cliUsageError("doctor takes no arguments")
+	}
+	report := a.RunDiagnostics()
+	var text strings.Builder
+	for _, check := range report.Checks {
+		fmt.Fprintf(&text, "[%s] %-28s %s\n", check.Status, check.ID, check.Message)
+		if check.Hint != "" {
+			fmt.Fprintf(&text, "       %-28s %s\n", "", check.Hint)
+		}
+	}
+	text.WriteString(report.Message)
+	code := ExitOK
+	if report.Status == CheckFail {
+		code = ExitDoctorFailed
+	}
+	return cliResult{Code: code, Data: report, Text: text.String()}
+}
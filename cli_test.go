@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCLIArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want cliArgs
+		cli  bool
+	}{
+		{[]string{"list"}, cliArgs{Command: "list", Args: []string{}}, true},
+		{[]string{"list", "--json"}, cliArgs{Command: "list", Args: []string{}, JSON: true}, true},
+		{[]string{"--json", "list"}, cliArgs{Command: "list", Args: []string{}, JSON: true}, true},
+		{[]string{"-v", "uninstall", "103", "--json", "222"}, cliArgs{Command: "uninstall", Args: []string{"103", "222"}, JSON: true, Verbose: true}, true},
+		{[]string{"install", "--local", "mod.zip"}, cliArgs{Command: "install", Args: []string{"--local", "mod.zip"}}, true},
+		{[]string{"--help"}, cliArgs{Command: "--help", Args: []string{}}, true},
+		{[]string{"--verbose"}, cliArgs{Args: []string{}, Verbose: true}, true},
+		{[]string{}, cliArgs{Args: []string{}}, false},
+		{[]string{"-someWailsFlag"}, cliArgs{Command: "-someWailsFlag", Args: []string{}}, false},
+	}
+	for _, tt := range tests {
+		if got := parseCLIArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCLIArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+		}
+		if got := isCLIInvocation(tt.args); got != tt.cli {
+			t.Errorf("isCLIInvocation(%q) = %v, want %v", tt.args, got, tt.cli)
+		}
+	}
+}
+
+func TestLookupCLICommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if command, code := lookupCLICommand(parseCLIArgs([]string{"help"}), &stdout, &stderr); command != nil || code != ExitOK {
+		t.Errorf("help = %v, %d", command != nil, code)
+	}
+	if !strings.HasPrefix(stdout.String(), "Usage:") {
+		t.Errorf("help printed %q", stdout.String())
+	}
+	if command, code := lookupCLICommand(parseCLIArgs([]string{"--json", "lsit"}), &stdout, &stderr); command != nil || code != ExitUsage {
+		t.Errorf("unknown command = %v, %d", command != nil, code)
+	}
+	if !strings.Contains(stderr.String(), `Unknown command "lsit"`) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+	if command, code := lookupCLICommand(parseCLIArgs([]string{"verify"}), &stdout, &stderr); command == nil || code != ExitOK {
+		t.Errorf("verify = %v, %d", command != nil, code)
+	}
+}
+
+// cliJSON ejecuta un comando y decodifica lo que imprimiría con --json
+func cliJSON(t *testing.T, a *App, args ...string) (cliResult, map[string]interface{}) {
+	t.Helper()
+	parsed := parseCLIArgs(args)
+	command, _ := lookupCLICommand(parsed, &bytes.Buffer{}, &bytes.Buffer{})
+	if command == nil {
+		t.Fatalf("no command in %q", args)
+	}
+	result := command(a, parsed.Args)
+	var out bytes.Buffer
+	writeCLIResult(&out, result, true)
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(out.Bytes(), &data); err != nil {
+		t.Fatalf("%q printed invalid JSON %q: %v", args, out.String(), err)
+	}
+	return result, data
+}
+
+func TestCLIListJSON(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.installedSkins.Set("222", SkinInfo{SkinId: "222003", FileName: "jinx.fantome", SkinName: "Jinx", Source: SkinSourceCatalog})
+	a.installedSkins.Set("103", SkinInfo{SkinId: "103015", FileName: "ahri.fantome", SkinName: "Ahri", ChromaName: "Ruby", Source: SkinSourceCatalog})
+
+	result, data := cliJSON(t, a, "list", "--json")
+	if result.Code != ExitOK || data["success"] != true {
+		t.Fatalf("list = %d, %v", result.Code, data)
+	}
+	skins, _ := data["skins"].([]interface{})
+	if len(skins) != 2 {
+		t.Fatalf("skins = %v", data["skins"])
+	}
+	first, _ := skins[0].(map[string]interface{})
+	skin, _ := first["skin"].(map[string]interface{})
+	if first["championId"] != "103" || skin["fileName"] != "ahri.fantome" || skin["chromaName"] != "Ruby" {
+		t.Errorf("first skin = %v", first)
+	}
+
+	var text bytes.Buffer
+	writeCLIResult(&text, result, false)
+	if !strings.Contains(text.String(), "Ahri (Ruby)") {
+		t.Errorf("text output = %q", text.String())
+	}
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 1)
+	backend.PutObject(ChampionJSONBucket, "103.json", []byte(`{"skins": [
+		{"id": 103000, "name": "Ahri", "isBase": true},
+		{"id": 103001, "name": "Dynasty Ahri"}
+	]}`))
+
+	usage := [][]string{
+		{"list", "extra"},
+		{"install"},
+		{"install", "abc"},
+		{"install", "--local", "mod.zip", "103001"},
+		{"uninstall"},
+		{"overlay"},
+		{"overlay", "restart"},
+		{"profile", "rebuild"},
+		{"verify", "now"},
+		{"export"},
+	}
+	for _, args := range usage {
+		result, data := cliJSON(t, a, args...)
+		if result.Code != ExitUsage || data["success"] != false || data["code"] != string(ErrInvalidArgument) {
+			t.Errorf("%q = %d, %v; want usage error", args, result.Code, data)
+		}
+	}
+
+	// Sin sesión no se puede descargar
+	if result, data := cliJSON(t, a, "install", "103001"); result.Code != ExitNotSignedIn || data["code"] != string(ErrAuthNotSignedIn) {
+		t.Errorf("install without a session = %d, %v", result.Code, data)
+	}
+	if result, _ := cliJSON(t, a, "uninstall", "103"); result.Code != ExitFailure {
+		t.Errorf("uninstall of a missing skin = %d", result.Code)
+	}
+
+	// verify falla con una entrada sin archivo y pasa cuando todo coincide
+	a.installedSkins.Set("157", SkinInfo{SkinId: "157001", FileName: "yasuo.fantome", Source: SkinSourceCatalog})
+	result, data := cliJSON(t, a, "verify")
+	if result.Code != ExitVerifyFailed || data["success"] != false || data["report"] == nil {
+		t.Errorf("verify = %d, %v", result.Code, data)
+	}
+	if err := os.WriteFile(filepath.Join(absInstalledPath, "yasuo.fantome"), []byte("yasuo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := cliJSON(t, a, "verify"); result.Code != ExitOK {
+		t.Errorf("consistent verify = %d", result.Code)
+	}
+
+	// Durante una partida el overlay está protegido
+	a.setGameflowPhase(PhaseInProgress, "test")
+	if result, data := cliJSON(t, a, "overlay", "start"); result.Code != ExitGameInProgress || data["code"] != string(ErrOverlayInGame) {
+		t.Errorf("overlay start in game = %d, %v", result.Code, data)
+	}
+	a.setGameflowPhase(PhaseNone, "test")
+
+	// Con sesión descarga del catálogo con el número de skin y la registra
+	useFakeModTools(t)
+	login(t, a)
+	result, data = cliJSON(t, a, "install", "103001")
+	// Arrancar el overlay usa cmd.exe: fuera de Windows solo falla ese último paso
+	if result.Code != ExitOK && data["code"] != string(ErrModToolsStartFailed) {
+		t.Fatalf("install = %d, %v", result.Code, data)
+	}
+	if content, _ := os.ReadFile(filepath.Join(absInstalledPath, "dynasty-ahri.fantome")); string(content) != "dynasty ahri" {
+		t.Errorf("installed file = %q", content)
+	}
+	if skin, ok := a.installedSkins.Get("103"); !ok || skin.SkinId != "103001" {
+		t.Errorf("installed entry = %+v", skin)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// attachParentConsole no hace nada fuera de Windows: la salida estándar ya es la de la terminal
+func attachParentConsole() {}
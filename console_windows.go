@@ -0,0 +1,40 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// attachParentConsole conecta stdout y stderr a la consola desde la que se lanzó
+// el ejecutable. La app se compila como aplicación de ventana, así que sin esto
+// la salida de la CLI se pierde cuando no está redirigida a un archivo o pipe.
+func attachParentConsole() {
+	const attachParentProcess = ^uintptr(0) // ATTACH_PARENT_PROCESS
+	kernel32 := windows.NewLazySystemDLL("kernel32.dll")
+	if ret, _, _ := kernel32.NewProc("AttachConsole").Call(attachParentProcess); ret == 0 {
+		return // Lanzado sin consola (p. ej. doble clic)
+	}
+	if !validStdHandle(windows.STD_OUTPUT_HANDLE) {
+		if f, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+			os.Stdout = f
+		}
+	}
+	if !validStdHandle(windows.STD_ERROR_HANDLE) {
+		if f, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+			os.Stderr = f
+		}
+	}
+}
+
+// validStdHandle indica si el handle estándar ya apunta a algo (p. ej. una redirección)
+func validStdHandle(std uint32) bool {
+	h, err := windows.GetStdHandle(std)
+	if err != nil || h == 0 || h == windows.InvalidHandle {
+		return false
+	}
+	_, err = windows.GetFileType(h)
+	return err == nil
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultControlAPIPort es el puerto por defecto de la API local de control
+const DefaultControlAPIPort = 48215
+
+const (
+	controlClientBuffer = 64               // Eventos en cola por cliente antes de descartar
+	controlPingInterval = 30 * time.Second // Ping para detectar clientes caídos
+	controlWriteTimeout = 10 * time.Second
+)
+
+// ControlAPISettings configura la API local (HTTP y WebSocket) para herramientas
+// externas como Stream Deck u OBS. Solo escucha en 127.0.0.1.
+type ControlAPISettings struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port"`
+	Token   string `json:"token"` // Se genera al activarla si está vacío
+}
+
+// normalize corrige el puerto fuera de rango
+func (s *ControlAPISettings) normalize() {
+	if s.Port < 1024 || s.Port > 65535 {
+		s.Port = DefaultControlAPIPort
+	}
+}
+
+// newControlToken genera un token aleatorio para la API de control
+func newControlToken() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// controlEvent es un mensaje del feed de eventos
+type controlEvent struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data,omitempty"`
+	Time  string      `json:"time"`
+}
+
+// ControlServer sirve la API local de control y reparte los eventos de la app a
+// los clientes conectados por WebSocket
+type ControlServer struct {
+	app      *App
+	token    string
+	port     int
+	server   *http.Server
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[chan controlEvent]struct{}
+}
+
+// NewControlServer crea el servidor para app; no escucha hasta Start
+func NewControlServer(app *App, port int, token string) *ControlServer {
+	s := &ControlServer{
+		app:     app,
+		token:   token,
+		port:    port,
+		clients: map[chan controlEvent]struct{}{},
+	}
+	// El token ya protege la conexión; Origin se acepta para que funcionen páginas locales (OBS)
+	s.upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	return s
+}
+
+// Handler devuelve las rutas de la API, protegidas por token y Host local
+func (s *ControlServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/skins", s.handleSkins)
+	mux.HandleFunc("/api/overlay/start", s.handleOverlay)
+	mux.HandleFunc("/api/overlay/stop", s.handleOverlay)
+	mux.HandleFunc("/api/overlay/restart", s.handleOverlay)
+	mux.HandleFunc("/api/events", s.handleEvents)
+	return s.guard(mux)
+}
+
+// Start escucha en 127.0.0.1:port
+func (s *ControlServer) Start() error {
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(s.port)))
+	if err != nil {
+		return fmt.Errorf("could not listen on port %d: %v", s.port, err)
+	}
+	s.server = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go s.server.Serve(listener)
+	return nil
+}
+
+// Stop cierra el servidor y las conexiones de eventos
+func (s *ControlServer) Stop() {
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		s.server.Shutdown(ctx)
+	}
+	s.mu.Lock()
+	for ch := range s.clients {
+		close(ch)
+		delete(s.clients, ch)
+	}
+	s.mu.Unlock()
+}
+
+// Broadcast envía un evento a todos los clientes. Si un cliente no lee, se le
+// descartan eventos en vez de frenar a la app.
+func (s *ControlServer) Broadcast(event string, data ...interface{}) {
+	msg := controlEvent{Event: event, Time: time.Now().Format(time.RFC3339)}
+	if len(data) == 1 {
+		msg.Data = data[0]
+	} else if len(data) > 1 {
+		msg.Data = data
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// guard rechaza pedidos sin token o con un Host que no sea local (DNS rebinding)
+func (s *ControlServer) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if host != "127.0.0.1" && host != "localhost" {
+			writeControlJSON(w, http.StatusForbidden, failResult(ErrInvalidArgument, "Forbidden", nil))
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" && r.URL.Path == "/api/events" {
+			// Los WebSocket del navegador no pueden enviar headers. En el resto de
+			// las rutas no se acepta, para que el token no quede en URLs ni historiales.
+			token = r.URL.Query().Get("token")
+		}
+		if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeControlJSON(w, http.StatusUnauthorized, failResult(ErrAuthInvalidToken, "Invalid token", nil))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *ControlServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeControlJSON(w, http.StatusMethodNotAllowed, failResult(ErrInvalidArgument, "Method not allowed", nil))
+		return
+	}
+	a := s.app
+	writeControlJSON(w, http.StatusOK, map[string]interface{}{
+		"success":        true,
+		"overlayRunning": a.CheckModToolsRunning(),
+		"modStatus":      readModStatus(),
+		"gameflowPhase":  a.GetGameflowPhase(),
+		"lcu":            a.GetLCUStatus(),
+		"installedCount": a.installedSkins.Len(),
+	})
+}
+
+func (s *ControlServer) handleSkins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeControlJSON(w, http.StatusMethodNotAllowed, failResult(ErrInvalidArgument, "Method not allowed", nil))
+		return
+	}
+	result := s.app.GetInstalledSkins()
+	status := http.StatusOK
+	if !result.Success {
+		status = http.StatusInternalServerError
+	}
+	writeControlJSON(w, status, result)
+}
+
+func (s *ControlServer) handleOverlay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeControlJSON(w, http.StatusMethodNotAllowed, failResult(ErrInvalidArgument, "Method not allowed", nil))
+		return
+	}
+	a := s.app
+	var result OverlayResult
+	switch strings.TrimPrefix(r.URL.Path, "/api/overlay/") {
+	case "start":
+		result = a.StartRunOverlay()
+	case "stop":
+		result = a.StopOverlay()
+	case "restart":
+		result = a.StartOverlay()
+	}
+	status := http.StatusOK
+	if !result.Success {
+		status = http.StatusInternalServerError
+		if result.Code == ErrOverlayInGame {
+			status = http.StatusConflict
+		}
+	}
+	writeControlJSON(w, status, result)
+}
+
+// handleEvents abre el feed de eventos por WebSocket: cada mensaje es {"event", "data", "time"}
+func (s *ControlServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade ya respondió con el error
+	}
+	ch := make(chan controlEvent, controlClientBuffer)
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+
+	// Lector: solo para detectar el cierre del cliente
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(controlPingInterval)
+	defer func() {
+		ping.Stop()
+		s.mu.Lock()
+		if _, ok := s.clients[ch]; ok {
+			delete(s.clients, ch)
+			close(ch)
+		}
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(controlWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(controlWriteTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func writeControlJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// applyControlAPI arranca, reinicia o detiene el servidor según la configuración
+func (a *App) applyControlAPI() {
+	cfg := a.currentSettings().ControlAPI
+	a.controlMu.Lock()
+	previous := a.control
+	a.control = nil
+	a.controlMu.Unlock()
+	if previous != nil {
+		previous.Stop()
+	}
+	if !cfg.Enabled {
+		return
+	}
+
+	server := NewControlServer(a, cfg.Port, cfg.Token)
+	if err := server.Start(); err != nil {
+		a.logger(LogSubsystemControl).Errorf("Control API: %v", err)
+		return
+	}
+	a.controlMu.Lock()
+	a.control = server
+	a.controlMu.Unlock()
+	a.logger(LogSubsystemControl).Infof("Control API listening on 127.0.0.1:%d", cfg.Port)
+}
+
+// controlServer devuelve el servidor activo, o nil
+func (a *App) controlServer() *ControlServer {
+	a.controlMu.Lock()
+	defer a.controlMu.Unlock()
+	return a.control
+}
+
+// ControlAPIStatus indica si la API local está activa y cómo conectarse
+type ControlAPIStatus struct {
+	Result
+	Enabled   bool   `json:"enabled"`
+	Running   bool   `json:"running"`
+	URL       string `json:"url"`
+	EventsURL string `json:"eventsUrl"`
+	Token     string `json:"token"`
+}
+
+// GetControlAPIStatus informa si la API local está activa y cómo conectarse
+func (a *App) GetControlAPIStatus() ControlAPIStatus {
+	cfg := a.currentSettings().ControlAPI
+	return ControlAPIStatus{
+		Result:    okResult(""),
+		Enabled:   cfg.Enabled,
+		Running:   a.controlServer() != nil,
+		URL:       fmt.Sprintf("http://127.0.0.1:%d/api", cfg.Port),
+		EventsURL: fmt.Sprintf("ws://127.0.0.1:%d/api/events", cfg.Port),
+		Token:     cfg.Token,
+	}
+}
+
+// RegenerateControlAPIToken invalida el token actual y reinicia el servidor con uno nuevo
+func (a *App) RegenerateControlAPIToken() ControlAPIStatus {
+	settings := a.currentSettings()
+	settings.ControlAPI.Token = newControlToken()
+	if result := a.UpdateSettings(settings); !result.Success {
+		return ControlAPIStatus{Result: result.Result}
+	}
+	return a.GetControlAPIStatus()
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testControlToken = "control-token"
+
+// newTestControlServer sirve la API de control de una App de prueba en un puerto local
+func newTestControlServer(t *testing.T) (*App, *ControlServer, *httptest.Server) {
+	t.Helper()
+	useTempPaths(t)
+	a := newTestApp(t)
+	control := NewControlServer(a, 0, testControlToken)
+	server := httptest.NewServer(control.Handler())
+	t.Cleanup(func() {
+		control.Stop()
+		server.Close()
+	})
+	a.controlMu.Lock()
+	a.control = control
+	a.controlMu.Unlock()
+	return a, control, server
+}
+
+// controlRequest hace un pedido a la API; header Host y token vacíos se omiten
+func controlRequest(t *testing.T, server *httptest.Server, method, path, host, token string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "" {
+		req.Host = host
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body := map[string]interface{}{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestControlAPIGuard(t *testing.T) {
+	_, _, server := newTestControlServer(t)
+	port := server.URL[strings.LastIndex(server.URL, ":")+1:]
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		host   string
+		token  string
+		status int
+		code   ErrorCode
+	}{
+		{"valid token", "GET", "/api/status", "", testControlToken, http.StatusOK, ""},
+		{"localhost host", "GET", "/api/status", "localhost:" + port, testControlToken, http.StatusOK, ""},
+		{"missing token", "GET", "/api/status", "", "", http.StatusUnauthorized, ErrAuthInvalidToken},
+		{"wrong token", "GET", "/api/status", "", "control-tokem", http.StatusUnauthorized, ErrAuthInvalidToken},
+		{"rebinding host", "GET", "/api/status", "evil.example:" + port, testControlToken, http.StatusForbidden, ErrInvalidArgument},
+		{"rebinding host without port", "GET", "/api/skins", "evil.example", testControlToken, http.StatusForbidden, ErrInvalidArgument},
+		{"query token outside events", "GET", "/api/status?token=" + testControlToken, "", "", http.StatusUnauthorized, ErrAuthInvalidToken},
+		{"POST status", "POST", "/api/status", "", testControlToken, http.StatusMethodNotAllowed, ErrInvalidArgument},
+		{"POST skins", "POST", "/api/skins", "", testControlToken, http.StatusMethodNotAllowed, ErrInvalidArgument},
+		{"GET overlay start", "GET", "/api/overlay/start", "", testControlToken, http.StatusMethodNotAllowed, ErrInvalidArgument},
+		{"GET overlay stop without token", "GET", "/api/overlay/stop", "", "", http.StatusUnauthorized, ErrAuthInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := controlRequest(t, server, tt.method, tt.path, tt.host, tt.token)
+			if status != tt.status {
+				t.Errorf("status = %d, want %d (%v)", status, tt.status, body)
+			}
+			if tt.code != "" && body["code"] != string(tt.code) {
+				t.Errorf("code = %v, want %s", body["code"], tt.code)
+			}
+		})
+	}
+}
+
+// Sin token configurado la API no acepta nada, ni siquiera un token vacío
+func TestControlAPIGuardWithoutToken(t *testing.T) {
+	useTempPaths(t)
+	server := httptest.NewServer(NewControlServer(newTestApp(t), 0, "").Handler())
+	t.Cleanup(server.Close)
+	if status, _ := controlRequest(t, server, "GET", "/api/status?token=", "", ""); status != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", status)
+	}
+}
+
+func TestControlAPIStatusAndSkins(t *testing.T) {
+	a, _, server := newTestControlServer(t)
+	a.installedSkins.Set("103", SkinInfo{SkinId: "103015", FileName: "ahri.fantome"})
+	if err := a.saveInstalledSkins(); err != nil {
+		t.Fatal(err)
+	}
+
+	status, body := controlRequest(t, server, "GET", "/api/status", "", testControlToken)
+	if status != http.StatusOK || body["success"] != true || body["installedCount"] != float64(1) {
+		t.Errorf("status = %d, %v", status, body)
+	}
+	status, body = controlRequest(t, server, "GET", "/api/skins", "", testControlToken)
+	skins, _ := body["skins"].([]interface{})
+	if status != http.StatusOK || body["success"] != true || len(skins) != 1 {
+		t.Fatalf("skins = %d, %v", status, body)
+	}
+	if skin, _ := skins[0].(map[string]interface{}); skin["championId"] != "103" || skin["fileName"] != "ahri.fantome" {
+		t.Errorf("skin = %v", skin)
+	}
+}
+
+// El feed acepta el token en la URL y recibe los eventos que la app emite
+func TestControlAPIEventsFeed(t *testing.T) {
+	a, control, server := newTestControlServer(t)
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/events"
+
+	if _, resp, err := websocket.DefaultDialer.Dial(wsURL+"?token=wrong", nil); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("dial with a wrong token: %v", err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+testControlToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// El cliente queda registrado poco después del upgrade
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		control.mu.Lock()
+		registered := len(control.clients)
+		control.mu.Unlock()
+		if registered == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("events client was not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	a.emit("overlay-stopped", map[string]interface{}{"exitError": false})
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg controlEvent
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := msg.Data.(map[string]interface{})
+	if msg.Event != "overlay-stopped" || data["exitError"] != false || msg.Time == "" {
+		t.Errorf("event = %+v", msg)
+	}
+
+	// Al detener el servidor el feed se cierra
+	control.Stop()
+	if err := conn.ReadJSON(&msg); err == nil {
+		t.Errorf("feed still open after Stop, got %+v", msg)
+	}
+}
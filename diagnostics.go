@@ -0,0 +1,201 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DiagnosticsFileName es el nombre propuesto para el paquete de diagnóstico
+const DiagnosticsFileName = "skinhunter-diagnostics.zip"
+
+// redactedValue reemplaza los secretos en la configuración exportada
+const redactedValue = "[redacted]"
+
+// DiagnosticsResult es la respuesta de ExportDiagnostics
+type DiagnosticsResult struct {
+	Result
+	Path  string   `json:"path,omitempty"`  // Archivo exportado
+	Files []string `json:"files,omitempty"` // Rutas dentro del zip
+}
+
+// diagnosticsPath describe una ruta de la app en environment.json
+type diagnosticsPath struct {
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+// diagnosticsEnvironment es el contenido de environment.json
+type diagnosticsEnvironment struct {
+	CreatedAt      string                     `json:"createdAt"`
+	OS             string                     `json:"os"`
+	Arch           string                     `json:"arch"`
+	GoVersion      string                     `json:"goVersion"`
+	Executable     string                     `json:"executable"`
+	Paths          map[string]diagnosticsPath `json:"paths"`
+	Locale         Locale                     `json:"locale"`
+	SystemLocale   string                     `json:"systemLocale"`
+	OverlayRunning bool                       `json:"overlayRunning"`
+	GameflowPhase  string                     `json:"gameflowPhase"`
+	InstalledSkins int                        `json:"installedSkins"`
+}
+
+// SelectDiagnosticsSavePath abre un diálogo para elegir dónde guardar el paquete de diagnóstico
+func (a *App) SelectDiagnosticsSavePath() (string, error) {
+	return runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export diagnostics",
+		DefaultFilename: DiagnosticsFileName,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Zip archive (*.zip)", Pattern: "*.zip"},
+		},
+	})
+}
+
+// ExportDiagnostics guarda en destPath un zip para soporte con los registros,
+// la configuración (sin secretos), installed.json, el historial de mod-status
+// y un resumen del entorno
+func (a *App) ExportDiagnostics(destPath string) DiagnosticsResult {
+	if strings.TrimSpace(destPath) == "" {
+		return DiagnosticsResult{Result: failResult(ErrInvalidArgument, "No destination selected", nil)}
+	}
+	a.logger(LogSubsystemApp).Infof("ExportDiagnostics: Exporting to %s", destPath)
+
+	settingsData, err := json.MarshalIndent(redactedSettings(a.currentSettings()), "", "  ")
+	if err != nil {
+		return DiagnosticsResult{Result: failResult(ErrInternal, "Error encoding settings", err)}
+	}
+	environmentData, err := json.MarshalIndent(a.diagnosticsEnvironment(), "", "  ")
+	if err != nil {
+		return DiagnosticsResult{Result: failResult(ErrInternal, "Error encoding environment", err)}
+	}
+
+	generated := map[string][]byte{
+		"settings.json":    settingsData,
+		"environment.json": environmentData,
+	}
+	files := make(map[string]string) // Ruta en el zip -> ruta absoluta en disco
+	if logFile := a.currentLogFile(); logFile != nil {
+		for _, p := range logFile.Files() {
+			files["logs/"+filepath.Base(p)] = p
+		}
+	}
+	files["installed.json"] = filepath.Join(absInstalledPath, "installed.json")
+	modStatusName := filepath.Base(absModStatusPath)
+	files[modStatusName] = absModStatusPath
+	for n := 1; n <= MetadataBackupCount; n++ {
+		files[filepath.Base(backupPath(absModStatusPath, n))] = backupPath(absModStatusPath, n)
+	}
+	for name, p := range files {
+		if _, err := os.Stat(p); err != nil {
+			delete(files, name) // Lo que no existe todavía simplemente no va
+		}
+	}
+
+	names, err := writeDiagnosticsArchive(destPath, generated, files)
+	if err != nil {
+		a.logger(LogSubsystemApp).Errorf("ExportDiagnostics: %v", err)
+		return DiagnosticsResult{Result: failResult(ErrStorageFailed, "Error writing diagnostics bundle", err)}
+	}
+	a.logger(LogSubsystemApp).Infof("ExportDiagnostics: Wrote %d files to %s", len(names), destPath)
+	return DiagnosticsResult{
+		Result: okResult(MsgDiagnosticsExported, len(names)),
+		Path:   destPath,
+		Files:  names,
+	}
+}
+
+// redactedSettings devuelve una copia de settings sin claves ni tokens. La anon
+// key de Supabase es pública y se deja.
+func redactedSettings(settings Settings) Settings {
+	if settings.Backend.S3.AccessKeyID != "" {
+		settings.Backend.S3.AccessKeyID = redactedValue
+	}
+	if settings.Backend.S3.SecretAccessKey != "" {
+		settings.Backend.S3.SecretAccessKey = redactedValue
+	}
+	if settings.ControlAPI.Token != "" {
+		settings.ControlAPI.Token = redactedValue
+	}
+	return settings
+}
+
+func (a *App) diagnosticsEnvironment() diagnosticsEnvironment {
+	exe, _ := os.Executable()
+	paths := make(map[string]diagnosticsPath)
+	for name, p := range map[string]string{
+		"base":      absBasePath,
+		"game":      absGamePath,
+		"modTools":  absModToolsPath,
+		"installed": absInstalledPath,
+		"profiles":  absProfilesPath,
+		"backups":   absBackupsPath,
+	} {
+		_, err := os.Stat(p)
+		paths[name] = diagnosticsPath{Path: p, Exists: err == nil}
+	}
+	return diagnosticsEnvironment{
+		CreatedAt:      time.Now().Format(time.RFC3339),
+		OS:             goruntime.GOOS,
+		Arch:           goruntime.GOARCH,
+		GoVersion:      goruntime.Version(),
+		Executable:     exe,
+		Paths:          paths,
+		Locale:         currentLocale(),
+		SystemLocale:   systemLocaleName(),
+		OverlayRunning: a.CheckModToolsRunning(),
+		GameflowPhase:  a.GetGameflowPhase(),
+		InstalledSkins: a.installedSkins.Len(),
+	}
+}
+
+// writeDiagnosticsArchive escribe el zip en un temporal y lo mueve a destPath.
+// Devuelve las rutas incluidas.
+func writeDiagnosticsArchive(destPath string, generated map[string][]byte, files map[string]string) ([]string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".diagnostics-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %v", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op tras el rename
+
+	var names []string
+	zw := zip.NewWriter(tmp)
+	for name, data := range generated {
+		w, err := zw.Create(name)
+		if err == nil {
+			_, err = w.Write(data)
+		}
+		if err != nil {
+			tmp.Close()
+			return nil, fmt.Errorf("error writing %s: %v", name, err)
+		}
+		names = append(names, name)
+	}
+	for name, absPath := range files {
+		if err := copyModEntry(zw, fileEntry(name, absPath)); err != nil {
+			tmp.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("error finishing diagnostics bundle: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("error closing diagnostics bundle: %v", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return nil, fmt.Errorf("error moving diagnostics bundle to %s: %v", destPath, err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
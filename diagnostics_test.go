@@ -0,0 +1,119 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactedSettings(t *testing.T) {
+	settings := defaultSettings()
+	settings.Backend.SupabaseAnonKey = "anon-key"
+	settings.Backend.S3.Endpoint = "http://127.0.0.1:9000"
+	settings.Backend.S3.AccessKeyID = "AKIA123"
+	settings.Backend.S3.SecretAccessKey = "s3-secret"
+	settings.ControlAPI.Token = "control-token"
+
+	redacted := redactedSettings(settings)
+	if redacted.Backend.S3.AccessKeyID != redactedValue || redacted.Backend.S3.SecretAccessKey != redactedValue || redacted.ControlAPI.Token != redactedValue {
+		t.Errorf("secrets left in %+v / %+v", redacted.Backend.S3, redacted.ControlAPI)
+	}
+	// La anon key es pública y el resto no cambia
+	if redacted.Backend.SupabaseAnonKey != "anon-key" || redacted.Backend.S3.Endpoint != "http://127.0.0.1:9000" {
+		t.Errorf("redacted too much: %+v", redacted.Backend)
+	}
+	// Es una copia: el original conserva los secretos
+	if settings.Backend.S3.SecretAccessKey != "s3-secret" || settings.ControlAPI.Token != "control-token" {
+		t.Errorf("original settings modified: %+v", settings.Backend.S3)
+	}
+
+	// Lo vacío se deja vacío para no sugerir que hay una clave
+	empty := redactedSettings(defaultSettings())
+	if empty.Backend.S3.AccessKeyID != "" || empty.Backend.S3.SecretAccessKey != "" || empty.ControlAPI.Token != "" {
+		t.Errorf("empty secrets redacted: %+v / %+v", empty.Backend.S3, empty.ControlAPI)
+	}
+}
+
+// readZip devuelve el contenido de cada archivo del zip por nombre
+func readZip(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	contents := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[f.Name] = data
+	}
+	return contents
+}
+
+func TestExportDiagnosticsContents(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	settings := a.currentSettings()
+	settings.Backend.S3.SecretAccessKey = "s3-secret"
+	settings.ControlAPI.Token = "control-token"
+	a.setSettings(settings)
+	a.openLogFile()
+	defer a.currentLogFile().Close()
+	a.logger(LogSubsystemApp).Info("before export")
+
+	a.installedSkins.Set("103", SkinInfo{SkinId: "103015", FileName: "ahri.fantome"})
+	if err := a.saveInstalledSkins(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(absModStatusPath, []byte(`{"status":"idle"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := a.ExportDiagnostics(" "); result.Code != ErrInvalidArgument {
+		t.Errorf("ExportDiagnostics without a destination = %+v", result.Result)
+	}
+
+	dest := filepath.Join(t.TempDir(), DiagnosticsFileName)
+	result := a.ExportDiagnostics(dest)
+	if !result.Success || result.Path != dest {
+		t.Fatalf("ExportDiagnostics = %+v", result)
+	}
+	contents := readZip(t, dest)
+	want := []string{"environment.json", "installed.json", "logs/" + logFileName, filepath.Base(absModStatusPath), "settings.json"}
+	if strings.Join(result.Files, ",") != strings.Join(want, ",") || len(contents) != len(want) {
+		t.Errorf("files = %v (zip has %d), want %v", result.Files, len(contents), want)
+	}
+
+	if strings.Contains(string(contents["settings.json"]), "s3-secret") || strings.Contains(string(contents["settings.json"]), "control-token") {
+		t.Errorf("settings.json has secrets: %s", contents["settings.json"])
+	}
+	var env diagnosticsEnvironment
+	if err := json.Unmarshal(contents["environment.json"], &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.InstalledSkins != 1 || env.Paths["base"].Path != absBasePath || !env.Paths["installed"].Exists {
+		t.Errorf("environment = %+v", env)
+	}
+	if !strings.Contains(string(contents["installed.json"]), "ahri.fantome") {
+		t.Errorf("installed.json = %s", contents["installed.json"])
+	}
+	if !strings.Contains(string(contents["logs/"+logFileName]), "before export") {
+		t.Errorf("log = %s", contents["logs/"+logFileName])
+	}
+	// No quedan temporales junto al destino
+	if entries, _ := os.ReadDir(filepath.Dir(dest)); len(entries) != 1 {
+		t.Errorf("destination folder = %v", entries)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CheckStatus es el resultado de una comprobación de RunDiagnostics
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn" // Funciona, pero puede dar problemas
+	CheckFail CheckStatus = "fail" // Algo que la app necesita no está bien
+)
+
+// ModToolsDLLName es la DLL de cslol-tools que se carga en el juego
+const ModToolsDLLName = "cslol-dll.dll"
+
+// modToolsSHA256 son los hashes de los binarios de cslol-tools que se distribuyen
+// con la app. Hay que actualizarlos al cambiar de versión de cslol-tools.
+var modToolsSHA256 = []struct {
+	Name   string
+	SHA256 string
+}{
+	{ModToolsExeName, "4e9116f06003e3786318751e1058ffab2427f86e3a602ee77815a73391df2b53"},
+	{ModToolsDLLName, "880aefba928f653f824eb118784182ed6a667c6646e80569f7fc4c8d2f3971fd"},
+}
+
+// doctorProbeChampion es el campeón que se pide al catálogo para ver si responde (Annie)
+const doctorProbeChampion = "1"
+
+// DoctorCheck es una comprobación del diagnóstico
+type DoctorCheck struct {
+	ID      string      `json:"id"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`        // Lo encontrado, en inglés como detail
+	Hint    string      `json:"hint,omitempty"` // Qué hacer, en el idioma activo
+}
+
+// DoctorReport es la respuesta de RunDiagnostics
+type DoctorReport struct {
+	Result
+	CheckedAt string        `json:"checkedAt"`
+	Status    CheckStatus   `json:"status"` // El peor estado de las comprobaciones
+	Checks    []DoctorCheck `json:"checks"`
+}
+
+// RunDiagnostics comprueba mod-tools, la carpeta del juego, los permisos, el
+// backend, installed.json y los procesos de mod-tools. No cambia nada: cada
+// comprobación que no pasa trae una sugerencia.
+func (a *App) RunDiagnostics() DoctorReport {
+	a.logger(LogSubsystemApp).Info("RunDiagnostics: Running health checks")
+	checks := a.checkModToolsFiles()
+	checks = append(checks,
+		checkGameFolder(),
+		checkWritablePaths(),
+		a.checkAuthServer(),
+		a.checkCatalog(),
+		a.checkInstalledConsistency(),
+		a.checkOrphanProcesses(),
+	)
+
+	report := DoctorReport{
+		CheckedAt: time.Now().Format(time.RFC3339),
+		Status:    CheckPass,
+		Checks:    checks,
+	}
+	passed, warned, failed := 0, 0, 0
+	for _, check := range checks {
+		switch check.Status {
+		case CheckPass:
+			passed++
+			continue
+		case CheckWarn:
+			warned++
+		case CheckFail:
+			failed++
+		}
+		a.logger(LogSubsystemApp).Warningf("RunDiagnostics: %s %s: %s", check.ID, check.Status, check.Message)
+	}
+	if failed > 0 {
+		report.Status = CheckFail
+	} else if warned > 0 {
+		report.Status = CheckWarn
+	}
+	report.Result = okResult(MsgDoctorSummary, passed, warned, failed)
+	return report
+}
+
+func passCheck(id, format string, args ...interface{}) DoctorCheck {
+	return DoctorCheck{ID: id, Status: CheckPass, Message: fmt.Sprintf(format, args...)}
+}
+
+// checkModToolsFiles comprueba que los binarios de cslol-tools estén y no hayan
+// cambiado (el antivirus suele borrarlos o ponerlos en cuarentena)
+func (a *App) checkModToolsFiles() []DoctorCheck {
+	checks := make([]DoctorCheck, 0, len(modToolsSHA256))
+	for _, expected := range modToolsSHA256 {
+		id := "modtools:" + expected.Name
+		p := filepath.Join(absBasePath, RelativeModToolsDir, expected.Name)
+		hash, err := fileSHA256(p)
+		switch {
+		case os.IsNotExist(err):
+			checks = append(checks, DoctorCheck{ID: id, Status: CheckFail,
+				Message: fmt.Sprintf("%s not found", p), Hint: tr(HintModToolsMissing)})
+		case err != nil:
+			checks = append(checks, DoctorCheck{ID: id, Status: CheckFail,
+				Message: fmt.Sprintf("Could not read %s: %v", p, err), Hint: tr(HintModToolsMissing)})
+		case hash != expected.SHA256:
+			checks = append(checks, DoctorCheck{ID: id, Status: CheckWarn,
+				Message: fmt.Sprintf("%s has SHA-256 %s, expected %s", expected.Name, hash, expected.SHA256), Hint: tr(HintModToolsModified)})
+		default:
+			checks = append(checks, passCheck(id, "%s is present and unmodified", expected.Name))
+		}
+	}
+	return checks
+}
+
+// checkGameFolder comprueba que la carpeta Game tenga el ejecutable del juego
+func checkGameFolder() DoctorCheck {
+	const id = "game_path"
+	if err := checkGamePath(); err != nil {
+		return DoctorCheck{ID: id, Status: CheckFail, Message: err.Error(), Hint: tr(HintGamePath, GamePath)}
+	}
+	if _, err := os.Stat(filepath.Join(absGamePath, "League of Legends.exe")); err != nil {
+		return DoctorCheck{ID: id, Status: CheckFail,
+			Message: fmt.Sprintf("League of Legends.exe not found in %s", absGamePath), Hint: tr(HintGamePath, GamePath)}
+	}
+	return passCheck(id, "Game folder found at %s", absGamePath)
+}
+
+// checkWritablePaths crea y borra un archivo en las carpetas donde escribe la app
+func checkWritablePaths() DoctorCheck {
+	const id = "base_writable"
+	for _, dir := range []string{absBasePath, absInstalledPath} {
+		tmp, err := os.CreateTemp(dir, ".doctor-*.tmp")
+		if err != nil {
+			return DoctorCheck{ID: id, Status: CheckFail,
+				Message: fmt.Sprintf("Cannot write to %s: %v", dir, err), Hint: tr(HintBaseNotWritable)}
+		}
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	return passCheck(id, "%s is writable", absBasePath)
+}
+
+// checkAuthServer comprueba que responda el servidor de auth del backend
+func (a *App) checkAuthServer() DoctorCheck {
+	const id = "backend_auth"
+	url := a.currentSettings().Backend.SupabaseURL
+	if err := a.backend.Auth().HealthCheck(); err != nil {
+		return DoctorCheck{ID: id, Status: CheckFail,
+			Message: fmt.Sprintf("Auth server at %s is not reachable: %v", url, err), Hint: tr(HintBackendUnreachable)}
+	}
+	return passCheck(id, "Auth server at %s is reachable", url)
+}
+
+// checkCatalog pide un campeón al catálogo con el proveedor de almacenamiento configurado
+func (a *App) checkCatalog() DoctorCheck {
+	const id = "backend_catalog"
+	provider := a.currentSettings().Backend.StorageProvider
+	if _, err := a.backend.Catalog().ChampionJSON(doctorProbeChampion); err != nil {
+		return DoctorCheck{ID: id, Status: CheckFail,
+			Message: fmt.Sprintf("Catalog (%s) is not reachable: %v", provider, err), Hint: tr(HintCatalogUnreachable)}
+	}
+	return passCheck(id, "Catalog (%s) is reachable", provider)
+}
+
+// checkInstalledConsistency compara installed/ con installed.json
+func (a *App) checkInstalledConsistency() DoctorCheck {
+	const id = "installed"
+	report, err := a.reconcileInstalled()
+	if err != nil {
+		return DoctorCheck{ID: id, Status: CheckFail, Message: err.Error(), Hint: tr(HintBaseNotWritable)}
+	}
+	if report.Consistent {
+		return passCheck(id, "installed.json matches installed/ (%d skins)", a.installedSkins.Len())
+	}
+	status := CheckWarn
+	if len(report.MissingFiles) > 0 {
+		status = CheckFail // Skins que el overlay no va a poder cargar
+	}
+	return DoctorCheck{ID: id, Status: status,
+		Message: fmt.Sprintf("%d missing files, %d untracked files, %d leftover folders",
+			len(report.MissingFiles), len(report.UntrackedFiles), len(report.LeftoverDirs)),
+		Hint: tr(HintInstalledInconsistent)}
+}
+
+// checkOrphanProcesses busca procesos de mod-tools.exe que esta instancia no
+// arrancó. Desde la CLI se admite uno, el overlay de la app abierta.
+func (a *App) checkOrphanProcesses() DoctorCheck {
+	const id = "processes"
+	pids, err := modToolsPIDs()
+	if err != nil {
+		return DoctorCheck{ID: id, Status: CheckWarn, Message: fmt.Sprintf("Could not list processes: %v", err)}
+	}
+	tracked := a.trackedModToolsPid()
+	untracked := []string{}
+	for _, pid := range pids {
+		if pid != tracked {
+			untracked = append(untracked, strconv.Itoa(pid))
+		}
+	}
+	allowed := 0
+	if a.headless {
+		allowed = 1
+	}
+	if len(untracked) > allowed {
+		return DoctorCheck{ID: id, Status: CheckWarn,
+			Message: fmt.Sprintf("%d mod-tools.exe processes not started by this app (PID %s)", len(untracked), strings.Join(untracked, ", ")),
+			Hint:    tr(HintOrphanProcesses)}
+	}
+	return passCheck(id, "No orphan mod-tools.exe processes (%d running)", len(pids))
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTasklistPIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []int
+	}{
+		{"none", "INFO: No tasks are running which match the specified criteria.\r\n", nil},
+		{"one", "\"mod-tools.exe\",\"4242\",\"Console\",\"1\",\"12,345 K\"\r\n", []int{4242}},
+		{"two", "\"mod-tools.exe\",\"4242\",\"Console\",\"1\",\"12,345 K\"\r\n\"MOD-TOOLS.EXE\",\"77\",\"Console\",\"1\",\"9,000 K\"\r\n", []int{4242, 77}},
+		{"other image", "\"cmd.exe\",\"10\",\"Console\",\"1\",\"1,000 K\"\r\n", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseTasklistPIDs([]byte(tt.output))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// useDoctorInstall deja en las rutas temporales una instalación sana: mod-tools
+// con hashes conocidos, la carpeta del juego y un catálogo que responde
+func useDoctorInstall(t *testing.T) (*App, *MemoryBackend) {
+	t.Helper()
+	a, backend := newMemoryTestApp(t, 0)
+	backend.PutObject(ChampionJSONBucket, doctorProbeChampion+".json", []byte(`{"skins": []}`))
+
+	savedHashes := modToolsSHA256
+	t.Cleanup(func() { modToolsSHA256 = savedHashes })
+	modToolsSHA256 = nil
+	for _, expected := range savedHashes {
+		p := filepath.Join(absBasePath, RelativeModToolsDir, expected.Name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(expected.Name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := fileSHA256(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		modToolsSHA256 = append(modToolsSHA256, struct {
+			Name   string
+			SHA256 string
+		}{expected.Name, hash})
+	}
+
+	savedGamePath := absGamePath
+	t.Cleanup(func() { absGamePath = savedGamePath })
+	absGamePath = t.TempDir()
+	if err := os.WriteFile(filepath.Join(absGamePath, "League of Legends.exe"), []byte("league"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return a, backend
+}
+
+// doctorChecks indexa las comprobaciones del informe por ID
+func doctorChecks(report DoctorReport) map[string]DoctorCheck {
+	checks := make(map[string]DoctorCheck)
+	for _, check := range report.Checks {
+		checks[check.ID] = check
+	}
+	return checks
+}
+
+// La lista de procesos usa tasklist: fuera de Windows esa comprobación avisa y
+// el resto del informe tiene que pasar igual
+func TestRunDiagnosticsHealthy(t *testing.T) {
+	a, _ := useDoctorInstall(t)
+	report := a.RunDiagnostics()
+	if !report.Success || report.CheckedAt == "" {
+		t.Fatalf("RunDiagnostics = %+v", report.Result)
+	}
+	checks := doctorChecks(report)
+	if len(checks) != len(report.Checks) || len(checks) != len(modToolsSHA256)+6 {
+		t.Errorf("checks = %+v", report.Checks)
+	}
+	for _, check := range report.Checks {
+		if check.ID != "processes" && check.Status != CheckPass {
+			t.Errorf("%s = %s: %s", check.ID, check.Status, check.Message)
+		}
+	}
+	if report.Status != checks["processes"].Status {
+		t.Errorf("status = %s, want %s", report.Status, checks["processes"].Status)
+	}
+}
+
+func TestRunDiagnosticsProblems(t *testing.T) {
+	modToolsDLL := func() string { return filepath.Join(absBasePath, RelativeModToolsDir, ModToolsDLLName) }
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, a *App)
+		id     string
+		status CheckStatus
+	}{
+		{"modified mod-tools", func(t *testing.T, a *App) {
+			if err := os.WriteFile(absModToolsPath, []byte("quarantined"), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}, "modtools:" + ModToolsExeName, CheckWarn},
+		{"missing mod-tools dll", func(t *testing.T, a *App) {
+			if err := os.Remove(modToolsDLL()); err != nil {
+				t.Fatal(err)
+			}
+		}, "modtools:" + ModToolsDLLName, CheckFail},
+		{"missing game exe", func(t *testing.T, a *App) {
+			if err := os.Remove(filepath.Join(absGamePath, "League of Legends.exe")); err != nil {
+				t.Fatal(err)
+			}
+		}, "game_path", CheckFail},
+		{"missing game folder", func(t *testing.T, a *App) {
+			absGamePath = filepath.Join(absGamePath, "missing")
+		}, "game_path", CheckFail},
+		{"unreachable auth server", func(t *testing.T, a *App) {
+			server := httptest.NewServer(nil)
+			server.Close()
+			a.backend = a.backend.(*MemoryBackend).WithAuth(NewAuthService(server.URL, "anon", server.Client()))
+		}, "backend_auth", CheckFail},
+		{"unreachable catalog", func(t *testing.T, a *App) {
+			a.backend = NewMemoryBackend()
+		}, "backend_catalog", CheckFail},
+		{"untracked installed file", func(t *testing.T, a *App) {
+			if err := os.WriteFile(filepath.Join(absInstalledPath, "stray.fantome"), []byte("stray"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}, "installed", CheckWarn},
+		{"installed.json entry without file", func(t *testing.T, a *App) {
+			a.installedSkins.Set("103", SkinInfo{SkinId: "103015", FileName: "ahri.fantome", Source: SkinSourceCatalog})
+			if err := a.saveInstalledSkins(); err != nil {
+				t.Fatal(err)
+			}
+		}, "installed", CheckFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := useDoctorInstall(t)
+			tt.setup(t, a)
+			report := a.RunDiagnostics()
+			if !report.Success {
+				t.Fatalf("RunDiagnostics = %+v", report.Result)
+			}
+			checks := doctorChecks(report)
+			check := checks[tt.id]
+			if check.Status != tt.status || check.Message == "" {
+				t.Errorf("%s = %+v, want %s", tt.id, check, tt.status)
+			}
+			if check.Hint == "" {
+				t.Errorf("%s has no hint", tt.id)
+			}
+			// El informe toma el peor estado; el de procesos como mucho avisa
+			if report.Status != tt.status {
+				t.Errorf("status = %s, want %s", report.Status, tt.status)
+			}
+			for _, other := range report.Checks {
+				if other.ID != tt.id && other.ID != "processes" && other.Status != CheckPass {
+					t.Errorf("%s also failed: %+v", other.ID, other)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DenialReason es el motivo tipado por el que el servidor niega una descarga
+type DenialReason string
+
+const (
+	DenialNotPurchased    DenialReason = "not_purchased"    // La cuenta no compró acceso
+	DenialOutOfTokens     DenialReason = "out_of_tokens"    // No le quedan fichas para skins
+	DenialBanned          DenialReason = "banned"           // Cuenta suspendida
+	DenialEmailUnverified DenialReason = "email_unverified" // Falta confirmar el email
+)
+
+// EntitlementDeniedError indica que el servidor rechazó la descarga por Reason
+type EntitlementDeniedError struct {
+	Reason  DenialReason
+	Message string
+}
+
+func (e *EntitlementDeniedError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	switch e.Reason {
+	case DenialNotPurchased:
+		return "This account has not purchased access"
+	case DenialOutOfTokens:
+		return "No skin tokens left"
+	case DenialBanned:
+		return "This account is suspended"
+	case DenialEmailUnverified:
+		return "Confirm your email before downloading skins"
+	}
+	return fmt.Sprintf("Download denied (%s)", e.Reason)
+}
+
+// SkinGrant es el permiso de descarga que emite el servidor: una URL firmada de vida
+// corta. Con un proveedor de almacenamiento propio URL queda vacía y el archivo se
+// descarga de ese proveedor.
+type SkinGrant struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// EntitlementService decide en el servidor si un usuario puede descargar una skin.
+// El cliente nunca decide por su cuenta; solo recibe una URL firmada o un motivo de rechazo.
+type EntitlementService interface {
+	AuthorizeSkinDownload(accessToken, championId, skinNum string) (*SkinGrant, error)
+}
+
+// SignedURLTTL es la vida de las URLs firmadas para el bucket de skins
+const SignedURLTTL = 60 * time.Second
+
+// SupabaseEntitlements implementa EntitlementService con la función
+// authorize_skin_download de la base y URLs firmadas del bucket privado de skins.
+// La función y las políticas del bucket están en supabase/migrations.
+type SupabaseEntitlements struct {
+	projectURL string
+	apiKey     string
+	httpClient *http.Client
+	ttl        time.Duration
+	bucket     string // Bucket con los .fantome
+	signURLs   bool   // false si el catálogo no está en Supabase Storage
+}
+
+// NewSupabaseEntitlements crea el servicio para el proyecto en projectURL
+func NewSupabaseEntitlements(projectURL, apiKey string, httpClient *http.Client) *SupabaseEntitlements {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &SupabaseEntitlements{
+		projectURL: strings.TrimRight(projectURL, "/"),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+		ttl:        SignedURLTTL,
+		bucket:     CatalogSkinsBucket,
+		signURLs:   true,
+	}
+}
+
+// WithStorage cambia el bucket de las skins. Con signURLs en false solo se
+// verifica el acceso y la descarga queda a cargo del proveedor configurado.
+func (s *SupabaseEntitlements) WithStorage(bucket string, signURLs bool) *SupabaseEntitlements {
+	s.bucket = bucket
+	s.signURLs = signURLs
+	return s
+}
+
+// authorizeResult es la respuesta de authorize_skin_download
+type authorizeResult struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// AuthorizeSkinDownload verifica el acceso en el servidor y, si está permitido,
+// firma la ruta de la skin en el bucket de skins. El bucket no es público y sus
+// políticas RLS repiten la misma comprobación, así que firmar sin permiso falla.
+func (s *SupabaseEntitlements) AuthorizeSkinDownload(accessToken, championId, skinNum string) (*SkinGrant, error) {
+	var result authorizeResult
+	err := callRPC(s.httpClient, s.projectURL, s.apiKey, accessToken, "authorize_skin_download", map[string]string{
+		"p_champion_id": championId,
+		"p_skin_num":    skinNum,
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("entitlement check failed: %v", err)
+	}
+	if !result.Allowed {
+		reason := DenialReason(result.Reason)
+		if reason == "" {
+			reason = DenialNotPurchased
+		}
+		return nil, &EntitlementDeniedError{Reason: reason, Message: result.Message}
+	}
+
+	if !s.signURLs {
+		return &SkinGrant{}, nil
+	}
+	signedURL, err := s.signObject(accessToken, s.bucket, catalogSkinPath(championId, skinNum))
+	if err != nil {
+		return nil, err
+	}
+	return &SkinGrant{URL: signedURL, ExpiresAt: time.Now().Add(s.ttl)}, nil
+}
+
+// signObject pide a Storage una URL firmada para bucket/objectPath con el token del usuario
+func (s *SupabaseEntitlements) signObject(accessToken, bucket, objectPath string) (string, error) {
+	body, _ := json.Marshal(map[string]int{"expiresIn": int(s.ttl.Seconds())})
+	endpoint := fmt.Sprintf("%s/storage/v1/object/sign/%s/%s", s.projectURL, url.PathEscape(bucket), escapeObjectPath(objectPath))
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("apikey", s.apiKey)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error signing skin URL: %v", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error signing skin URL: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	var signed struct {
+		SignedURL string `json:"signedURL"`
+	}
+	if err := json.Unmarshal(respBody, &signed); err != nil || signed.SignedURL == "" {
+		return "", fmt.Errorf("invalid signed URL response")
+	}
+	return s.projectURL + "/storage/v1" + signed.SignedURL, nil
+}
+
+// escapeObjectPath escapa cada segmento de la ruta sin tocar las barras
+func escapeObjectPath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// downloadGrant descarga el archivo de una URL firmada
+func downloadGrant(httpClient *http.Client, grant *SkinGrant) ([]byte, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Get(grant.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading file: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading file: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
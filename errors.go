@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode es el código estable de un error de la app. El frontend decide qué
+// mostrar según el código, no según el texto.
+type ErrorCode string
+
+const (
+	ErrAuthInvalidToken       ErrorCode = "AUTH_INVALID_TOKEN"       // Token ausente, vencido o de otro usuario
+	ErrAuthNotSignedIn        ErrorCode = "AUTH_NOT_SIGNED_IN"       // No hay sesión guardada
+	ErrAuthInvalidCredentials ErrorCode = "AUTH_INVALID_CREDENTIALS" // Login o contraseña incorrectos
+	ErrAuthRateLimited        ErrorCode = "AUTH_RATE_LIMITED"        // Demasiados intentos
+	ErrAuthEmailUnverified    ErrorCode = "AUTH_EMAIL_UNVERIFIED"    // Falta confirmar el email
+	ErrAuthSessionExpired     ErrorCode = "AUTH_SESSION_EXPIRED"     // No se pudo refrescar la sesión
+	ErrAuthUnavailable        ErrorCode = "AUTH_UNAVAILABLE"         // No se pudo contactar al servidor de auth
+
+	ErrValidationFailed ErrorCode = "VALIDATION_FAILED" // Campos inválidos (ver fieldErrors)
+	ErrInvalidArgument  ErrorCode = "INVALID_ARGUMENT"  // Parámetro inválido
+	ErrNotFound         ErrorCode = "NOT_FOUND"         // Skin, backup o usuario inexistente
+
+	ErrEntitlementDenied ErrorCode = "ENTITLEMENT_DENIED" // El servidor negó la descarga (ver reason)
+	ErrOutOfTokens       ErrorCode = "OUT_OF_TOKENS"      // No quedan fichas
+
+	ErrCatalogUnavailable ErrorCode = "CATALOG_UNAVAILABLE"  // No se pudo leer el catálogo
+	ErrDownloadFailed     ErrorCode = "DOWNLOAD_FAILED"      // Falló la descarga de una skin
+	ErrStorageFailed      ErrorCode = "STORAGE_FAILED"       // Falló la lectura o escritura de archivos locales
+	ErrBackupInvalid      ErrorCode = "BACKUP_INVALID"       // El snapshot está dañado o no pasa la validación
+	ErrLoadoutInvalid     ErrorCode = "LOADOUT_INVALID"      // El paquete de loadout no se puede leer
+	ErrLoadoutCostChanged ErrorCode = "LOADOUT_COST_CHANGED" // Aplicar el loadout cuesta más fichas de las aceptadas
+	ErrModInvalid         ErrorCode = "MOD_INVALID"          // El mod local no tiene un formato soportado
+
+	ErrModToolsMissing       ErrorCode = "MODTOOLS_MISSING"        // No está mod-tools.exe
+	ErrModToolsImportFailed  ErrorCode = "MODTOOLS_IMPORT_FAILED"  // Falló "mod-tools import"
+	ErrModToolsOverlayFailed ErrorCode = "MODTOOLS_OVERLAY_FAILED" // Falló "mod-tools mkoverlay"
+	ErrModToolsStartFailed   ErrorCode = "MODTOOLS_START_FAILED"   // No arrancó el overlay
+	ErrModToolsCommandFailed ErrorCode = "MODTOOLS_COMMAND_FAILED" // Falló otro comando de mod-tools
+	ErrOverlayInGame         ErrorCode = "OVERLAY_GAME_IN_PROGRESS"
+	ErrGamePathMissing       ErrorCode = "GAME_PATH_MISSING" // No está la carpeta Game de League
+
+	ErrLCUNotRunning ErrorCode = "LCU_NOT_RUNNING" // El cliente de League no está abierto
+
+	ErrInternal ErrorCode = "INTERNAL"
+)
+
+// AllErrorCodes se registra con EnumBind para que los bindings de TypeScript
+// incluyan el enum ErrorCode
+var AllErrorCodes = []struct {
+	Value  ErrorCode
+	TSName string
+}{
+	{ErrAuthInvalidToken, "AUTH_INVALID_TOKEN"},
+	{ErrAuthNotSignedIn, "AUTH_NOT_SIGNED_IN"},
+	{ErrAuthInvalidCredentials, "AUTH_INVALID_CREDENTIALS"},
+	{ErrAuthRateLimited, "AUTH_RATE_LIMITED"},
+	{ErrAuthEmailUnverified, "AUTH_EMAIL_UNVERIFIED"},
+	{ErrAuthSessionExpired, "AUTH_SESSION_EXPIRED"},
+	{ErrAuthUnavailable, "AUTH_UNAVAILABLE"},
+	{ErrValidationFailed, "VALIDATION_FAILED"},
+	{ErrInvalidArgument, "INVALID_ARGUMENT"},
+	{ErrNotFound, "NOT_FOUND"},
+	{ErrEntitlementDenied, "ENTITLEMENT_DENIED"},
+	{ErrOutOfTokens, "OUT_OF_TOKENS"},
+	{ErrCatalogUnavailable, "CATALOG_UNAVAILABLE"},
+	{ErrDownloadFailed, "DOWNLOAD_FAILED"},
+	{ErrStorageFailed, "STORAGE_FAILED"},
+	{ErrBackupInvalid, "BACKUP_INVALID"},
+	{ErrLoadoutInvalid, "LOADOUT_INVALID"},
+	{ErrLoadoutCostChanged, "LOADOUT_COST_CHANGED"},
+	{ErrModInvalid, "MOD_INVALID"},
+	{ErrModToolsMissing, "MODTOOLS_MISSING"},
+	{ErrModToolsImportFailed, "MODTOOLS_IMPORT_FAILED"},
+	{ErrModToolsOverlayFailed, "MODTOOLS_OVERLAY_FAILED"},
+	{ErrModToolsStartFailed, "MODTOOLS_START_FAILED"},
+	{ErrModToolsCommandFailed, "MODTOOLS_COMMAND_FAILED"},
+	{ErrOverlayInGame, "OVERLAY_GAME_IN_PROGRESS"},
+	{ErrGamePathMissing, "GAME_PATH_MISSING"},
+	{ErrLCUNotRunning, "LCU_NOT_RUNNING"},
+	{ErrInternal, "INTERNAL"},
+}
+
+// AppError es un error de la app con código estable y, opcionalmente, la causa original
+type AppError struct {
+	Code    ErrorCode
+	Message string
+	Cause   error
+}
+
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error { return e.Cause }
+
+// newAppError crea un AppError; cause puede ser nil
+func newAppError(code ErrorCode, message string, cause error) *AppError {
+	return &AppError{Code: code, Message: message, Cause: cause}
+}
+
+// toAppError clasifica err: los AppError se devuelven tal cual y los errores
+// conocidos de los servicios se traducen a su código
+func toAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var denied *EntitlementDeniedError
+	if errors.As(err, &denied) {
+		switch denied.Reason {
+		case DenialOutOfTokens:
+			return newAppError(ErrOutOfTokens, denied.Error(), nil)
+		case DenialEmailUnverified:
+			return newAppError(ErrAuthEmailUnverified, denied.Error(), nil)
+		}
+		return newAppError(ErrEntitlementDenied, denied.Error(), nil)
+	}
+	var throttled *LoginThrottledError
+	if errors.As(err, &throttled) {
+		return newAppError(ErrAuthRateLimited, throttled.Error(), nil)
+	}
+	if errors.Is(err, ErrNoSession) {
+		return newAppError(ErrAuthNotSignedIn, "Not signed in", nil)
+	}
+	return newAppError(ErrInternal, "Unexpected error", err)
+}
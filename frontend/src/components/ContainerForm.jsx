@@ -4,11 +4,25 @@ import { Button, Text, Flex, Card } from "@radix-ui/themes";
 import { PersonIcon, LockClosedIcon, EnvelopeClosedIcon, Cross1Icon } from "@radix-ui/react-icons";
 import { toast } from "sonner";
 import { Login, Register } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { useUser } from '../context/usercontext';
+
+// failureMessage arma el texto de un AuthResult fallido: los errores por campo si los hay
+const failureMessage = (response, fallback) => {
+    if (response.fieldErrors?.length) {
+        return response.fieldErrors.map((fieldError) => fieldError.message).join("\n");
+    }
+    if (response.code === main.ErrorCode.AUTH_RATE_LIMITED && response.retryAfter) {
+        return `Too many attempts. Try again in ${response.retryAfter} seconds.`;
+    }
+    return response.error || fallback;
+};
 
 const ContainerForm = ({ closePopup, setCurrentPopup, currentPopup }) => {
     const [formData, setFormData] = useState({ login: "", password: "", email: "" });
     const [statusMessage, setStatusMessage] = useState("");
     const navigate = useNavigate();
+    const { setUserData } = useUser();
 
     const handleChange = (e) => {
         const { name, value } = e.target;
@@ -22,10 +36,14 @@ const ContainerForm = ({ closePopup, setCurrentPopup, currentPopup }) => {
             if (response.success) {
                 toast.success(response.message);
                 setCurrentPopup("login");
+                setStatusMessage(response.emailVerificationRequired
+                    ? "Check your email to confirm your account, then log in."
+                    : response.message);
             } else {
-                toast.error(response.error || "Registration failed");
+                const message = failureMessage(response, "Registration failed");
+                toast.error(message);
+                setStatusMessage(message);
             }
-            setStatusMessage(response.message);
         } catch (error) {
             console.error(error);
             toast.error("Registration failed: " + (error.message || "Unknown error"));
@@ -37,13 +55,20 @@ const ContainerForm = ({ closePopup, setCurrentPopup, currentPopup }) => {
         try {
             const response = await Login(formData.login, formData.password);
             if (response.success) {
-                localStorage.setItem("token", response.token);
+                // Go guarda la sesión; el token no se copia al frontend
+                setUserData(response.user);
                 toast.success(`Welcome back! ${response.user.login}`);
                 navigate("/home");
                 closePopup();
             } else {
-                toast.error(response.error || "Login failed");
-                setStatusMessage("Unable to log in. Please check your credentials.");
+                toast.error(failureMessage(response, "Login failed"));
+                if (response.emailVerificationRequired) {
+                    setStatusMessage("Check your email to confirm your account, then log in.");
+                } else {
+                    setStatusMessage(response.code === main.ErrorCode.AUTH_INVALID_CREDENTIALS
+                        ? "Unable to log in. Please check your credentials."
+                        : failureMessage(response, "Login failed"));
+                }
             }
         } catch (error) {
             console.error("Login error:", error);
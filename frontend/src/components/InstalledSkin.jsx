@@ -23,12 +23,11 @@ const InstalledSkinsDialog = ({ isOpen, onOpenChange }) => {
   const loadInstalledSkins = async () => {
     setIsLoading(true);
     try {
-      const installedSkinsData = await GetInstalledSkins();
-      console.log("installedSkinsData:", installedSkinsData); // Depuración
-
-      if (!Array.isArray(installedSkinsData)) {
-        throw new Error("GetInstalledSkins did not return an array");
+      const result = await GetInstalledSkins();
+      if (!result.success) {
+        throw new Error(result.error || "Could not read installed skins");
       }
+      const installedSkinsData = result.skins;
 
       const enhancedSkins = installedSkinsData.map(skinInfo => {
         const champion = champions.find(c => String(c.id) === String(skinInfo.championId));
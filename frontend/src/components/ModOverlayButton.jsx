@@ -37,10 +37,10 @@ const ModOverlayButton = () => {
       if (status === "running") {
         // Use KillModTools directly for more reliable stopping
         const result = await KillModTools();
-        if (result) {
+        if (result.success) {
           toast.success("Mod overlay stopped successfully");
         } else {
-          toast.error("Failed to stop mod overlay");
+          toast.error(result.error || "Failed to stop mod overlay");
         }
       } else {
         await toggleOverlay();
@@ -10,7 +10,7 @@ import {
   Text,
   Table
 } from '@radix-ui/themes';
-import React, { useState, useEffect } from 'react';
+import React, { useState } from 'react';
 import { useNavigate } from "react-router-dom";
 import ContainerForm from './ContainerForm';
 import { useUser } from '../context/usercontext';
@@ -74,57 +74,22 @@ function Profile() {
     const [loginOpen, setLoginOpen] = useState(false);
     const [profileOpen, setProfileOpen] = useState(false);
     const [currentPopup, setCurrentPopup] = useState("login");
-    const { userData, setUserData } = useUser();
+    const { userData, isAuthenticated, logout } = useUser();
     const navigate = useNavigate();
-    const [isLoading, setIsLoading] = useState(true);
-  
-    useEffect(() => {
-      const fetchUserData = async () => {
-          const token = localStorage.getItem("token");
-  
-          if (!token) {
-              setIsLoading(false);
-              return;
-          }
-  
-          try {
-              const response = await window.api.getUserData(token);
-              if (response.success) {
-                  setUserData(response.user);
-              } else {
-                  throw new Error(response.message);
-              }
-          } catch (error) {
-              console.error("Error fetching user data:", error);
-              if (error.message === 'No token provided' || error.message === 'Invalid token') {
-                  localStorage.removeItem("token");
-                  setUserData(null);
-              }
-          } finally {
-              setIsLoading(false);
-          }
-      };
-  
-      fetchUserData();
-  }, [setUserData, navigate]);
   
     const handleLogout = () => {
-      localStorage.removeItem("token");
-      setUserData(null);
+      logout();
       setProfileOpen(false);
       navigate("/");
     };
   
     const handleMenuClick = () => {
-      const token = localStorage.getItem("token");
-      if (token) {
+      if (isAuthenticated) {
         navigate("/home");
       } else {
         setLoginOpen(true);
       }
     };
-  
-    const isAuthenticated = !!localStorage.getItem("token");
 
 
 
@@ -133,17 +133,13 @@ const SkinDialog = memo(({ skin, champKey, asset, rarity, chromas }) => {
   useEffect(() => {
     async function checkInstallation() {
       try {
-        const installedSkins = await GetInstalledSkins();
-        if (!installedSkins) {
+        const result = await GetInstalledSkins();
+        if (!result.success) {
+          console.error("Could not read installed skins:", result.error);
           setIsInstalled(false);
           return;
         }
-        if (!Array.isArray(installedSkins)) {
-          console.error("Installed skins data is not an array:", installedSkins);
-          setIsInstalled(false);
-          return;
-        }
-        setIsInstalled(installedSkins.some(installedSkin => 
+        setIsInstalled(result.skins.some(installedSkin => 
           installedSkin.championId === String(Math.floor(skin.id / 1000))
         ));
       } catch (error) {
@@ -4,7 +4,7 @@ import { useUser } from '../context/usercontext';
 import { GetUserData, InstallSkin, DownloadSkin } from '../../wailsjs/go/main/App';
 
 export const useDownloadSkin = () => {
-  const { revalidateUser } = useUser();
+  const { revalidateUser, isAuthenticated } = useUser();
 
   const sanitizeFileName = (name) => {
     return name
@@ -32,9 +32,10 @@ const downloadSkin = async (championId, skinId, setUserData, skin, selectedChrom
   const loadingToast = toast.loading('Processing download...');
 
   try {
-    const token = localStorage.getItem("token");
+    // Los bindings usan la sesión guardada en Go cuando el token va vacío
+    const token = "";
 
-    if (!token) {
+    if (!isAuthenticated) {
       toast.dismiss(loadingToast);
       toast.error("You are not authenticated. Please log in to continue.");
       return;
@@ -43,15 +44,9 @@ const downloadSkin = async (championId, skinId, setUserData, skin, selectedChrom
     const userResponse = await GetUserData(token);
     const userData = userResponse.user;
 
-    if (!userData) {
+    if (!userResponse.success || !userData) {
       toast.dismiss(loadingToast);
-      toast.error("User not found. Please log in again.");
-      return;
-    }
-
-    if (userData.fichasporskin <= 0) {
-      toast.dismiss(loadingToast);
-      toast.error("Insufficient credits. Please purchase more.");
+      toast.error(userResponse.error || "User not found. Please log in again.");
       return;
     }
 
@@ -66,7 +61,6 @@ const downloadSkin = async (championId, skinId, setUserData, skin, selectedChrom
       championId: String(Math.floor(skinId / 1000)),
       skinNum: String(skinNum),
       userId: String(userData.id),
-      token: token,
       skinName: String(skin.name),
       fileName: String(fileName),
       chromaName: chromaName || "",
@@ -74,7 +68,8 @@ const downloadSkin = async (championId, skinId, setUserData, skin, selectedChrom
       baseSkinName: String(baseSkinName),
     });
 
-    // Descargar skin
+    // Descargar skin. Las fichas las cuenta el servidor: una skin ya pagada se vuelve
+    // a descargar sin gastar otra, y sin fichas la respuesta trae reason "out_of_tokens"
     const downloadResponse = await DownloadSkin(
       String(Math.floor(skinId / 1000)),
       String(skinNum),
@@ -91,6 +86,9 @@ const downloadSkin = async (championId, skinId, setUserData, skin, selectedChrom
     if (!downloadResponse.success) {
       throw new Error(downloadResponse.error || "Failed to download skin");
     }
+    if (downloadResponse.alreadyOwned) {
+      toast.info("You already own this skin; no credit was spent.");
+    }
 
     toast.dismiss(loadingToast);
     const installingToast = toast.loading('Installing skin...');
@@ -116,7 +114,7 @@ const downloadSkin = async (championId, skinId, setUserData, skin, selectedChrom
       // Actualizar datos del usuario
       await revalidateUser();
       const refreshedResponse = await GetUserData(token);
-      if (refreshedResponse.user) {
+      if (refreshedResponse.success && refreshedResponse.user) {
         setUserData(refreshedResponse.user);
       }
     } catch (installError) {
@@ -1,9 +1,20 @@
-import { createContext, useContext, useState, useEffect, useRef } from 'react';
+import { createContext, useContext, useState, useEffect, useRef, useCallback } from 'react';
 import { toast } from 'sonner';
-import { GetUserData } from '../../wailsjs/go/main/App';
+import { GetSession, GetUserData, Logout, RefreshSession } from '../../wailsjs/go/main/App';
+import { main } from '../../wailsjs/go/models';
+import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 
 const UserContext = createContext();
 
+// authError convierte una respuesta fallida de Go en un Error con su código
+const authError = (response) => {
+  const error = new Error(response.error || 'Failed to load user data');
+  error.code = response.code;
+  return error;
+};
+
+// La sesión vive del lado de Go (cifrada y con refresco automático); el frontend
+// nunca guarda el token. Los bindings usan la sesión guardada cuando reciben un token vacío.
 export function UserProvider({ children }) {
   const [userData, setUserData] = useState(null);
   const [isLoading, setIsLoading] = useState(true);
@@ -14,17 +25,53 @@ export function UserProvider({ children }) {
     setRevalidationCount(prev => prev + 1);
   };
 
+  const logout = useCallback(async () => {
+    try {
+      await Logout();
+    } catch (error) {
+      console.error('Error logging out:', error);
+    }
+    setUserData(null);
+  }, []);
+
+  // Un token rechazado cierra la sesión; un error de red solo oculta el usuario
+  // y la sesión guardada sigue sirviendo en el próximo intento
+  const dropUser = useCallback((error) => {
+    if (error.code === main.ErrorCode.AUTH_INVALID_TOKEN || error.code === main.ErrorCode.AUTH_SESSION_EXPIRED) {
+      logout();
+    } else {
+      setUserData(null);
+    }
+  }, [logout]);
+
+  useEffect(() => {
+    const onExpired = () => {
+      setUserData(null);
+      toast.error('Session expired. Please log in again.');
+    };
+    EventsOn('session-expired', onExpired);
+    return () => EventsOff('session-expired');
+  }, []);
+
   useEffect(() => {
     const initializeAuth = async () => {
-      const token = localStorage.getItem('token');
-      
-      if (!token) {
+      const session = await GetSession();
+
+      if (!session.authenticated) {
+        setUserData(null);
         setIsLoading(false);
         return;
       }
 
       const fetchUser = async () => {
-        const response = await GetUserData(token);
+        let response = await GetUserData('');
+        if (!response.success && response.code === main.ErrorCode.AUTH_INVALID_TOKEN) {
+          // El access token pudo vencer sin conexión: se refresca una vez y se reintenta
+          const refreshed = await RefreshSession();
+          if (refreshed.success) {
+            response = await GetUserData('');
+          }
+        }
         return { data: response }; // Mantener estructura similar a axios para compatibilidad
       };
 
@@ -40,11 +87,11 @@ export function UserProvider({ children }) {
                   setUserData(response.data.user);
                   return `Welcome back, ${response.data.user.login}!`;
                 }
-                throw new Error(response.data.error || 'Failed to load user data');
+                throw authError(response.data);
               },
               error: (error) => {
                 console.error('Error initializing auth:', error);
-                localStorage.removeItem('token');
+                dropUser(error);
                 return 'Failed to load user data. Please log in again.';
               },
               finally: () => {
@@ -59,11 +106,11 @@ export function UserProvider({ children }) {
           if (response.data.success) {
             setUserData(response.data.user);
           } else {
-            throw new Error(response.data.error || 'Failed to load user data');
+            throw authError(response.data);
           }
         } catch (error) {
           console.error('Error initializing auth:', error);
-          localStorage.removeItem('token');
+          dropUser(error);
           toast.error('Session expired. Please log in again.');
         } finally {
           setIsLoading(false);
@@ -72,13 +119,15 @@ export function UserProvider({ children }) {
     };
 
     initializeAuth();
-  }, [revalidationCount]);
+  }, [revalidationCount, dropUser]);
 
   const value = {
     userData,
     setUserData,
+    isAuthenticated: !!userData,
     isLoading,
-    revalidateUser
+    revalidateUser,
+    logout
   };
 
   return (
@@ -94,4 +143,4 @@ export function useUser() {
     throw new Error('useUser must be used within a UserProvider');
   }
   return context;
-}
\ No newline at end of file
+}
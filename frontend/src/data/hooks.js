@@ -75,8 +75,8 @@ export const useModStatus = () => {
       
     // Check if mod-tools is actually running
     GetModStatus()
-      .then(savedStatus => {
-        if (savedStatus && savedStatus.status === "running") {
+      .then(result => {
+        if (result.modStatus && result.modStatus.status === "running") {
           // Verify if process is actually running
           CheckModToolsRunning()
             .then(isRunning => {
@@ -202,7 +202,7 @@ export const useModStatus = () => {
         if (currentState === "stopped" || currentState === "idle" || currentState === "error") {
           console.log("Attempting to start overlay...");
           const result = await StartRunOverlay(); // StartOverlay now handles cleanup if necessary
-          if (!result.success && result.message !== "Overlay is already running") { // Allow "already running" as success
+          if (!result.success) { // "Already running" comes back as success with alreadyRunning
             throw new Error(result.error || "Failed to start overlay");
           }
            // Event 'overlay-started' should update the status to 'running'
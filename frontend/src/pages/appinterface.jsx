@@ -11,7 +11,7 @@ import { useUser } from '../context/usercontext';
 import { SkinLine, SkinLinesIndex } from '../components/SkinLines';
 import { motion, AnimatePresence } from 'framer-motion';
 import ModOverlayButton from '../components/ModOverlayButton';
-import { GetUserData, GetModStatus } from '../../wailsjs/go/main/App';
+import { GetModStatus } from '../../wailsjs/go/main/App';
 import { EventsOn, EventsOff } from "../../wailsjs/runtime/runtime";
 // Import virtualization and lazy loading components
 import { FixedSizeGrid } from 'react-window';
@@ -270,49 +270,17 @@ const VirtualizedSkinLinesIndex = ({ onSkinLineSelect }) => {
 // Update the MainContent component to use the virtualized components
 const MainContent = ({ activeTab, selectedChampion, setSelectedChampion, selectedSkinLine, setSelectedSkinLine }) => {
   const { champion } = useParams();
-  const { userData, setUserData } = useUser();
-  const { revalidateUser } = useUser();
+  const { userData, logout } = useUser();
 
-  const [isLoading, setIsLoading] = useState(true);
   const handleChampionSelect = (championKey) => {
     setSelectedChampion(championKey);
   };
   const handleSkinLineSelect = (skinLineId) => {
     setSelectedSkinLine(skinLineId);
   };
-  useEffect(() => {
-    const fetchUserData = async () => {
-      const token = localStorage.getItem("token");
-
-      if (!token) {
-        setIsLoading(false);
-        return;
-      }
-
-      try {
-        const response = await GetUserData(token);
-        if (response.success) {
-          setUserData(response.user);
-        } else {
-          throw new Error(response.error || 'Failed to load user data');
-        }
-      } catch (error) {
-        console.error("Error fetching user data:", error);
-        if (error.message.includes('token')) {
-          localStorage.removeItem("token");
-          setUserData(null);
-        }
-      } finally {
-        setIsLoading(false);
-      }
-    };
-
-    fetchUserData();
-  }, [setUserData]);
-
+  // UserProvider carga el usuario desde la sesión guardada en Go
   const handleLogout = () => {
-    localStorage.removeItem("token");
-    setUserData(null);
+    logout();
   };
   switch (activeTab) {
 
@@ -358,7 +326,7 @@ const AppInterface = () => {
   const [activeTab, setActiveTab] = useState('champions');
   const [isOmnisearchOpen, setIsOmnisearchOpen] = useState(false);
   const [currentStatus, setCurrentStatus] = useState('Waiting...');
-  const isAuthenticated = !!localStorage.getItem("token");
+  const { isAuthenticated } = useUser();
   const [loginOpen, setLoginOpen] = useState(false);
   const [profileOpen, setProfileOpen] = useState(false);
   const [currentPopup, setCurrentPopup] = useState("login");
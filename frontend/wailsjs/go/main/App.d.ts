@@ -1,54 +1,123 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {main} from '../models';
 
 export function CheckModToolsRunning():Promise<boolean>;
 
-export function CleanupLocalStorage():Promise<Record<string, any>>;
+export function CleanupLocalStorage():Promise<main.Result>;
 
-export function CleanupTempFiles():Promise<void>;
+export function ConfirmPasswordReset(arg1:string,arg2:string,arg3:string):Promise<main.AuthResult>;
 
-export function DownloadSkin(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string,arg8:string,arg9:string):Promise<Record<string, any>>;
+export function CreateBackup(arg1:string):Promise<main.BackupResult>;
 
-export function FetchChampionJson(arg1:string):Promise<Record<string, any>>;
+export function DeleteBackup(arg1:string):Promise<main.Result>;
 
-export function GetInstalledSkins():Promise<Array<Record<string, any>>>;
+export function DownloadSkin(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string,arg8:string,arg9:string):Promise<main.DownloadResult>;
 
-export function GetModStatus():Promise<any>;
+export function ExportDiagnostics(arg1:string):Promise<main.DiagnosticsResult>;
 
-export function GetUserData(arg1:string):Promise<Record<string, any>>;
+export function ExportLoadout(arg1:string,arg2:boolean):Promise<main.LoadoutResult>;
 
-export function InstallSkin(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<Record<string, any>>;
+export function FetchChampionJson(arg1:string):Promise<main.ChampionResult>;
 
-export function KillModTools():Promise<boolean>;
+export function GetControlAPIStatus():Promise<main.ControlAPIStatus>;
 
-export function LoadInstalledSkins():Promise<void>;
+export function GetGameflowPhase():Promise<string>;
 
-export function Login(arg1:string,arg2:string):Promise<Record<string, any>>;
+export function GetInstalledSkins():Promise<main.InstalledSkinsResult>;
 
-export function Register(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+export function GetLCUStatus():Promise<main.LCUStatus>;
 
-export function RestartModTools():Promise<boolean>;
+export function GetLocaleInfo():Promise<main.LocaleInfo>;
 
-export function RunAndWaitModToolCommand(arg1:string,arg2:Array<string>):Promise<Record<string, any>>;
+export function GetLoginAudit():Promise<Array<main.LoginAuditEntry>>;
 
-export function RunModToolCommand(arg1:string,arg2:Array<string>):Promise<Record<string, any>>;
+export function GetModStatus():Promise<main.ModStatusResult>;
 
-export function RunOverlay(arg1:Array<string>):Promise<Record<string, any>>;
+export function GetOwnedSkins():Promise<main.OwnedSkinsStatus>;
 
-export function SaveInstalledSkins():Promise<void>;
+export function GetRouletteCandidates(arg1:string,arg2:boolean):Promise<main.RouletteResult>;
 
-export function SaveModStatus(arg1:Record<string, any>):Promise<Record<string, any>>;
+export function GetSession():Promise<main.SessionStatus>;
 
-export function StartOverlay():Promise<Record<string, any>>;
+export function GetSettings():Promise<main.Settings>;
 
-export function StartRunOverlay():Promise<Record<string, any>>;
+export function GetTokenHistory():Promise<main.TokenHistoryResult>;
 
-export function StopOverlay():Promise<Record<string, any>>;
+export function GetUserData(arg1:string):Promise<main.AuthResult>;
 
-export function StopRunOverlay():Promise<Record<string, any>>;
+export function ImportLoadout(arg1:string,arg2:string,arg3:string,arg4:boolean,arg5:number):Promise<main.LoadoutResult>;
 
-export function UninstallMultipleSkins(arg1:Array<string>):Promise<Record<string, any>>;
+export function ImportLocalMod(arg1:string):Promise<main.LocalModResult>;
 
-export function UninstallSkin(arg1:string):Promise<Record<string, any>>;
+export function InstallSkin(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<main.Result>;
 
-export function UpdateUserData(arg1:string,arg2:Record<string, any>):Promise<void>;
+export function KillModTools():Promise<main.OverlayResult>;
+
+export function ListBackups():Promise<main.BackupListResult>;
+
+export function Login(arg1:string,arg2:string):Promise<main.AuthResult>;
+
+export function Logout():Promise<main.Result>;
+
+export function PreviewLoadout(arg1:string,arg2:string):Promise<main.LoadoutResult>;
+
+export function ReconcileInstalled():Promise<main.ReconcileResult>;
+
+export function RefreshOwnedSkins():Promise<main.Result>;
+
+export function RefreshSession():Promise<main.AuthResult>;
+
+export function RegenerateControlAPIToken():Promise<main.ControlAPIStatus>;
+
+export function Register(arg1:string,arg2:string,arg3:string):Promise<main.AuthResult>;
+
+export function RepairInstalled(arg1:main.RepairRequest):Promise<main.Result>;
+
+export function RequestPasswordReset(arg1:string):Promise<main.AuthResult>;
+
+export function ResendVerification(arg1:string):Promise<main.AuthResult>;
+
+export function RestartModTools():Promise<main.OverlayResult>;
+
+export function RestoreBackup(arg1:string):Promise<main.BackupResult>;
+
+export function RunAndWaitModToolCommand(arg1:string,arg2:Array<string>):Promise<main.CommandResult>;
+
+export function RunDiagnostics():Promise<main.DoctorReport>;
+
+export function RunModToolCommand(arg1:string,arg2:Array<string>):Promise<main.CommandResult>;
+
+export function RunOverlay(arg1:Array<string>):Promise<main.OverlayResult>;
+
+export function SaveModStatus(arg1:main.ModStatus):Promise<main.Result>;
+
+export function SelectDiagnosticsSavePath():Promise<string>;
+
+export function SelectLoadoutFile():Promise<string>;
+
+export function SelectLoadoutSavePath():Promise<string>;
+
+export function SelectLocalMod():Promise<string>;
+
+export function SelectLocalModFolder():Promise<string>;
+
+export function SpinRoulette(arg1:main.RouletteOptions):Promise<main.RouletteResult>;
+
+export function StartOverlay():Promise<main.OverlayResult>;
+
+export function StartRunOverlay():Promise<main.OverlayResult>;
+
+export function StopOverlay():Promise<main.OverlayResult>;
+
+export function StopRunOverlay():Promise<main.OverlayResult>;
+
+export function UninstallMultipleSkins(arg1:Array<string>):Promise<main.Result>;
+
+export function UninstallSkin(arg1:string):Promise<main.Result>;
+
+export function UpdateProfile(arg1:string,arg2:main.ProfileUpdate):Promise<main.AuthResult>;
+
+export function UpdateSettings(arg1:main.Settings):Promise<main.SettingsResult>;
+
+export function VerifyEmail(arg1:string,arg2:string):Promise<main.AuthResult>;
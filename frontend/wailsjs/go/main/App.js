@@ -10,30 +10,94 @@ export function CleanupLocalStorage() {
   return window['go']['main']['App']['CleanupLocalStorage']();
 }
 
-export function CleanupTempFiles() {
-  return window['go']['main']['App']['CleanupTempFiles']();
+export function ConfirmPasswordReset(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ConfirmPasswordReset'](arg1, arg2, arg3);
+}
+
+export function CreateBackup(arg1) {
+  return window['go']['main']['App']['CreateBackup'](arg1);
+}
+
+export function DeleteBackup(arg1) {
+  return window['go']['main']['App']['DeleteBackup'](arg1);
 }
 
 export function DownloadSkin(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9) {
   return window['go']['main']['App']['DownloadSkin'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9);
 }
 
+export function ExportDiagnostics(arg1) {
+  return window['go']['main']['App']['ExportDiagnostics'](arg1);
+}
+
+export function ExportLoadout(arg1, arg2) {
+  return window['go']['main']['App']['ExportLoadout'](arg1, arg2);
+}
+
 export function FetchChampionJson(arg1) {
   return window['go']['main']['App']['FetchChampionJson'](arg1);
 }
 
+export function GetControlAPIStatus() {
+  return window['go']['main']['App']['GetControlAPIStatus']();
+}
+
+export function GetGameflowPhase() {
+  return window['go']['main']['App']['GetGameflowPhase']();
+}
+
 export function GetInstalledSkins() {
   return window['go']['main']['App']['GetInstalledSkins']();
 }
 
+export function GetLCUStatus() {
+  return window['go']['main']['App']['GetLCUStatus']();
+}
+
+export function GetLocaleInfo() {
+  return window['go']['main']['App']['GetLocaleInfo']();
+}
+
+export function GetLoginAudit() {
+  return window['go']['main']['App']['GetLoginAudit']();
+}
+
 export function GetModStatus() {
   return window['go']['main']['App']['GetModStatus']();
 }
 
+export function GetOwnedSkins() {
+  return window['go']['main']['App']['GetOwnedSkins']();
+}
+
+export function GetRouletteCandidates(arg1, arg2) {
+  return window['go']['main']['App']['GetRouletteCandidates'](arg1, arg2);
+}
+
+export function GetSession() {
+  return window['go']['main']['App']['GetSession']();
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetTokenHistory() {
+  return window['go']['main']['App']['GetTokenHistory']();
+}
+
 export function GetUserData(arg1) {
   return window['go']['main']['App']['GetUserData'](arg1);
 }
 
+export function ImportLoadout(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ImportLoadout'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function ImportLocalMod(arg1) {
+  return window['go']['main']['App']['ImportLocalMod'](arg1);
+}
+
 export function InstallSkin(arg1, arg2, arg3, arg4, arg5, arg6) {
   return window['go']['main']['App']['InstallSkin'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
@@ -42,26 +106,70 @@ export function KillModTools() {
   return window['go']['main']['App']['KillModTools']();
 }
 
-export function LoadInstalledSkins() {
-  return window['go']['main']['App']['LoadInstalledSkins']();
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
 }
 
 export function Login(arg1, arg2) {
   return window['go']['main']['App']['Login'](arg1, arg2);
 }
 
+export function Logout() {
+  return window['go']['main']['App']['Logout']();
+}
+
+export function PreviewLoadout(arg1, arg2) {
+  return window['go']['main']['App']['PreviewLoadout'](arg1, arg2);
+}
+
+export function ReconcileInstalled() {
+  return window['go']['main']['App']['ReconcileInstalled']();
+}
+
+export function RefreshOwnedSkins() {
+  return window['go']['main']['App']['RefreshOwnedSkins']();
+}
+
+export function RefreshSession() {
+  return window['go']['main']['App']['RefreshSession']();
+}
+
+export function RegenerateControlAPIToken() {
+  return window['go']['main']['App']['RegenerateControlAPIToken']();
+}
+
 export function Register(arg1, arg2, arg3) {
   return window['go']['main']['App']['Register'](arg1, arg2, arg3);
 }
 
+export function RepairInstalled(arg1) {
+  return window['go']['main']['App']['RepairInstalled'](arg1);
+}
+
+export function RequestPasswordReset(arg1) {
+  return window['go']['main']['App']['RequestPasswordReset'](arg1);
+}
+
+export function ResendVerification(arg1) {
+  return window['go']['main']['App']['ResendVerification'](arg1);
+}
+
 export function RestartModTools() {
   return window['go']['main']['App']['RestartModTools']();
 }
 
+export function RestoreBackup(arg1) {
+  return window['go']['main']['App']['RestoreBackup'](arg1);
+}
+
 export function RunAndWaitModToolCommand(arg1, arg2) {
   return window['go']['main']['App']['RunAndWaitModToolCommand'](arg1, arg2);
 }
 
+export function RunDiagnostics() {
+  return window['go']['main']['App']['RunDiagnostics']();
+}
+
 export function RunModToolCommand(arg1, arg2) {
   return window['go']['main']['App']['RunModToolCommand'](arg1, arg2);
 }
@@ -70,14 +178,34 @@ export function RunOverlay(arg1) {
   return window['go']['main']['App']['RunOverlay'](arg1);
 }
 
-export function SaveInstalledSkins() {
-  return window['go']['main']['App']['SaveInstalledSkins']();
-}
-
 export function SaveModStatus(arg1) {
   return window['go']['main']['App']['SaveModStatus'](arg1);
 }
 
+export function SelectDiagnosticsSavePath() {
+  return window['go']['main']['App']['SelectDiagnosticsSavePath']();
+}
+
+export function SelectLoadoutFile() {
+  return window['go']['main']['App']['SelectLoadoutFile']();
+}
+
+export function SelectLoadoutSavePath() {
+  return window['go']['main']['App']['SelectLoadoutSavePath']();
+}
+
+export function SelectLocalMod() {
+  return window['go']['main']['App']['SelectLocalMod']();
+}
+
+export function SelectLocalModFolder() {
+  return window['go']['main']['App']['SelectLocalModFolder']();
+}
+
+export function SpinRoulette(arg1) {
+  return window['go']['main']['App']['SpinRoulette'](arg1);
+}
+
 export function StartOverlay() {
   return window['go']['main']['App']['StartOverlay']();
 }
@@ -102,6 +230,14 @@ export function UninstallSkin(arg1) {
   return window['go']['main']['App']['UninstallSkin'](arg1);
 }
 
-export function UpdateUserData(arg1, arg2) {
-  return window['go']['main']['App']['UpdateUserData'](arg1, arg2);
+export function UpdateProfile(arg1, arg2) {
+  return window['go']['main']['App']['UpdateProfile'](arg1, arg2);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
+export function VerifyEmail(arg1, arg2) {
+  return window['go']['main']['App']['VerifyEmail'](arg1, arg2);
 }
@@ -0,0 +1,1482 @@
+export namespace main {
+	
+	export enum ErrorCode {
+	    AUTH_INVALID_TOKEN = "AUTH_INVALID_TOKEN",
+	    AUTH_NOT_SIGNED_IN = "AUTH_NOT_SIGNED_IN",
+	    AUTH_INVALID_CREDENTIALS = "AUTH_INVALID_CREDENTIALS",
+	    AUTH_RATE_LIMITED = "AUTH_RATE_LIMITED",
+	    AUTH_EMAIL_UNVERIFIED = "AUTH_EMAIL_UNVERIFIED",
+	    AUTH_SESSION_EXPIRED = "AUTH_SESSION_EXPIRED",
+	    AUTH_UNAVAILABLE = "AUTH_UNAVAILABLE",
+	    VALIDATION_FAILED = "VALIDATION_FAILED",
+	    INVALID_ARGUMENT = "INVALID_ARGUMENT",
+	    NOT_FOUND = "NOT_FOUND",
+	    ENTITLEMENT_DENIED = "ENTITLEMENT_DENIED",
+	    OUT_OF_TOKENS = "OUT_OF_TOKENS",
+	    CATALOG_UNAVAILABLE = "CATALOG_UNAVAILABLE",
+	    DOWNLOAD_FAILED = "DOWNLOAD_FAILED",
+	    STORAGE_FAILED = "STORAGE_FAILED",
+	    BACKUP_INVALID = "BACKUP_INVALID",
+	    LOADOUT_INVALID = "LOADOUT_INVALID",
+	    LOADOUT_COST_CHANGED = "LOADOUT_COST_CHANGED",
+	    MOD_INVALID = "MOD_INVALID",
+	    MODTOOLS_MISSING = "MODTOOLS_MISSING",
+	    MODTOOLS_IMPORT_FAILED = "MODTOOLS_IMPORT_FAILED",
+	    MODTOOLS_OVERLAY_FAILED = "MODTOOLS_OVERLAY_FAILED",
+	    MODTOOLS_START_FAILED = "MODTOOLS_START_FAILED",
+	    MODTOOLS_COMMAND_FAILED = "MODTOOLS_COMMAND_FAILED",
+	    OVERLAY_GAME_IN_PROGRESS = "OVERLAY_GAME_IN_PROGRESS",
+	    GAME_PATH_MISSING = "GAME_PATH_MISSING",
+	    LCU_NOT_RUNNING = "LCU_NOT_RUNNING",
+	    INTERNAL = "INTERNAL",
+	}
+	export class FieldError {
+	    field: string;
+	    code: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
+	export class UserProfile {
+	    id: string;
+	    login: string;
+	    fichasporskin: number;
+	    escomprador: boolean;
+	    email?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.login = source["login"];
+	        this.fichasporskin = source["fichasporskin"];
+	        this.escomprador = source["escomprador"];
+	        this.email = source["email"];
+	    }
+	}
+	export class AuthResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    token?: string;
+	    expiresAt?: number;
+	    user?: UserProfile;
+	    fieldErrors?: FieldError[];
+	    retryAfter?: number;
+	    emailVerificationRequired?: boolean;
+	    pendingEmail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuthResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.token = source["token"];
+	        this.expiresAt = source["expiresAt"];
+	        this.user = this.convertValues(source["user"], UserProfile);
+	        this.fieldErrors = this.convertValues(source["fieldErrors"], FieldError);
+	        this.retryAfter = source["retryAfter"];
+	        this.emailVerificationRequired = source["emailVerificationRequired"];
+	        this.pendingEmail = source["pendingEmail"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class S3Settings {
+	    endpoint: string;
+	    region: string;
+	    accessKeyId: string;
+	    secretAccessKey: string;
+	    pathStyle: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new S3Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoint = source["endpoint"];
+	        this.region = source["region"];
+	        this.accessKeyId = source["accessKeyId"];
+	        this.secretAccessKey = source["secretAccessKey"];
+	        this.pathStyle = source["pathStyle"];
+	    }
+	}
+	export class BackendSettings {
+	    supabaseUrl: string;
+	    supabaseAnonKey: string;
+	    storageProvider: string;
+	    skinsBucket: string;
+	    championJsonBucket: string;
+	    s3: S3Settings;
+	    httpBaseUrl: string;
+	    localDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackendSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.supabaseUrl = source["supabaseUrl"];
+	        this.supabaseAnonKey = source["supabaseAnonKey"];
+	        this.storageProvider = source["storageProvider"];
+	        this.skinsBucket = source["skinsBucket"];
+	        this.championJsonBucket = source["championJsonBucket"];
+	        this.s3 = this.convertValues(source["s3"], S3Settings);
+	        this.httpBaseUrl = source["httpBaseUrl"];
+	        this.localDir = source["localDir"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupInfo {
+	    name: string;
+	    createdAt: string;
+	    note: string;
+	    automatic?: boolean;
+	    fileCount: number;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.createdAt = source["createdAt"];
+	        this.note = source["note"];
+	        this.automatic = source["automatic"];
+	        this.fileCount = source["fileCount"];
+	        this.size = source["size"];
+	    }
+	}
+	export class BackupListResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    backups: BackupInfo[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupListResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.backups = this.convertValues(source["backups"], BackupInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    backup?: BackupInfo;
+	    previousDataAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.backup = this.convertValues(source["backup"], BackupInfo);
+	        this.previousDataAt = source["previousDataAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChampionResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    data?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChampionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.data = source["data"];
+	    }
+	}
+	export class CommandResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    output?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommandResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.output = source["output"];
+	    }
+	}
+	export class ControlAPISettings {
+	    enabled: boolean;
+	    port: number;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ControlAPISettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	    }
+	}
+	export class ControlAPIStatus {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    enabled: boolean;
+	    running: boolean;
+	    url: string;
+	    eventsUrl: string;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ControlAPIStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.enabled = source["enabled"];
+	        this.running = source["running"];
+	        this.url = source["url"];
+	        this.eventsUrl = source["eventsUrl"];
+	        this.token = source["token"];
+	    }
+	}
+	export class DiagnosticsResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    path?: string;
+	    files?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DiagnosticsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.path = source["path"];
+	        this.files = source["files"];
+	    }
+	}
+	export class DoctorCheck {
+	    id: string;
+	    status: string;
+	    message: string;
+	    hint?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DoctorCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.status = source["status"];
+	        this.message = source["message"];
+	        this.hint = source["hint"];
+	    }
+	}
+	export class DoctorReport {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    checkedAt: string;
+	    status: string;
+	    checks: DoctorCheck[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DoctorReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.checkedAt = source["checkedAt"];
+	        this.status = source["status"];
+	        this.checks = this.convertValues(source["checks"], DoctorCheck);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DownloadResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    remaining: number;
+	    reason?: string;
+	    alreadyOwned?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DownloadResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.remaining = source["remaining"];
+	        this.reason = source["reason"];
+	        this.alreadyOwned = source["alreadyOwned"];
+	    }
+	}
+	
+	export class InstalledSkinRecord {
+	    championId: string;
+	    skinId: string;
+	    fileName: string;
+	    processId: string;
+	    chromaName: string;
+	    skinName: string;
+	    imageUrl: string;
+	    source: string;
+	    author: string;
+	    version: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstalledSkinRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.championId = source["championId"];
+	        this.skinId = source["skinId"];
+	        this.fileName = source["fileName"];
+	        this.processId = source["processId"];
+	        this.chromaName = source["chromaName"];
+	        this.skinName = source["skinName"];
+	        this.imageUrl = source["imageUrl"];
+	        this.source = source["source"];
+	        this.author = source["author"];
+	        this.version = source["version"];
+	    }
+	}
+	export class InstalledSkinsResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    skins: InstalledSkinRecord[];
+	
+	    static createFrom(source: any = {}) {
+	        return new InstalledSkinsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.skins = this.convertValues(source["skins"], InstalledSkinRecord);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LCUStatus {
+	    connected: boolean;
+	    lockedChampion: number;
+	    autoApply: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LCUStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connected = source["connected"];
+	        this.lockedChampion = source["lockedChampion"];
+	        this.autoApply = source["autoApply"];
+	    }
+	}
+	export class LoadoutSkin {
+	    order: number;
+	    championId: string;
+	    skinId: string;
+	    skinName: string;
+	    chromaName: string;
+	    imageUrl: string;
+	    fileName: string;
+	    source: string;
+	    author?: string;
+	    version?: string;
+	    catalogPath?: string;
+	    bundledFile?: string;
+	    sha256?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoadoutSkin(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.order = source["order"];
+	        this.championId = source["championId"];
+	        this.skinId = source["skinId"];
+	        this.skinName = source["skinName"];
+	        this.chromaName = source["chromaName"];
+	        this.imageUrl = source["imageUrl"];
+	        this.fileName = source["fileName"];
+	        this.source = source["source"];
+	        this.author = source["author"];
+	        this.version = source["version"];
+	        this.catalogPath = source["catalogPath"];
+	        this.bundledFile = source["bundledFile"];
+	        this.sha256 = source["sha256"];
+	    }
+	}
+	export class SkinInfo {
+	    skinId: string;
+	    fileName: string;
+	    processId: string;
+	    chromaName: string;
+	    skinName: string;
+	    imageUrl: string;
+	    source: string;
+	    author: string;
+	    version: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkinInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.skinId = source["skinId"];
+	        this.fileName = source["fileName"];
+	        this.processId = source["processId"];
+	        this.chromaName = source["chromaName"];
+	        this.skinName = source["skinName"];
+	        this.imageUrl = source["imageUrl"];
+	        this.source = source["source"];
+	        this.author = source["author"];
+	        this.version = source["version"];
+	    }
+	}
+	export class LoadoutChange {
+	    championId: string;
+	    current: SkinInfo;
+	    incoming: LoadoutSkin;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoadoutChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.championId = source["championId"];
+	        this.current = this.convertValues(source["current"], SkinInfo);
+	        this.incoming = this.convertValues(source["incoming"], LoadoutSkin);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LoadoutUnavailable {
+	    skin: LoadoutSkin;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoadoutUnavailable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.skin = this.convertValues(source["skin"], LoadoutSkin);
+	        this.reason = source["reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LoadoutDiff {
+	    add: LoadoutSkin[];
+	    replace: LoadoutChange[];
+	    unchanged: LoadoutSkin[];
+	    unavailable: LoadoutUnavailable[];
+	    notInLoadout: string[];
+	    alreadyOwned: LoadoutSkin[];
+	    tokenCost: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoadoutDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.add = this.convertValues(source["add"], LoadoutSkin);
+	        this.replace = this.convertValues(source["replace"], LoadoutChange);
+	        this.unchanged = this.convertValues(source["unchanged"], LoadoutSkin);
+	        this.unavailable = this.convertValues(source["unavailable"], LoadoutUnavailable);
+	        this.notInLoadout = source["notInLoadout"];
+	        this.alreadyOwned = this.convertValues(source["alreadyOwned"], LoadoutSkin);
+	        this.tokenCost = source["tokenCost"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LoadoutResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    path?: string;
+	    diff?: LoadoutDiff;
+	    failed?: LoadoutUnavailable[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LoadoutResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.path = source["path"];
+	        this.diff = this.convertValues(source["diff"], LoadoutDiff);
+	        this.failed = this.convertValues(source["failed"], LoadoutUnavailable);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class LocalModResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    championId?: string;
+	    skin?: SkinInfo;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocalModResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.championId = source["championId"];
+	        this.skin = this.convertValues(source["skin"], SkinInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LocaleInfo {
+	    setting: string;
+	    active: string;
+	    system: string;
+	    available: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LocaleInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.setting = source["setting"];
+	        this.active = source["active"];
+	        this.system = source["system"];
+	        this.available = source["available"];
+	    }
+	}
+	export class LogSettings {
+	    level: string;
+	    subsystems: Record<string, string>;
+	    maxSizeMb: number;
+	    maxAgeDays: number;
+	    maxFiles: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.subsystems = source["subsystems"];
+	        this.maxSizeMb = source["maxSizeMb"];
+	        this.maxAgeDays = source["maxAgeDays"];
+	        this.maxFiles = source["maxFiles"];
+	    }
+	}
+	export class LoginAuditEntry {
+	    time: string;
+	    login: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoginAuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.login = source["login"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class MissingSkinFile {
+	    championId: string;
+	    skinId: string;
+	    fileName: string;
+	    skinName: string;
+	    chromaName: string;
+	    actions: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MissingSkinFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.championId = source["championId"];
+	        this.skinId = source["skinId"];
+	        this.fileName = source["fileName"];
+	        this.skinName = source["skinName"];
+	        this.chromaName = source["chromaName"];
+	        this.actions = source["actions"];
+	    }
+	}
+	export class ModStatus {
+	    status: string;
+	    isDisabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.isDisabled = source["isDisabled"];
+	    }
+	}
+	export class ModStatusResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    modStatus?: ModStatus;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModStatusResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.modStatus = this.convertValues(source["modStatus"], ModStatus);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OverlayPolicy {
+	    startPhase: string;
+	    afterGame: string;
+	    protectInGame: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new OverlayPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startPhase = source["startPhase"];
+	        this.afterGame = source["afterGame"];
+	        this.protectInGame = source["protectInGame"];
+	    }
+	}
+	export class OverlayResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    pid?: number;
+	    alreadyRunning?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new OverlayResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.pid = source["pid"];
+	        this.alreadyRunning = source["alreadyRunning"];
+	    }
+	}
+	export class OwnedSkinsStatus {
+	    available: boolean;
+	    skinIds: number[];
+	    hideOwned: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new OwnedSkinsStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.available = source["available"];
+	        this.skinIds = source["skinIds"];
+	        this.hideOwned = source["hideOwned"];
+	    }
+	}
+	export class ProfileUpdate {
+	    displayName: string;
+	    email: string;
+	    currentPassword: string;
+	    newPassword: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileUpdate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.displayName = source["displayName"];
+	        this.email = source["email"];
+	        this.currentPassword = source["currentPassword"];
+	        this.newPassword = source["newPassword"];
+	    }
+	}
+	export class StrayEntry {
+	    name: string;
+	    size: number;
+	    actions: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StrayEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.actions = source["actions"];
+	    }
+	}
+	export class ReconcileReport {
+	    checkedAt: string;
+	    consistent: boolean;
+	    missingFiles: MissingSkinFile[];
+	    untrackedFiles: StrayEntry[];
+	    leftoverDirs: StrayEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReconcileReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.checkedAt = source["checkedAt"];
+	        this.consistent = source["consistent"];
+	        this.missingFiles = this.convertValues(source["missingFiles"], MissingSkinFile);
+	        this.untrackedFiles = this.convertValues(source["untrackedFiles"], StrayEntry);
+	        this.leftoverDirs = this.convertValues(source["leftoverDirs"], StrayEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReconcileResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    report?: ReconcileReport;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReconcileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.report = this.convertValues(source["report"], ReconcileReport);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepairRequest {
+	    action: string;
+	    target: string;
+	    championId: string;
+	    skinId: string;
+	    skinName: string;
+	    userId: string;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepairRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.target = source["target"];
+	        this.championId = source["championId"];
+	        this.skinId = source["skinId"];
+	        this.skinName = source["skinName"];
+	        this.userId = source["userId"];
+	        this.token = source["token"];
+	    }
+	}
+	export class Result {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	    }
+	}
+	export class RouletteCandidate {
+	    championId: string;
+	    skinId: string;
+	    skinName: string;
+	    chromaName: string;
+	    fileName: string;
+	    imageUrl: string;
+	    source: string;
+	    installed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RouletteCandidate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.championId = source["championId"];
+	        this.skinId = source["skinId"];
+	        this.skinName = source["skinName"];
+	        this.chromaName = source["chromaName"];
+	        this.fileName = source["fileName"];
+	        this.imageUrl = source["imageUrl"];
+	        this.source = source["source"];
+	        this.installed = source["installed"];
+	    }
+	}
+	export class RouletteOptions {
+	    championId: string;
+	    allChampions: boolean;
+	    includeCatalog: boolean;
+	    includeChromas?: boolean;
+	    excludeRecent?: number;
+	    weights: Record<string, number>;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RouletteOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.championId = source["championId"];
+	        this.allChampions = source["allChampions"];
+	        this.includeCatalog = source["includeCatalog"];
+	        this.includeChromas = source["includeChromas"];
+	        this.excludeRecent = source["excludeRecent"];
+	        this.weights = source["weights"];
+	        this.token = source["token"];
+	    }
+	}
+	export class RouletteResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    candidates?: RouletteCandidate[];
+	    recent?: string[];
+	    picks?: RouletteCandidate[];
+	    failed?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new RouletteResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.candidates = this.convertValues(source["candidates"], RouletteCandidate);
+	        this.recent = source["recent"];
+	        this.picks = this.convertValues(source["picks"], RouletteCandidate);
+	        this.failed = source["failed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RouletteSettings {
+	    onLockIn: boolean;
+	    excludeRecent: number;
+	    includeChromas: boolean;
+	    weights: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new RouletteSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.onLockIn = source["onLockIn"];
+	        this.excludeRecent = source["excludeRecent"];
+	        this.includeChromas = source["includeChromas"];
+	        this.weights = source["weights"];
+	    }
+	}
+	
+	export class SessionStatus {
+	    authenticated: boolean;
+	    userId?: string;
+	    email?: string;
+	    expiresAt?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.authenticated = source["authenticated"];
+	        this.userId = source["userId"];
+	        this.email = source["email"];
+	        this.expiresAt = source["expiresAt"];
+	    }
+	}
+	export class Settings {
+	    backupRetention: number;
+	    backend: BackendSettings;
+	    autoApplyOnLockIn: boolean;
+	    overlay: OverlayPolicy;
+	    hideOwnedSkins: boolean;
+	    roulette: RouletteSettings;
+	    controlApi: ControlAPISettings;
+	    language: string;
+	    logging: LogSettings;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backupRetention = source["backupRetention"];
+	        this.backend = this.convertValues(source["backend"], BackendSettings);
+	        this.autoApplyOnLockIn = source["autoApplyOnLockIn"];
+	        this.overlay = this.convertValues(source["overlay"], OverlayPolicy);
+	        this.hideOwnedSkins = source["hideOwnedSkins"];
+	        this.roulette = this.convertValues(source["roulette"], RouletteSettings);
+	        this.controlApi = this.convertValues(source["controlApi"], ControlAPISettings);
+	        this.language = source["language"];
+	        this.logging = this.convertValues(source["logging"], LogSettings);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SettingsResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    settings?: Settings;
+	    restartRequired?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.settings = this.convertValues(source["settings"], Settings);
+	        this.restartRequired = source["restartRequired"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class TokenLedgerEntry {
+	    idempotencyKey: string;
+	    reservationId: string;
+	    userId: string;
+	    championId: string;
+	    skinId: string;
+	    skinName: string;
+	    chromaName: string;
+	    fileName: string;
+	    status: string;
+	    remaining: number;
+	    error?: string;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TokenLedgerEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.idempotencyKey = source["idempotencyKey"];
+	        this.reservationId = source["reservationId"];
+	        this.userId = source["userId"];
+	        this.championId = source["championId"];
+	        this.skinId = source["skinId"];
+	        this.skinName = source["skinName"];
+	        this.chromaName = source["chromaName"];
+	        this.fileName = source["fileName"];
+	        this.status = source["status"];
+	        this.remaining = source["remaining"];
+	        this.error = source["error"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class TokenHistoryResult {
+	    success: boolean;
+	    code?: ErrorCode;
+	    error?: string;
+	    detail?: string;
+	    message?: string;
+	    entries: TokenLedgerEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TokenHistoryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.code = source["code"];
+	        this.error = source["error"];
+	        this.detail = source["detail"];
+	        this.message = source["message"];
+	        this.entries = this.convertValues(source["entries"], TokenLedgerEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Fases de gameflow del cliente de League que usa la app
+const (
+	PhaseNone            = "None"
+	PhaseLobby           = "Lobby"
+	PhaseMatchmaking     = "Matchmaking"
+	PhaseReadyCheck      = "ReadyCheck"
+	PhaseChampSelect     = "ChampSelect"
+	PhaseGameStart       = "GameStart"
+	PhaseInProgress      = "InProgress"
+	PhaseReconnect       = "Reconnect"
+	PhaseWaitingForStats = "WaitingForStats"
+	PhasePreEndOfGame    = "PreEndOfGame"
+	PhaseEndOfGame       = "EndOfGame"
+)
+
+const (
+	gameflowEvent = "OnJsonApiEvent_lol-gameflow_v1_gameflow-phase"
+	gameflowURI   = "/lol-gameflow/v1/gameflow-phase"
+)
+
+// Cuándo arrancar el overlay automáticamente
+const (
+	OverlayStartManual      = "manual"      // Solo a mano
+	OverlayStartLobby       = "lobby"       // Al entrar a una sala o cola
+	OverlayStartChampSelect = "champselect" // Al empezar la selección de campeones
+)
+
+// Qué hacer con el overlay cuando termina la partida
+const (
+	OverlayAfterGameKeep    = "keep"    // Dejarlo corriendo
+	OverlayAfterGameStop    = "stop"    // Detenerlo
+	OverlayAfterGameRebuild = "rebuild" // Rearmarlo con todas las skins y reiniciarlo
+)
+
+// OverlayPolicy son las reglas del ciclo de vida del overlay según la fase de la partida
+type OverlayPolicy struct {
+	StartPhase    string `json:"startPhase"`    // OverlayStart*
+	AfterGame     string `json:"afterGame"`     // OverlayAfterGame*
+	ProtectInGame bool   `json:"protectInGame"` // No dejar detenerlo durante una partida
+}
+
+// defaultOverlayPolicy arranca en la selección, lo protege en partida y lo rearma al final
+func defaultOverlayPolicy() OverlayPolicy {
+	return OverlayPolicy{
+		StartPhase:    OverlayStartChampSelect,
+		AfterGame:     OverlayAfterGameRebuild,
+		ProtectInGame: true,
+	}
+}
+
+// normalize reemplaza valores desconocidos por los de defaultOverlayPolicy
+func (p *OverlayPolicy) normalize() {
+	defaults := defaultOverlayPolicy()
+	switch p.StartPhase {
+	case OverlayStartManual, OverlayStartLobby, OverlayStartChampSelect:
+	default:
+		p.StartPhase = defaults.StartPhase
+	}
+	switch p.AfterGame {
+	case OverlayAfterGameKeep, OverlayAfterGameStop, OverlayAfterGameRebuild:
+	default:
+		p.AfterGame = defaults.AfterGame
+	}
+}
+
+// isInGamePhase indica si la partida ya está cargando o en curso
+func isInGamePhase(phase string) bool {
+	return phase == PhaseGameStart || phase == PhaseInProgress || phase == PhaseReconnect
+}
+
+// modToolsPhases traduce las líneas "Status: ..." de mod-tools a fases de gameflow,
+// para seguir la partida aunque el cliente de League no esté disponible
+var modToolsPhases = []struct {
+	status string
+	phase  string
+}{
+	{"Status: Found League", PhaseInProgress},
+	{"Status: Waiting for exit", PhaseInProgress},
+	{"Status: League exited", PhaseEndOfGame},
+}
+
+// observeModToolsStatus actualiza la fase a partir de una línea de salida de mod-tools
+func (a *App) observeModToolsStatus(line string) {
+	for _, m := range modToolsPhases {
+		if strings.Contains(line, m.status) {
+			a.setGameflowPhase(m.phase, "mod-tools")
+			return
+		}
+	}
+}
+
+// registerGameflowHandlers suscribe el watcher a los cambios de fase
+func (a *App) registerGameflowHandlers() {
+	a.lcu.Handle(gameflowEvent, gameflowURI, func(event LCUEvent) {
+		var phase string
+		if err := json.Unmarshal(event.Data, &phase); err == nil {
+			a.setGameflowPhase(phase, "lcu")
+		}
+	})
+	a.lcu.OnConnect(func(client *LCUClient) {
+		var phase string
+		if err := client.Get(gameflowURI, &phase); err == nil {
+			a.setGameflowPhase(phase, "lcu")
+		}
+	})
+	a.lcu.OnDisconnect(func() {
+		a.setGameflowPhase(PhaseNone, "lcu")
+	})
+}
+
+// GetGameflowPhase devuelve la última fase conocida
+func (a *App) GetGameflowPhase() string {
+	a.gameflowMu.Lock()
+	defer a.gameflowMu.Unlock()
+	if a.gameflowPhase == "" {
+		return PhaseNone
+	}
+	return a.gameflowPhase
+}
+
+// setGameflowPhase registra la fase nueva y aplica la política del overlay
+func (a *App) setGameflowPhase(phase, source string) {
+	a.gameflowMu.Lock()
+	previous := a.gameflowPhase
+	if phase == previous {
+		a.gameflowMu.Unlock()
+		return
+	}
+	a.gameflowPhase = phase
+	if isInGamePhase(phase) {
+		a.playedGame = true
+	}
+	gameEnded := a.playedGame && !isInGamePhase(phase) && phase != PhaseWaitingForStats && phase != PhasePreEndOfGame
+	if gameEnded {
+		a.playedGame = false
+	}
+	a.gameflowMu.Unlock()
+
+	a.logger(LogSubsystemOverlay).Infof("Gameflow phase %s -> %s (%s)", previous, phase, source)
+	a.emit("gameflow-phase", map[string]interface{}{
+		"phase":    phase,
+		"previous": previous,
+		"source":   source,
+	})
+
+	policy := a.currentSettings().Overlay
+	switch {
+	case gameEnded:
+		go a.afterGame(policy.AfterGame)
+	case phase == PhaseLobby || phase == PhaseMatchmaking || phase == PhaseReadyCheck:
+		if policy.StartPhase == OverlayStartLobby {
+			go a.autoStartOverlay()
+		}
+	case phase == PhaseChampSelect:
+		// Con AutoApplyOnLockIn el overlay se arma al confirmar el campeón
+		if policy.StartPhase == OverlayStartChampSelect && !a.currentSettings().AutoApplyOnLockIn {
+			go a.autoStartOverlay()
+		}
+	}
+}
+
+// autoStartOverlay arma el overlay con todas las skins y lo arranca si no está corriendo
+func (a *App) autoStartOverlay() {
+	if a.CheckModToolsRunning() || a.installedSkins.Len() == 0 {
+		return
+	}
+	if err := a.buildOverlay(); err != nil {
+		a.logger(LogSubsystemOverlay).Errorf("Auto start: %v", err)
+		return
+	}
+	a.StartRunOverlay()
+}
+
+// afterGame aplica la política de fin de partida
+func (a *App) afterGame(policy string) {
+	switch policy {
+	case OverlayAfterGameStop:
+		if a.CheckModToolsRunning() {
+			a.killModTools()
+		}
+	case OverlayAfterGameRebuild:
+		if a.CheckModToolsRunning() {
+			a.killModTools()
+		}
+		a.autoStartOverlay()
+	}
+}
+
+// overlayLockedByGame indica si la política impide detener el overlay ahora
+func (a *App) overlayLockedByGame() bool {
+	return a.currentSettings().Overlay.ProtectInGame && isInGamePhase(a.GetGameflowPhase())
+}
+
+// checkOverlayNotInGame devuelve errOverlayInGame si la política impide tocar el
+// overlay ahora. KillModTools y RestartModTools lo comprueban siempre; quien
+// además cambia installed/ o el perfil lo comprueba antes de empezar.
+func (a *App) checkOverlayNotInGame() error {
+	if a.overlayLockedByGame() {
+		return errOverlayInGame()
+	}
+	return nil
+}
+
+// errOverlayInGame es el error cuando se intenta detener o cambiar el overlay en partida
+func errOverlayInGame() error {
+	return newAppError(ErrOverlayInGame, "The overlay cannot be stopped or changed while a game is in progress", nil)
+}
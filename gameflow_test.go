@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestOverlayGuardedDuringGame(t *testing.T) {
+	a := newTestApp(t)
+	a.installedSkins.Set("103", SkinInfo{SkinId: "103015", FileName: "ahri.fantome"})
+	a.setGameflowPhase(PhaseInProgress, "test")
+
+	if result := a.KillModTools(); result.Success || result.Code != ErrOverlayInGame {
+		t.Errorf("KillModTools = %+v; want ErrOverlayInGame", result)
+	}
+	if result := a.RestartModTools(); result.Success || result.Code != ErrOverlayInGame {
+		t.Errorf("RestartModTools = %+v; want ErrOverlayInGame", result)
+	}
+	if result := a.UninstallSkin("103"); result.Code != ErrOverlayInGame {
+		t.Errorf("UninstallSkin code = %q", result.Code)
+	}
+	if _, ok := a.installedSkins.Get("103"); !ok {
+		t.Error("UninstallSkin removed the skin during a game")
+	}
+	if result := a.StopOverlay(); result.Code != ErrOverlayInGame {
+		t.Errorf("StopOverlay code = %q", result.Code)
+	}
+
+	settings := a.currentSettings()
+	settings.AutoApplyOnLockIn = true
+	a.setSettings(settings)
+	if err := a.applyChampionOverlay(103); toAppError(err).Code != ErrOverlayInGame {
+		t.Errorf("applyChampionOverlay = %v; want ErrOverlayInGame", err)
+	}
+
+	settings.Overlay.ProtectInGame = false
+	a.setSettings(settings)
+	if err := a.checkOverlayNotInGame(); err != nil {
+		t.Errorf("ProtectInGame off: %v", err)
+	}
+}
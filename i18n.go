@@ -0,0 +1,417 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Locale es un idioma de los mensajes del backend (etiqueta BCP 47)
+type Locale string
+
+const (
+	LocaleEnglish      Locale = "en"
+	LocaleSpanishSpain Locale = "es-ES"
+	LocaleSpanishLatam Locale = "es-419" // Español de Latinoamérica
+)
+
+// DefaultLocale se usa cuando el idioma del sistema no está soportado
+const DefaultLocale = LocaleEnglish
+
+// SupportedLocales son los idiomas con catálogo completo
+var SupportedLocales = []Locale{LocaleEnglish, LocaleSpanishSpain, LocaleSpanishLatam}
+
+// MessageKey identifica un mensaje del catálogo. Los códigos de error (ErrorCode)
+// son claves; los avisos de éxito usan las constantes Msg* y los errores de
+// formulario, fieldMessageKey.
+type MessageKey string
+
+// Avisos de las operaciones que salieron bien
+const (
+	MsgPasswordResetSent     MessageKey = "PASSWORD_RESET_SENT"
+	MsgVerificationSent      MessageKey = "VERIFICATION_SENT"
+	MsgPasswordUpdated       MessageKey = "PASSWORD_UPDATED"
+	MsgEmailVerified         MessageKey = "EMAIL_VERIFIED"
+	MsgLoginSuccess          MessageKey = "LOGIN_SUCCESS"
+	MsgLoggedOut             MessageKey = "LOGGED_OUT"
+	MsgRegisterSuccess       MessageKey = "REGISTER_SUCCESS"
+	MsgProfileUpdated        MessageKey = "PROFILE_UPDATED"
+	MsgProfileEmailPending   MessageKey = "PROFILE_EMAIL_PENDING"
+	MsgSkinDownloaded        MessageKey = "SKIN_DOWNLOADED"
+	MsgSkinInstalled         MessageKey = "SKIN_INSTALLED"
+	MsgSkinUninstalled       MessageKey = "SKIN_UNINSTALLED"
+	MsgSkinsUninstalled      MessageKey = "SKINS_UNINSTALLED"
+	MsgOverlayStarted        MessageKey = "OVERLAY_STARTED"
+	MsgOverlayAlreadyRunning MessageKey = "OVERLAY_ALREADY_RUNNING"
+	MsgOverlayStopped        MessageKey = "OVERLAY_STOPPED"
+	MsgOverlayRebuilt        MessageKey = "OVERLAY_REBUILT"
+	MsgBackupCreated         MessageKey = "BACKUP_CREATED"
+	MsgBackupRestored        MessageKey = "BACKUP_RESTORED"
+	MsgLoadoutExported       MessageKey = "LOADOUT_EXPORTED" // %d skins
+	MsgLoadoutApplied        MessageKey = "LOADOUT_APPLIED"  // %d instaladas, %d quitadas, %d fallidas
+	MsgLoadoutNothingToApply MessageKey = "LOADOUT_NOTHING_TO_APPLY"
+	MsgModImported           MessageKey = "MOD_IMPORTED"
+	MsgRepairRedownloaded    MessageKey = "REPAIR_REDOWNLOADED"
+	MsgRepairDropped         MessageKey = "REPAIR_DROPPED"
+	MsgRepairAdopted         MessageKey = "REPAIR_ADOPTED"
+	MsgRepairDeleted         MessageKey = "REPAIR_DELETED"
+	MsgRoulettePicked        MessageKey = "ROULETTE_PICKED"         // %d skins
+	MsgRoulettePickedPartial MessageKey = "ROULETTE_PICKED_PARTIAL" // %d skins, %d campeones fallidos
+	MsgDiagnosticsExported   MessageKey = "DIAGNOSTICS_EXPORTED"    // %d archivos
+	MsgDoctorSummary         MessageKey = "DOCTOR_SUMMARY"          // %d correctas, %d avisos, %d fallos
+)
+
+// Sugerencias de RunDiagnostics para cada comprobación que no pasa
+const (
+	HintModToolsMissing       MessageKey = "HINT_MODTOOLS_MISSING"
+	HintModToolsModified      MessageKey = "HINT_MODTOOLS_MODIFIED"
+	HintGamePath              MessageKey = "HINT_GAME_PATH" // %s carpeta esperada
+	HintBaseNotWritable       MessageKey = "HINT_BASE_NOT_WRITABLE"
+	HintBackendUnreachable    MessageKey = "HINT_BACKEND_UNREACHABLE"
+	HintCatalogUnreachable    MessageKey = "HINT_CATALOG_UNREACHABLE"
+	HintInstalledInconsistent MessageKey = "HINT_INSTALLED_INCONSISTENT"
+	HintOrphanProcesses       MessageKey = "HINT_ORPHAN_PROCESSES"
+)
+
+// fieldMessageKey es la clave del mensaje de un código de validación ("weak_password" -> FIELD_WEAK_PASSWORD)
+func fieldMessageKey(code string) MessageKey {
+	return MessageKey("FIELD_" + strings.ToUpper(code))
+}
+
+// messageCatalog tiene un texto por clave para cada idioma soportado. Los avisos
+// con %d reciben los argumentos de tr.
+var messageCatalog = map[Locale]map[MessageKey]string{
+	LocaleEnglish: {
+		MessageKey(ErrAuthInvalidToken):       "Your session is not valid. Please sign in again",
+		MessageKey(ErrAuthNotSignedIn):        "You are not signed in",
+		MessageKey(ErrAuthInvalidCredentials): "Invalid login or password",
+		MessageKey(ErrAuthRateLimited):        "Too many attempts. Please try again later",
+		MessageKey(ErrAuthEmailUnverified):    "Confirm your email before continuing",
+		MessageKey(ErrAuthSessionExpired):     "Your session has expired. Please sign in again",
+		MessageKey(ErrAuthUnavailable):        "Could not reach the server",
+		MessageKey(ErrValidationFailed):       "Some fields are not valid",
+		MessageKey(ErrInvalidArgument):        "Invalid request",
+		MessageKey(ErrNotFound):               "Not found",
+		MessageKey(ErrEntitlementDenied):      "You do not have access to this feature",
+		MessageKey(ErrOutOfTokens):            "You have no skin tokens left",
+		MessageKey(ErrCatalogUnavailable):     "Could not load the skin catalog",
+		MessageKey(ErrDownloadFailed):         "Error downloading skin",
+		MessageKey(ErrStorageFailed):          "Could not read or write local files",
+		MessageKey(ErrBackupInvalid):          "The backup is damaged or invalid",
+		MessageKey(ErrLoadoutInvalid):         "The loadout file is damaged or invalid",
+		MessageKey(ErrLoadoutCostChanged):     "Applying this loadout now costs more skin tokens. Review it again",
+		MessageKey(ErrModInvalid):             "This mod format is not supported",
+		MessageKey(ErrModToolsMissing):        "mod-tools.exe is missing. Reinstall the app or check your antivirus quarantine",
+		MessageKey(ErrModToolsImportFailed):   "Could not import the skin",
+		MessageKey(ErrModToolsOverlayFailed):  "Could not build the overlay",
+		MessageKey(ErrModToolsStartFailed):    "Could not start the overlay",
+		MessageKey(ErrModToolsCommandFailed):  "mod-tools reported an error",
+		MessageKey(ErrOverlayInGame):          "The overlay cannot be changed while a game is in progress",
+		MessageKey(ErrGamePathMissing):        "League of Legends was not found. Check the game folder",
+		MessageKey(ErrLCUNotRunning):          "The League client is not running",
+		MessageKey(ErrInternal):               "Unexpected error",
+
+		MsgPasswordResetSent:     "If an account exists for that email, a reset code has been sent",
+		MsgVerificationSent:      "If the account is pending confirmation, a new email has been sent",
+		MsgPasswordUpdated:       "Password updated",
+		MsgEmailVerified:         "Email verified",
+		MsgLoginSuccess:          "Signed in",
+		MsgLoggedOut:             "Signed out",
+		MsgRegisterSuccess:       "Account created",
+		MsgProfileUpdated:        "Profile updated",
+		MsgProfileEmailPending:   "Profile updated. Check your inbox to confirm the new email",
+		MsgSkinDownloaded:        "Skin downloaded",
+		MsgSkinInstalled:         "Skin installed and overlay started",
+		MsgSkinUninstalled:       "Skin uninstalled and overlay restarted",
+		MsgSkinsUninstalled:      "Skins uninstalled and overlay restarted",
+		MsgOverlayStarted:        "Overlay started",
+		MsgOverlayAlreadyRunning: "The overlay is already running",
+		MsgOverlayStopped:        "Overlay stopped",
+		MsgOverlayRebuilt:        "Overlay profile rebuilt",
+		MsgBackupCreated:         "Backup created",
+		MsgBackupRestored:        "Backup restored",
+		MsgLoadoutExported:       "Loadout exported with %d skins",
+		MsgLoadoutApplied:        "Loadout applied: %d skins installed, %d removed, %d failed",
+		MsgLoadoutNothingToApply: "Nothing to apply",
+		MsgModImported:           "Mod imported and overlay started",
+		MsgRepairRedownloaded:    "Skin file downloaded again",
+		MsgRepairDropped:         "Entry removed",
+		MsgRepairAdopted:         "File adopted",
+		MsgRepairDeleted:         "File deleted",
+		MsgRoulettePicked:        "Roulette picked %d skins",
+		MsgRoulettePickedPartial: "Roulette picked %d skins; %d champions failed",
+		MsgDiagnosticsExported:   "Diagnostics bundle exported with %d files",
+		MsgDoctorSummary:         "%d checks passed, %d warnings, %d failed",
+
+		fieldMessageKey(ValidationRequired):      "This field is required",
+		fieldMessageKey(ValidationInvalidEmail):  "Invalid email address",
+		fieldMessageKey(ValidationEmailTaken):    "An account with this email already exists",
+		fieldMessageKey(ValidationInvalidLogin):  "Use 3 to 24 letters, numbers, spaces, '.', '-' or '_'",
+		fieldMessageKey(ValidationLoginTaken):    "This name is already taken",
+		fieldMessageKey(ValidationReservedName):  "This name is reserved",
+		fieldMessageKey(ValidationWeakPassword):  "Use 8 to 72 characters with letters and numbers, not a common password or your login",
+		fieldMessageKey(ValidationSamePassword):  "The new password must be different from the current one",
+		fieldMessageKey(ValidationWrongPassword): "The current password is incorrect",
+		fieldMessageKey(ValidationRateLimited):   "Too many attempts. Please try again later",
+
+		HintModToolsMissing:       "Reinstall the app. If the file disappears again, your antivirus quarantined it: restore it and add an exclusion for the cslol-tools folder",
+		HintModToolsModified:      "The file does not match the version shipped with the app. Reinstall the app and add an antivirus exclusion for the cslol-tools folder",
+		HintGamePath:              "Install League of Legends in %s",
+		HintBaseNotWritable:       "Move the app to a folder you can write to (not Program Files) or check its permissions",
+		HintBackendUnreachable:    "Check your internet connection, firewall or proxy, and the backend URL in the settings",
+		HintCatalogUnreachable:    "Check the storage settings (provider, endpoint and buckets)",
+		HintInstalledInconsistent: "Open the repair view (or run \"skinhunter verify\") and fix the listed entries",
+		HintOrphanProcesses:       "Stop the overlay, which ends every mod-tools.exe, or end them in Task Manager",
+	},
+	LocaleSpanishSpain: {
+		MessageKey(ErrAuthInvalidToken):       "Tu sesión no es válida. Vuelve a iniciar sesión",
+		MessageKey(ErrAuthNotSignedIn):        "No has iniciado sesión",
+		MessageKey(ErrAuthInvalidCredentials): "Usuario o contraseña incorrectos",
+		MessageKey(ErrAuthRateLimited):        "Demasiados intentos. Inténtalo de nuevo más tarde",
+		MessageKey(ErrAuthEmailUnverified):    "Confirma tu email antes de continuar",
+		MessageKey(ErrAuthSessionExpired):     "Tu sesión ha caducado. Vuelve a iniciar sesión",
+		MessageKey(ErrAuthUnavailable):        "No se ha podido conectar con el servidor",
+		MessageKey(ErrValidationFailed):       "Hay campos que no son válidos",
+		MessageKey(ErrInvalidArgument):        "Petición no válida",
+		MessageKey(ErrNotFound):               "No encontrado",
+		MessageKey(ErrEntitlementDenied):      "No tienes acceso a esta función",
+		MessageKey(ErrOutOfTokens):            "No te quedan fichas",
+		MessageKey(ErrCatalogUnavailable):     "No se ha podido cargar el catálogo de skins",
+		MessageKey(ErrDownloadFailed):         "Error al descargar la skin",
+		MessageKey(ErrStorageFailed):          "No se han podido leer o escribir los archivos locales",
+		MessageKey(ErrBackupInvalid):          "La copia de seguridad está dañada o no es válida",
+		MessageKey(ErrLoadoutInvalid):         "El archivo de loadout está dañado o no es válido",
+		MessageKey(ErrLoadoutCostChanged):     "Aplicar este loadout ahora cuesta más fichas. Revísalo de nuevo",
+		MessageKey(ErrModInvalid):             "Este formato de mod no es compatible",
+		MessageKey(ErrModToolsMissing):        "Falta mod-tools.exe. Reinstala la aplicación o revisa la cuarentena del antivirus",
+		MessageKey(ErrModToolsImportFailed):   "No se ha podido importar la skin",
+		MessageKey(ErrModToolsOverlayFailed):  "No se ha podido crear el overlay",
+		MessageKey(ErrModToolsStartFailed):    "No se ha podido iniciar el overlay",
+		MessageKey(ErrModToolsCommandFailed):  "mod-tools ha devuelto un error",
+		MessageKey(ErrOverlayInGame):          "No se puede cambiar el overlay durante una partida",
+		MessageKey(ErrGamePathMissing):        "No se ha encontrado League of Legends. Revisa la carpeta del juego",
+		MessageKey(ErrLCUNotRunning):          "El cliente de League no está abierto",
+		MessageKey(ErrInternal):               "Error inesperado",
+
+		MsgPasswordResetSent:     "Si existe una cuenta con ese email, te hemos enviado un código de recuperación",
+		MsgVerificationSent:      "Si la cuenta está pendiente de confirmar, te hemos enviado otro email",
+		MsgPasswordUpdated:       "Contraseña actualizada",
+		MsgEmailVerified:         "Email confirmado",
+		MsgLoginSuccess:          "Sesión iniciada",
+		MsgLoggedOut:             "Has cerrado sesión",
+		MsgRegisterSuccess:       "Cuenta creada",
+		MsgProfileUpdated:        "Perfil actualizado",
+		MsgProfileEmailPending:   "Perfil actualizado. Revisa tu correo para confirmar el nuevo email",
+		MsgSkinDownloaded:        "Skin descargada",
+		MsgSkinInstalled:         "Skin instalada y overlay iniciado",
+		MsgSkinUninstalled:       "Skin desinstalada y overlay reiniciado",
+		MsgSkinsUninstalled:      "Skins desinstaladas y overlay reiniciado",
+		MsgOverlayStarted:        "Overlay iniciado",
+		MsgOverlayAlreadyRunning: "El overlay ya está en marcha",
+		MsgOverlayStopped:        "Overlay detenido",
+		MsgOverlayRebuilt:        "Perfil del overlay regenerado",
+		MsgBackupCreated:         "Copia de seguridad creada",
+		MsgBackupRestored:        "Copia de seguridad restaurada",
+		MsgLoadoutExported:       "Loadout exportado con %d skins",
+		MsgLoadoutApplied:        "Loadout aplicado: %d skins instaladas, %d quitadas, %d con errores",
+		MsgLoadoutNothingToApply: "No hay nada que aplicar",
+		MsgModImported:           "Mod importado y overlay iniciado",
+		MsgRepairRedownloaded:    "Archivo de la skin descargado de nuevo",
+		MsgRepairDropped:         "Entrada eliminada",
+		MsgRepairAdopted:         "Archivo registrado",
+		MsgRepairDeleted:         "Archivo borrado",
+		MsgRoulettePicked:        "La ruleta ha elegido %d skins",
+		MsgRoulettePickedPartial: "La ruleta ha elegido %d skins; han fallado %d campeones",
+		MsgDiagnosticsExported:   "Paquete de diagnóstico exportado con %d archivos",
+		MsgDoctorSummary:         "%d comprobaciones correctas, %d avisos, %d fallos",
+
+		fieldMessageKey(ValidationRequired):      "Este campo es obligatorio",
+		fieldMessageKey(ValidationInvalidEmail):  "Email no válido",
+		fieldMessageKey(ValidationEmailTaken):    "Ya existe una cuenta con este email",
+		fieldMessageKey(ValidationInvalidLogin):  "Usa de 3 a 24 letras, números, espacios, '.', '-' o '_'",
+		fieldMessageKey(ValidationLoginTaken):    "Este nombre ya está en uso",
+		fieldMessageKey(ValidationReservedName):  "Este nombre está reservado",
+		fieldMessageKey(ValidationWeakPassword):  "Usa de 8 a 72 caracteres con letras y números, que no sea una contraseña común ni tu usuario",
+		fieldMessageKey(ValidationSamePassword):  "La nueva contraseña debe ser distinta de la actual",
+		fieldMessageKey(ValidationWrongPassword): "La contraseña actual no es correcta",
+		fieldMessageKey(ValidationRateLimited):   "Demasiados intentos. Inténtalo de nuevo más tarde",
+
+		HintModToolsMissing:       "Reinstala la aplicación. Si el archivo vuelve a desaparecer, el antivirus lo ha puesto en cuarentena: restáuralo y añade una exclusión para la carpeta cslol-tools",
+		HintModToolsModified:      "El archivo no coincide con la versión incluida en la aplicación. Reinstala la aplicación y añade una exclusión del antivirus para la carpeta cslol-tools",
+		HintGamePath:              "Instala League of Legends en %s",
+		HintBaseNotWritable:       "Mueve la aplicación a una carpeta con permisos de escritura (no Archivos de programa) o revisa sus permisos",
+		HintBackendUnreachable:    "Comprueba la conexión a Internet, el cortafuegos o el proxy, y la URL del backend en los ajustes",
+		HintCatalogUnreachable:    "Revisa la configuración de almacenamiento (proveedor, endpoint y buckets)",
+		HintInstalledInconsistent: "Abre la vista de reparación (o ejecuta \"skinhunter verify\") y corrige las entradas indicadas",
+		HintOrphanProcesses:       "Detén el overlay, que cierra todos los mod-tools.exe, o ciérralos desde el Administrador de tareas",
+	},
+	LocaleSpanishLatam: {
+		MessageKey(ErrAuthInvalidToken):       "Tu sesión no es válida. Vuelve a iniciar sesión",
+		MessageKey(ErrAuthNotSignedIn):        "No iniciaste sesión",
+		MessageKey(ErrAuthInvalidCredentials): "Usuario o contraseña incorrectos",
+		MessageKey(ErrAuthRateLimited):        "Demasiados intentos. Vuelve a intentarlo más tarde",
+		MessageKey(ErrAuthEmailUnverified):    "Confirma tu correo antes de continuar",
+		MessageKey(ErrAuthSessionExpired):     "Tu sesión venció. Vuelve a iniciar sesión",
+		MessageKey(ErrAuthUnavailable):        "No se pudo conectar con el servidor",
+		MessageKey(ErrValidationFailed):       "Hay campos que no son válidos",
+		MessageKey(ErrInvalidArgument):        "Solicitud no válida",
+		MessageKey(ErrNotFound):               "No se encontró",
+		MessageKey(ErrEntitlementDenied):      "No tienes acceso a esta función",
+		MessageKey(ErrOutOfTokens):            "No te quedan fichas",
+		MessageKey(ErrCatalogUnavailable):     "No se pudo cargar el catálogo de skins",
+		MessageKey(ErrDownloadFailed):         "Error al descargar la skin",
+		MessageKey(ErrStorageFailed):          "No se pudieron leer o escribir los archivos locales",
+		MessageKey(ErrBackupInvalid):          "La copia de seguridad está dañada o no es válida",
+		MessageKey(ErrLoadoutInvalid):         "El archivo de loadout está dañado o no es válido",
+		MessageKey(ErrLoadoutCostChanged):     "Aplicar este loadout ahora cuesta más fichas. Revísalo de nuevo",
+		MessageKey(ErrModInvalid):             "Este formato de mod no es compatible",
+		MessageKey(ErrModToolsMissing):        "Falta mod-tools.exe. Reinstala la aplicación o revisa la cuarentena del antivirus",
+		MessageKey(ErrModToolsImportFailed):   "No se pudo importar la skin",
+		MessageKey(ErrModToolsOverlayFailed):  "No se pudo crear el overlay",
+		MessageKey(ErrModToolsStartFailed):    "No se pudo iniciar el overlay",
+		MessageKey(ErrModToolsCommandFailed):  "mod-tools devolvió un error",
+		MessageKey(ErrOverlayInGame):          "No se puede cambiar el overlay durante una partida",
+		MessageKey(ErrGamePathMissing):        "No se encontró League of Legends. Revisa la carpeta del juego",
+		MessageKey(ErrLCUNotRunning):          "El cliente de League no está abierto",
+		MessageKey(ErrInternal):               "Error inesperado",
+
+		MsgPasswordResetSent:     "Si existe una cuenta con ese correo, te enviamos un código de recuperación",
+		MsgVerificationSent:      "Si la cuenta está pendiente de confirmación, te enviamos otro correo",
+		MsgPasswordUpdated:       "Contraseña actualizada",
+		MsgEmailVerified:         "Correo confirmado",
+		MsgLoginSuccess:          "Sesión iniciada",
+		MsgLoggedOut:             "Cerraste sesión",
+		MsgRegisterSuccess:       "Cuenta creada",
+		MsgProfileUpdated:        "Perfil actualizado",
+		MsgProfileEmailPending:   "Perfil actualizado. Revisa tu bandeja de entrada para confirmar el nuevo correo",
+		MsgSkinDownloaded:        "Skin descargada",
+		MsgSkinInstalled:         "Skin instalada y overlay iniciado",
+		MsgSkinUninstalled:       "Skin desinstalada y overlay reiniciado",
+		MsgSkinsUninstalled:      "Skins desinstaladas y overlay reiniciado",
+		MsgOverlayStarted:        "Overlay iniciado",
+		MsgOverlayAlreadyRunning: "El overlay ya está funcionando",
+		MsgOverlayStopped:        "Overlay detenido",
+		MsgOverlayRebuilt:        "Perfil del overlay regenerado",
+		MsgBackupCreated:         "Copia de seguridad creada",
+		MsgBackupRestored:        "Copia de seguridad restaurada",
+		MsgLoadoutExported:       "Loadout exportado con %d skins",
+		MsgLoadoutApplied:        "Loadout aplicado: %d skins instaladas, %d quitadas, %d con errores",
+		MsgLoadoutNothingToApply: "No hay nada para aplicar",
+		MsgModImported:           "Mod importado y overlay iniciado",
+		MsgRepairRedownloaded:    "Se volvió a descargar el archivo de la skin",
+		MsgRepairDropped:         "Entrada eliminada",
+		MsgRepairAdopted:         "Archivo registrado",
+		MsgRepairDeleted:         "Archivo eliminado",
+		MsgRoulettePicked:        "La ruleta eligió %d skins",
+		MsgRoulettePickedPartial: "La ruleta eligió %d skins; fallaron %d campeones",
+		MsgDiagnosticsExported:   "Paquete de diagnóstico exportado con %d archivos",
+		MsgDoctorSummary:         "%d verificaciones correctas, %d advertencias, %d fallas",
+
+		fieldMessageKey(ValidationRequired):      "Este campo es obligatorio",
+		fieldMessageKey(ValidationInvalidEmail):  "Correo no válido",
+		fieldMessageKey(ValidationEmailTaken):    "Ya existe una cuenta con este correo",
+		fieldMessageKey(ValidationInvalidLogin):  "Usa de 3 a 24 letras, números, espacios, '.', '-' o '_'",
+		fieldMessageKey(ValidationLoginTaken):    "Este nombre ya está en uso",
+		fieldMessageKey(ValidationReservedName):  "Este nombre está reservado",
+		fieldMessageKey(ValidationWeakPassword):  "Usa de 8 a 72 caracteres con letras y números, que no sea una contraseña común ni tu usuario",
+		fieldMessageKey(ValidationSamePassword):  "La nueva contraseña debe ser distinta de la actual",
+		fieldMessageKey(ValidationWrongPassword): "La contraseña actual es incorrecta",
+		fieldMessageKey(ValidationRateLimited):   "Demasiados intentos. Vuelve a intentarlo más tarde",
+
+		HintModToolsMissing:       "Reinstala la aplicación. Si el archivo vuelve a desaparecer, el antivirus lo puso en cuarentena: restáuralo y agrega una exclusión para la carpeta cslol-tools",
+		HintModToolsModified:      "El archivo no coincide con la versión incluida en la aplicación. Reinstala la aplicación y agrega una exclusión del antivirus para la carpeta cslol-tools",
+		HintGamePath:              "Instala League of Legends en %s",
+		HintBaseNotWritable:       "Mueve la aplicación a una carpeta con permisos de escritura (no Archivos de programa) o revisa sus permisos",
+		HintBackendUnreachable:    "Revisa la conexión a internet, el firewall o el proxy, y la URL del backend en la configuración",
+		HintCatalogUnreachable:    "Revisa la configuración de almacenamiento (proveedor, endpoint y buckets)",
+		HintInstalledInconsistent: "Abre la vista de reparación (o ejecuta \"skinhunter verify\") y corrige las entradas indicadas",
+		HintOrphanProcesses:       "Detén el overlay, que cierra todos los mod-tools.exe, o ciérralos desde el Administrador de tareas",
+	},
+}
+
+var (
+	localeMu     sync.RWMutex
+	activeLocale = DefaultLocale
+)
+
+// setLocale cambia el idioma de los mensajes devueltos al frontend y a la CLI
+func setLocale(locale Locale) {
+	localeMu.Lock()
+	activeLocale = locale
+	localeMu.Unlock()
+}
+
+func currentLocale() Locale {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	return activeLocale
+}
+
+// tr devuelve el mensaje de key en el idioma activo. Si falta, usa inglés y, como
+// último recurso, la propia clave.
+func tr(key MessageKey, args ...interface{}) string {
+	if key == "" {
+		return ""
+	}
+	message, ok := messageCatalog[currentLocale()][key]
+	if !ok {
+		message, ok = messageCatalog[DefaultLocale][key]
+	}
+	if !ok {
+		return string(key)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// matchLocale elige el idioma soportado más cercano a una etiqueta como "es_MX.UTF-8",
+// "es-AR" o "en-US". Cualquier español que no sea de España usa es-419.
+func matchLocale(tag string) (Locale, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	parts := strings.Split(strings.ToLower(tag), "-")
+	switch parts[0] {
+	case "en":
+		return LocaleEnglish, true
+	case "es":
+		if len(parts) > 1 && parts[1] == "es" {
+			return LocaleSpanishSpain, true
+		}
+		return LocaleSpanishLatam, true
+	}
+	return "", false
+}
+
+// resolveLocale devuelve el idioma a usar para el ajuste language: el elegido o,
+// si está vacío, el del sistema
+func resolveLocale(setting string) Locale {
+	if locale, ok := matchLocale(setting); ok {
+		return locale
+	}
+	if locale, ok := matchLocale(systemLocaleName()); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// LocaleInfo describe el idioma de los mensajes del backend
+type LocaleInfo struct {
+	Setting   string   `json:"setting"` // Valor de settings.language ("" = el del sistema)
+	Active    Locale   `json:"active"`
+	System    string   `json:"system"` // Idioma del sistema operativo, tal cual lo informa
+	Available []Locale `json:"available"`
+}
+
+// GetLocaleInfo informa el idioma activo y los disponibles
+func (a *App) GetLocaleInfo() LocaleInfo {
+	return LocaleInfo{
+		Setting:   a.currentSettings().Language,
+		Active:    currentLocale(),
+		System:    systemLocaleName(),
+		Available: SupportedLocales,
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// declaredKeys lee del código fuente las constantes que necesitan un texto en el
+// catálogo: los ErrorCode, los MessageKey (Msg*, Hint*) y los códigos Validation*.
+// Se parsea el código para que una constante nueva no se pueda olvidar en una lista.
+func declaredKeys(t *testing.T) (keys map[MessageKey]string, errorCodes map[ErrorCode]string) {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = map[MessageKey]string{}
+	errorCodes = map[ErrorCode]string{}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				typeName := ""
+				if ident, ok := vs.Type.(*ast.Ident); ok {
+					typeName = ident.Name
+				}
+				for i, ident := range vs.Names {
+					if i >= len(vs.Values) {
+						continue
+					}
+					lit, ok := vs.Values[i].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					value, _ := strconv.Unquote(lit.Value)
+					switch {
+					case typeName == "ErrorCode":
+						errorCodes[ErrorCode(value)] = ident.Name
+						keys[MessageKey(value)] = ident.Name
+					case typeName == "MessageKey":
+						keys[MessageKey(value)] = ident.Name
+					case typeName == "" && strings.HasPrefix(ident.Name, "Validation"):
+						keys[fieldMessageKey(value)] = ident.Name
+					}
+				}
+			}
+		}
+	}
+	if len(keys) == 0 {
+		t.Fatal("no message keys found in the source")
+	}
+	return keys, errorCodes
+}
+
+func TestCatalogHasEveryKey(t *testing.T) {
+	keys, _ := declaredKeys(t)
+	for _, locale := range SupportedLocales {
+		messages, ok := messageCatalog[locale]
+		if !ok {
+			t.Errorf("%s: locale missing from the catalog", locale)
+			continue
+		}
+		var missing []string
+		for key, name := range keys {
+			if messages[key] == "" {
+				missing = append(missing, name+" ("+string(key)+")")
+			}
+		}
+		sort.Strings(missing)
+		for _, m := range missing {
+			t.Errorf("%s: missing translation for %s", locale, m)
+		}
+	}
+}
+
+func TestCatalogHasNoUnknownKeys(t *testing.T) {
+	keys, _ := declaredKeys(t)
+	for locale, messages := range messageCatalog {
+		for key := range messages {
+			if _, ok := keys[key]; !ok {
+				t.Errorf("%s: %s is not declared as a constant", locale, key)
+			}
+		}
+	}
+}
+
+func TestAllErrorCodesListsEveryCode(t *testing.T) {
+	_, errorCodes := declaredKeys(t)
+	listed := map[ErrorCode]bool{}
+	for _, code := range AllErrorCodes {
+		listed[code.Value] = true
+		if code.TSName != string(code.Value) {
+			t.Errorf("%s: TSName is %q", code.Value, code.TSName)
+		}
+	}
+	for code, name := range errorCodes {
+		if !listed[code] {
+			t.Errorf("%s is missing from AllErrorCodes", name)
+		}
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Locale
+		ok   bool
+	}{
+		{"en", LocaleEnglish, true},
+		{"en-US", LocaleEnglish, true},
+		{"es-ES", LocaleSpanishSpain, true},
+		{"es_ES.UTF-8", LocaleSpanishSpain, true},
+		{"es-MX", LocaleSpanishLatam, true},
+		{"es_AR.UTF-8@euro", LocaleSpanishLatam, true},
+		{"es", LocaleSpanishLatam, true},
+		{"es-419", LocaleSpanishLatam, true},
+		{"fr-FR", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := matchLocale(tt.tag)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("matchLocale(%q) = %q, %v; want %q, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTrFallsBack(t *testing.T) {
+	defer setLocale(currentLocale())
+	setLocale(LocaleSpanishSpain)
+
+	if got := tr(MsgLoadoutExported, 3); got != "Loadout exportado con 3 skins" {
+		t.Errorf("tr(MsgLoadoutExported, 3) = %q", got)
+	}
+	if got := tr(MessageKey("NOT_A_KEY")); got != "NOT_A_KEY" {
+		t.Errorf("unknown key: got %q", got)
+	}
+	if got := tr(""); got != "" {
+		t.Errorf("empty key: got %q", got)
+	}
+}
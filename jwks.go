@@ -0,0 +1,183 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksCacheTTL es cada cuánto se vuelven a pedir las claves aunque no haya kids desconocidos
+const jwksCacheTTL = 10 * time.Minute
+
+// jwksMinRefetch limita los pedidos al endpoint cuando llegan tokens con kids desconocidos
+const jwksMinRefetch = 30 * time.Second
+
+// jwk es una clave pública del documento JWKS de Supabase Auth
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSVerifier verifica los access tokens de Supabase Auth con las claves
+// públicas del proyecto (<auth>/.well-known/jwks.json). La app no conoce ningún secreto.
+type JWKSVerifier struct {
+	jwksURL    string
+	issuer     string
+	audience   string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{} // kid -> *rsa.PublicKey | *ecdsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKSVerifier crea un verificador para el servidor de auth en authURL (…/auth/v1)
+func NewJWKSVerifier(authURL string, httpClient *http.Client) *JWKSVerifier {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &JWKSVerifier{
+		jwksURL:    authURL + "/.well-known/jwks.json",
+		issuer:     authURL,
+		audience:   "authenticated",
+		httpClient: httpClient,
+		keys:       make(map[string]interface{}),
+	}
+}
+
+// Verify comprueba firma, expiración, emisor y audiencia y devuelve los claims.
+// Devuelve errUnverifiableToken si el token usa un algoritmo simétrico (proyectos
+// sin claves asimétricas), para que el llamador lo valide contra el servidor.
+func (v *JWKSVerifier) Verify(token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		case *jwt.SigningMethodHMAC:
+			return nil, errUnverifiableToken
+		default:
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return v.key(kid)
+	}, jwt.WithIssuer(v.issuer), jwt.WithAudience(v.audience), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, errUnverifiableToken) {
+			return nil, errUnverifiableToken
+		}
+		return nil, err
+	}
+	if !parsed.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if sub, _ := claims.GetSubject(); sub == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+	return claims, nil
+}
+
+var errUnverifiableToken = fmt.Errorf("token must be verified by the auth server")
+
+// key busca la clave por kid, volviendo a pedir el JWKS si el caché venció o el kid es nuevo
+func (v *JWKSVerifier) key(kid string) (interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if k, ok := v.keys[kid]; ok && time.Since(v.fetchedAt) < jwksCacheTTL {
+		return k, nil
+	}
+	if time.Since(v.fetchedAt) >= jwksMinRefetch {
+		if err := v.fetchLocked(); err != nil {
+			// Con un caché viejo pero con la clave, seguir funcionando sin red
+			if k, ok := v.keys[kid]; ok {
+				return k, nil
+			}
+			return nil, err
+		}
+	}
+	if k, ok := v.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (v *JWKSVerifier) fetchLocked() error {
+	resp, err := v.httpClient.Get(v.jwksURL)
+	if err != nil {
+		return fmt.Errorf("error fetching JWKS: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error fetching JWKS: status %d", resp.StatusCode)
+	}
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("invalid JWKS: %v", err)
+	}
+
+	keys := make(map[string]interface{})
+	for _, k := range doc.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			continue // Claves de tipos que no usamos (p. ej. oct)
+		}
+		keys[k.Kid] = pub
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+// publicKey convierte el JWK a una clave de crypto/rsa o crypto/ecdsa
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
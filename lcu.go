@@ -0,0 +1,225 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// LockfileName es el archivo que escribe el cliente de League al iniciar, en su carpeta de instalación
+const LockfileName = "lockfile"
+
+// LCUCredentials son los datos de conexión del lockfile: "LeagueClient:pid:puerto:contraseña:https"
+type LCUCredentials struct {
+	PID      int
+	Port     int
+	Password string
+	Protocol string
+}
+
+// readLockfile lee y valida el lockfile en path
+func readLockfile(path string) (*LCUCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseLockfile(string(data))
+}
+
+// parseLockfile interpreta el contenido de un lockfile
+func parseLockfile(content string) (*LCUCredentials, error) {
+	parts := strings.Split(strings.TrimSpace(content), ":")
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid lockfile: expected 5 fields, got %d", len(parts))
+	}
+	pid, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockfile pid %q", parts[1])
+	}
+	port, err := strconv.Atoi(parts[2])
+	if err != nil || port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid lockfile port %q", parts[2])
+	}
+	return &LCUCredentials{PID: pid, Port: port, Password: parts[3], Protocol: parts[4]}, nil
+}
+
+// LCUEvent es un evento OnJsonApiEvent del cliente
+type LCUEvent struct {
+	URI       string          `json:"uri"`
+	EventType string          `json:"eventType"` // Create, Update o Delete
+	Data      json.RawMessage `json:"data"`
+}
+
+// Códigos de mensaje WAMP 1.0 que usa el LCU
+const (
+	wampSubscribe   = 5
+	wampUnsubscribe = 6
+	wampEvent       = 8
+)
+
+// LCUClient habla con la API local del cliente de League (REST y WebSocket).
+// El cliente usa un certificado autofirmado y solo escucha en 127.0.0.1.
+type LCUClient struct {
+	baseURL    string // https://127.0.0.1:<puerto>
+	authHeader string
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+// NewLCUClient crea un cliente para las credenciales del lockfile
+func NewLCUClient(creds *LCUCredentials) *LCUClient {
+	tlsConfig := &tls.Config{InsecureSkipVerify: true} // Certificado autofirmado de Riot en 127.0.0.1
+	return newLCUClient(
+		fmt.Sprintf("%s://127.0.0.1:%d", creds.Protocol, creds.Port),
+		creds.Password,
+		&http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+		&websocket.Dialer{TLSClientConfig: tlsConfig, HandshakeTimeout: 10 * time.Second},
+	)
+}
+
+// newLCUClient permite apuntar a otro servidor (p. ej. uno falso con httptest)
+func newLCUClient(baseURL, password string, httpClient *http.Client, dialer *websocket.Dialer) *LCUClient {
+	return &LCUClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		authHeader: "Basic " + base64.StdEncoding.EncodeToString([]byte("riot:"+password)),
+		httpClient: httpClient,
+		dialer:     dialer,
+	}
+}
+
+// Get hace un GET a la API REST y decodifica la respuesta en out
+func (c *LCUClient) Get(path string, out interface{}) error {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("LCU request %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading LCU response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("LCU request %s failed: status %d", path, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error decoding LCU response %s: %v", path, err)
+	}
+	return nil
+}
+
+// Connect abre el WebSocket del cliente y se suscribe a los eventos dados
+// (p. ej. "OnJsonApiEvent_lol-champ-select_v1_session")
+func (c *LCUClient) Connect(events ...string) error {
+	wsURL := "wss" + strings.TrimPrefix(c.baseURL, "https")
+	if strings.HasPrefix(c.baseURL, "http://") {
+		wsURL = "ws" + strings.TrimPrefix(c.baseURL, "http")
+	}
+	header := http.Header{}
+	header.Set("Authorization", c.authHeader)
+	conn, _, err := c.dialer.Dial(wsURL+"/", header)
+	if err != nil {
+		return fmt.Errorf("error connecting to LCU websocket: %v", err)
+	}
+	for _, event := range events {
+		if err := conn.WriteJSON([]interface{}{wampSubscribe, event}); err != nil {
+			conn.Close()
+			return fmt.Errorf("error subscribing to %s: %v", event, err)
+		}
+	}
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	return nil
+}
+
+// Listen lee eventos hasta que la conexión se cierra y llama a handle por cada uno
+func (c *LCUClient) Listen(handle func(event LCUEvent)) error {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("LCU websocket not connected")
+	}
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		event, ok := parseWAMPEvent(message)
+		if ok {
+			handle(event)
+		}
+	}
+}
+
+// Close cierra el WebSocket
+func (c *LCUClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// parseWAMPEvent extrae el evento de un mensaje [8, "<tema>", {...}]; ignora el resto
+func parseWAMPEvent(message []byte) (LCUEvent, bool) {
+	var frame []json.RawMessage
+	if err := json.Unmarshal(message, &frame); err != nil || len(frame) != 3 {
+		return LCUEvent{}, false
+	}
+	var code int
+	if err := json.Unmarshal(frame[0], &code); err != nil || code != wampEvent {
+		return LCUEvent{}, false
+	}
+	var event LCUEvent
+	if err := json.Unmarshal(frame[2], &event); err != nil {
+		return LCUEvent{}, false
+	}
+	return event, true
+}
+
+// ChampSelectSession es la parte de /lol-champ-select/v1/session que usa la app
+type ChampSelectSession struct {
+	LocalPlayerCellID int `json:"localPlayerCellId"`
+	Actions           [][]struct {
+		ActorCellID int    `json:"actorCellId"`
+		ChampionID  int    `json:"championId"`
+		Completed   bool   `json:"completed"`
+		Type        string `json:"type"`
+	} `json:"actions"`
+}
+
+// LockedChampion devuelve el campeón que el jugador local ya confirmó, o 0
+func (s *ChampSelectSession) LockedChampion() int {
+	for _, turn := range s.Actions {
+		for _, action := range turn {
+			if action.ActorCellID == s.LocalPlayerCellID && action.Type == "pick" && action.Completed && action.ChampionID != 0 {
+				return action.ChampionID
+			}
+		}
+	}
+	return 0
+}
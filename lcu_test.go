@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestApp crea una App en modo consola, sin registro en stderr ni overlay automático
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	a := NewApp()
+	a.headless = true
+	a.cliLogLevel = LogLevelError + 1
+	settings := defaultSettings()
+	settings.AutoApplyOnLockIn = false
+	settings.Overlay.StartPhase = OverlayStartManual
+	settings.Overlay.AfterGame = OverlayAfterGameKeep
+	a.setSettings(settings)
+	return a
+}
+
+// fakeLCU es un cliente de League falso: responde la fase por REST y, al
+// conectarse el WebSocket, envía events y espera a que se cierre closeConn
+type fakeLCU struct {
+	*httptest.Server
+	password  string
+	phase     string
+	events    [][]interface{}
+	closeConn chan struct{}
+
+	mu         sync.Mutex
+	subscribed []string
+}
+
+func newFakeLCU(t *testing.T, phase string, events ...[]interface{}) *fakeLCU {
+	t.Helper()
+	f := &fakeLCU{password: "s3cret", phase: phase, events: events, closeConn: make(chan struct{})}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeLCU) serve(w http.ResponseWriter, r *http.Request) {
+	user, password, ok := r.BasicAuth()
+	if !ok || user != "riot" || password != f.password {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path == gameflowURI {
+		json.NewEncoder(w).Encode(f.phase)
+		return
+	}
+	if !websocket.IsWebSocketUpgrade(r) {
+		http.NotFound(w, r)
+		return
+	}
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	// El cliente envía una suscripción por evento antes de escuchar
+	for i := 0; i < 2; i++ {
+		var msg []interface{}
+		if err := conn.ReadJSON(&msg); err != nil || len(msg) != 2 || msg[0] != float64(wampSubscribe) {
+			return
+		}
+		f.mu.Lock()
+		f.subscribed = append(f.subscribed, fmt.Sprint(msg[1]))
+		f.mu.Unlock()
+	}
+	for _, event := range f.events {
+		if err := conn.WriteJSON(event); err != nil {
+			return
+		}
+	}
+	<-f.closeConn
+}
+
+// writeLockfile escribe un lockfile que apunta al servidor falso
+func (f *fakeLCU) writeLockfile(t *testing.T) string {
+	t.Helper()
+	port := f.URL[strings.LastIndex(f.URL, ":")+1:]
+	p := filepath.Join(t.TempDir(), LockfileName)
+	content := fmt.Sprintf("LeagueClient:4242:%s:%s:http", port, f.password)
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func lcuEvent(topic, uri, eventType string, data interface{}) []interface{} {
+	return []interface{}{wampEvent, topic, map[string]interface{}{"uri": uri, "eventType": eventType, "data": data}}
+}
+
+func champSelectSession(localCell int, actions ...map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"localPlayerCellId": localCell,
+		"actions":           [][]map[string]interface{}{actions},
+	}
+}
+
+func pickAction(cell, championId int, completed bool) map[string]interface{} {
+	return map[string]interface{}{"actorCellId": cell, "championId": championId, "completed": completed, "type": "pick"}
+}
+
+// waitFor espera hasta un segundo a que cond se cumpla
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestParseLockfile(t *testing.T) {
+	creds, err := parseLockfile("LeagueClient:4242:50123:s3cret:https\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *creds != (LCUCredentials{PID: 4242, Port: 50123, Password: "s3cret", Protocol: "https"}) {
+		t.Errorf("got %+v", *creds)
+	}
+	for _, content := range []string{"", "LeagueClient:4242:50123:s3cret", "LeagueClient:x:50123:s3cret:https", "LeagueClient:4242:0:s3cret:https", "LeagueClient:4242:70000:s3cret:https"} {
+		if _, err := parseLockfile(content); err == nil {
+			t.Errorf("parseLockfile(%q): expected an error", content)
+		}
+	}
+}
+
+func TestChampSelectLockedChampion(t *testing.T) {
+	tests := []struct {
+		name    string
+		session map[string]interface{}
+		want    int
+	}{
+		{"hovered", champSelectSession(2, pickAction(2, 103, false)), 0},
+		{"locked", champSelectSession(2, pickAction(2, 103, true)), 103},
+		{"another player", champSelectSession(2, pickAction(3, 222, true)), 0},
+		{"ban", champSelectSession(2, map[string]interface{}{"actorCellId": 2, "championId": 157, "completed": true, "type": "ban"}), 0},
+	}
+	for _, tt := range tests {
+		data, _ := json.Marshal(tt.session)
+		var session ChampSelectSession
+		if err := json.Unmarshal(data, &session); err != nil {
+			t.Fatal(err)
+		}
+		if got := session.LockedChampion(); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestLCUWatcherPhaseAndLockIn conecta el watcher a un cliente falso y comprueba
+// que la fase de gameflow y el campeón confirmado llegan a la App y se limpian al cerrarse
+func TestLCUWatcherPhaseAndLockIn(t *testing.T) {
+	lcu := newFakeLCU(t, PhaseLobby,
+		lcuEvent(gameflowEvent, gameflowURI, "Update", PhaseChampSelect),
+		lcuEvent(champSelectEvent, champSelectURI, "Update", champSelectSession(2, pickAction(2, 103, false))),
+		lcuEvent(champSelectEvent, champSelectURI, "Update", champSelectSession(2, pickAction(2, 103, true))),
+	)
+	a := newTestApp(t)
+	a.lcu = NewLCUWatcher(lcu.writeLockfile(t))
+	a.lcu.Handle(champSelectEvent, champSelectURI, a.onChampSelectEvent)
+	a.registerGameflowHandlers()
+	a.lcu.OnDisconnect(func() { a.setLockedChampion(0) })
+	a.lcu.Start()
+	defer a.lcu.Stop()
+
+	waitFor(t, "lock-in", func() bool { return a.currentLockedChampion() == 103 })
+	if phase := a.GetGameflowPhase(); phase != PhaseChampSelect {
+		t.Errorf("phase = %s, want %s", phase, PhaseChampSelect)
+	}
+	if !a.GetLCUStatus().Connected {
+		t.Error("GetLCUStatus: not connected")
+	}
+	lcu.mu.Lock()
+	subscribed := strings.Join(lcu.subscribed, ",")
+	lcu.mu.Unlock()
+	if subscribed != champSelectEvent+","+gameflowEvent {
+		t.Errorf("subscribed to %s", subscribed)
+	}
+
+	close(lcu.closeConn) // El cliente se cierra
+	waitFor(t, "disconnect", func() bool { return !a.GetLCUStatus().Connected })
+	if phase := a.GetGameflowPhase(); phase != PhaseNone {
+		t.Errorf("phase after disconnect = %s, want %s", phase, PhaseNone)
+	}
+	if champion := a.currentLockedChampion(); champion != 0 {
+		t.Errorf("locked champion after disconnect = %d", champion)
+	}
+}
+
+func TestChampSelectDeleteClearsLockIn(t *testing.T) {
+	a := newTestApp(t)
+	a.setLockedChampion(103)
+	a.onChampSelectEvent(LCUEvent{URI: champSelectURI, EventType: "Delete"})
+	if champion := a.currentLockedChampion(); champion != 0 {
+		t.Errorf("locked champion after Delete = %d", champion)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// lcuPollInterval es cada cuánto se busca el lockfile mientras el cliente está cerrado
+	lcuPollInterval = 5 * time.Second
+
+	champSelectEvent = "OnJsonApiEvent_lol-champ-select_v1_session"
+	champSelectURI   = "/lol-champ-select/v1/session"
+)
+
+// LCUWatcher espera a que el cliente de League arranque, se conecta a su
+// WebSocket y reparte los eventos a los handlers registrados por URI. Si el
+// cliente se cierra, vuelve a esperar el lockfile.
+type LCUWatcher struct {
+	lockfilePath string
+	newClient    func(creds *LCUCredentials) *LCUClient
+
+	mu           sync.Mutex
+	client       *LCUClient
+	events       []string
+	handlers     map[string][]func(event LCUEvent)
+	onConnect    []func(client *LCUClient)
+	onDisconnect []func()
+	stop         chan struct{}
+}
+
+// NewLCUWatcher crea el watcher para el lockfile en lockfilePath
+func NewLCUWatcher(lockfilePath string) *LCUWatcher {
+	return &LCUWatcher{
+		lockfilePath: lockfilePath,
+		newClient:    NewLCUClient,
+		handlers:     map[string][]func(event LCUEvent){},
+	}
+}
+
+// Handle suscribe event y llama a fn con cada evento cuya URI sea uri.
+// Debe llamarse antes de Start.
+func (w *LCUWatcher) Handle(event, uri string, fn func(event LCUEvent)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.handlers[uri]) == 0 {
+		w.events = appendUnique(w.events, event)
+	}
+	w.handlers[uri] = append(w.handlers[uri], fn)
+}
+
+// OnConnect registra fn para cada conexión nueva (p. ej. para leer el estado inicial)
+func (w *LCUWatcher) OnConnect(fn func(client *LCUClient)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onConnect = append(w.onConnect, fn)
+}
+
+// OnDisconnect registra fn para cuando el cliente se cierra
+func (w *LCUWatcher) OnDisconnect(fn func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onDisconnect = append(w.onDisconnect, fn)
+}
+
+// Start empieza a vigilar en segundo plano
+func (w *LCUWatcher) Start() {
+	w.mu.Lock()
+	if w.stop != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.stop = make(chan struct{})
+	stop := w.stop
+	w.mu.Unlock()
+	go w.run(stop)
+}
+
+// Stop deja de vigilar y cierra la conexión
+func (w *LCUWatcher) Stop() {
+	w.mu.Lock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+	client := w.client
+	w.mu.Unlock()
+	if client != nil {
+		client.Close()
+	}
+}
+
+// Client devuelve el cliente conectado, o nil
+func (w *LCUWatcher) Client() *LCUClient {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.client
+}
+
+func (w *LCUWatcher) run(stop chan struct{}) {
+	for {
+		if creds, err := readLockfile(w.lockfilePath); err == nil {
+			w.session(creds)
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(lcuPollInterval):
+		}
+	}
+}
+
+// session mantiene una conexión hasta que el cliente la cierra
+func (w *LCUWatcher) session(creds *LCUCredentials) {
+	client := w.newClient(creds)
+	w.mu.Lock()
+	events := append([]string(nil), w.events...)
+	w.mu.Unlock()
+	if err := client.Connect(events...); err != nil {
+		return // El cliente todavía está arrancando o el lockfile es viejo
+	}
+
+	w.mu.Lock()
+	w.client = client
+	onConnect := append([]func(*LCUClient){}, w.onConnect...)
+	w.mu.Unlock()
+	for _, fn := range onConnect {
+		fn(client)
+	}
+
+	client.Listen(w.dispatch)
+	client.Close()
+
+	w.mu.Lock()
+	w.client = nil
+	onDisconnect := append([]func(){}, w.onDisconnect...)
+	w.mu.Unlock()
+	for _, fn := range onDisconnect {
+		fn()
+	}
+}
+
+func (w *LCUWatcher) dispatch(event LCUEvent) {
+	w.mu.Lock()
+	handlers := w.handlers[event.URI]
+	w.mu.Unlock()
+	for _, fn := range handlers {
+		fn(event)
+	}
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// defaultLockfilePath es el lockfile en la carpeta del cliente, junto a la carpeta Game
+func defaultLockfilePath() string {
+	return filepath.Join(filepath.Dir(absGamePath), LockfileName)
+}
+
+// startLCUWatcher conecta la app con el cliente de League
+func (a *App) startLCUWatcher() {
+	a.lcu = NewLCUWatcher(defaultLockfilePath())
+	a.lcu.Handle(champSelectEvent, champSelectURI, a.onChampSelectEvent)
+	a.registerGameflowHandlers()
+	a.registerOwnedSkinsHandlers()
+	a.lcu.OnConnect(func(client *LCUClient) {
+		a.logger(LogSubsystemLCU).Info("Connected to League client")
+		a.emit("lcu-connected", nil)
+	})
+	a.lcu.OnDisconnect(func() {
+		a.logger(LogSubsystemLCU).Info("League client closed")
+		a.setLockedChampion(0)
+		a.emit("lcu-disconnected", nil)
+	})
+	a.lcu.Start()
+}
+
+// LCUStatus describe la conexión con el cliente de League
+type LCUStatus struct {
+	Connected      bool `json:"connected"`
+	LockedChampion int  `json:"lockedChampion"`
+	AutoApply      bool `json:"autoApply"`
+}
+
+// GetLCUStatus informa si la app está conectada al cliente de League
+func (a *App) GetLCUStatus() LCUStatus {
+	return LCUStatus{
+		Connected:      a.lcu != nil && a.lcu.Client() != nil,
+		LockedChampion: a.currentLockedChampion(),
+		AutoApply:      a.currentSettings().AutoApplyOnLockIn,
+	}
+}
+
+// currentLockedChampion devuelve el campeón confirmado en la selección actual, o 0
+func (a *App) currentLockedChampion() int {
+	a.overlayMu.Lock()
+	defer a.overlayMu.Unlock()
+	return a.lockedChampion
+}
+
+// setLockedChampion guarda el campeón confirmado y devuelve si cambió
+func (a *App) setLockedChampion(championId int) bool {
+	a.overlayMu.Lock()
+	defer a.overlayMu.Unlock()
+	if a.lockedChampion == championId {
+		return false
+	}
+	a.lockedChampion = championId
+	return true
+}
+
+// onChampSelectEvent detecta cuándo el jugador confirma su campeón
+func (a *App) onChampSelectEvent(event LCUEvent) {
+	if event.EventType == "Delete" {
+		a.setLockedChampion(0) // Terminó la selección (partida o dodge)
+		return
+	}
+	var session ChampSelectSession
+	if err := json.Unmarshal(event.Data, &session); err != nil {
+		a.logger(LogSubsystemLCU).Warningf("Invalid champ select session: %v", err)
+		return
+	}
+	championId := session.LockedChampion()
+	if championId == 0 || !a.setLockedChampion(championId) {
+		return
+	}
+	go func() {
+		if settings := a.currentSettings(); settings.Roulette.OnLockIn && settings.AutoApplyOnLockIn {
+			a.spinLockedChampion(championId)
+		}
+		a.applyChampionOverlay(championId)
+	}()
+}
+
+// applyChampionOverlay arma y activa un overlay solo con la skin configurada
+// para championId. Si no tiene ninguna, el overlay actual no se toca.
+func (a *App) applyChampionOverlay(championId int) error {
+	skin, ok := a.installedSkins.Get(strconv.Itoa(championId))
+	applied := ok && a.currentSettings().AutoApplyOnLockIn
+	a.emit("champion-locked", map[string]interface{}{
+		"championId": championId,
+		"skinName":   skin.SkinName,
+		"applied":    applied,
+	})
+	if !applied {
+		return nil
+	}
+
+	if err := a.checkOverlayNotInGame(); err != nil {
+		return err
+	}
+	a.logger(LogSubsystemLCU).Infof("Champion %d locked in, applying %s", championId, skin.FileName)
+	if a.CheckModToolsRunning() {
+		a.killModTools()
+	}
+	if err := a.buildOverlayFiles([]string{skin.FileName}); err != nil {
+		a.logger(LogSubsystemLCU).Errorf("Could not build overlay for champion %d: %v", championId, err)
+		a.emit("overlay-error", map[string]interface{}{"championId": championId, "error": err.Error()})
+		return err
+	}
+	return a.StartRunOverlay().Err()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Estados de una entrada del libro de fichas
+const (
+	LedgerReserved  = "reserved"  // Ficha apartada, descarga en curso o sin confirmar
+	LedgerCommitted = "committed" // Descarga completada, ficha gastada
+	LedgerRefunded  = "refunded"  // Descarga fallida, ficha devuelta
+)
+
+// TokenLedgerEntry registra un gasto de ficha por skin
+type TokenLedgerEntry struct {
+	IdempotencyKey string `json:"idempotencyKey"`
+	ReservationId  string `json:"reservationId"`
+	UserId         string `json:"userId"`
+	ChampionId     string `json:"championId"`
+	SkinId         string `json:"skinId"`
+	SkinName       string `json:"skinName"`
+	ChromaName     string `json:"chromaName"`
+	FileName       string `json:"fileName"`
+	Status         string `json:"status"`
+	Remaining      int    `json:"remaining"`
+	Error          string `json:"error,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+	UpdatedAt      string `json:"updatedAt"`
+}
+
+// TokenLedger es el historial local de gastos de fichas, guardado en token-ledger.json
+type TokenLedger struct {
+	path string
+
+	mu      sync.Mutex
+	entries []TokenLedgerEntry
+}
+
+// NewTokenLedger carga el libro desde path (o su copia de seguridad)
+func NewTokenLedger(path string) (*TokenLedger, error) {
+	l := &TokenLedger{path: path, entries: []TokenLedgerEntry{}}
+	data, _, err := readFileWithBackups(path, MetadataBackupCount, validateJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return l, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &l.entries); err != nil {
+		return l, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return l, nil
+}
+
+// KeyFor devuelve la clave de idempotencia para descargar una skin. Reutiliza la
+// de una reserva del mismo usuario y skin que sigue pendiente, así un reintento no
+// se cobra de nuevo. Una reserva confirmada o devuelta nunca se reutiliza: las
+// skins ya pagadas se detectan con Owned y no reservan.
+func (l *TokenLedger) KeyFor(userId, championId, skinId string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	latest := l.latestLocked()
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		e := l.entries[i]
+		if e.UserId == userId && e.ChampionId == championId && e.SkinId == skinId &&
+			latest[e.IdempotencyKey].Status == LedgerReserved {
+			return e.IdempotencyKey
+		}
+	}
+	return newIdempotencyKey()
+}
+
+// Owned devuelve la entrada confirmada de userId para la skin, o nil si no la pagó
+func (l *TokenLedger) Owned(userId, championId, skinId string) *TokenLedgerEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.latestLocked() {
+		if e.UserId == userId && e.ChampionId == championId && e.SkinId == skinId && e.Status == LedgerCommitted {
+			return &e
+		}
+	}
+	return nil
+}
+
+// Status devuelve el último estado de la reserva con idempotencyKey, o "" si no existe
+func (l *TokenLedger) Status(idempotencyKey string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.latestLocked()[idempotencyKey].Status
+}
+
+// Record agrega una entrada y guarda el libro. El libro solo crece: cada cambio de
+// estado de una reserva es una entrada nueva y el estado vigente es el último.
+func (l *TokenLedger) Record(entry TokenLedgerEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now().Format(time.RFC3339)
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	l.entries = append(l.entries, entry)
+	return l.saveLocked()
+}
+
+// Pending devuelve las reservas que quedaron sin confirmar ni devolver
+func (l *TokenLedger) Pending(userId string) []TokenLedgerEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	latest := l.latestLocked()
+	pending := []TokenLedgerEntry{}
+	seen := make(map[string]bool)
+	for _, e := range l.entries {
+		if e.UserId != userId || seen[e.IdempotencyKey] {
+			continue
+		}
+		seen[e.IdempotencyKey] = true
+		if current := latest[e.IdempotencyKey]; current.Status == LedgerReserved {
+			pending = append(pending, current)
+		}
+	}
+	return pending
+}
+
+// latestLocked devuelve la última entrada de cada reserva, por clave de idempotencia
+func (l *TokenLedger) latestLocked() map[string]TokenLedgerEntry {
+	latest := make(map[string]TokenLedgerEntry, len(l.entries))
+	for _, e := range l.entries {
+		latest[e.IdempotencyKey] = e
+	}
+	return latest
+}
+
+// History devuelve las entradas de userId (todas si está vacío), de la más nueva a la
+// más vieja. Una misma reserva aparece una vez por cada cambio de estado.
+func (l *TokenLedger) History(userId string) []TokenLedgerEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	history := []TokenLedgerEntry{}
+	for i := len(l.entries) - 1; i >= 0; i-- { // Las entradas están en orden de llegada
+		if e := l.entries[i]; userId == "" || e.UserId == userId {
+			history = append(history, e)
+		}
+	}
+	return history
+}
+
+func (l *TokenLedger) saveLocked() error {
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling token ledger: %v", err)
+	}
+	if err := writeFileAtomic(l.path, data, 0644, MetadataBackupCount); err != nil {
+		return fmt.Errorf("error writing %s: %v", l.path, err)
+	}
+	return nil
+}
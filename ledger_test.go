@@ -0,0 +1,82 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestLedger(t *testing.T) *TokenLedger {
+	t.Helper()
+	l, err := NewTokenLedger(filepath.Join(t.TempDir(), "token-ledger.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLedgerKeyForReusesOnlyReserved(t *testing.T) {
+	l := newTestLedger(t)
+	entry := TokenLedgerEntry{UserId: "u1", ChampionId: "103", SkinId: "15", ReservationId: "r1", Status: LedgerReserved}
+	entry.IdempotencyKey = l.KeyFor("u1", "103", "15")
+	if err := l.Record(entry); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.KeyFor("u1", "103", "15"); got != entry.IdempotencyKey {
+		t.Errorf("pending reservation: got key %q, want %q", got, entry.IdempotencyKey)
+	}
+	if got := l.KeyFor("u2", "103", "15"); got == entry.IdempotencyKey {
+		t.Error("another user must not reuse the key")
+	}
+
+	entry.Status = LedgerCommitted
+	l.Record(entry)
+	if got := l.KeyFor("u1", "103", "15"); got == entry.IdempotencyKey {
+		t.Error("a committed reservation must not be reused")
+	}
+}
+
+func TestLedgerRecordAppends(t *testing.T) {
+	l := newTestLedger(t)
+	entry := TokenLedgerEntry{IdempotencyKey: "k1", UserId: "u1", ChampionId: "103", SkinId: "15", Status: LedgerReserved}
+	l.Record(entry)
+	entry.Status = LedgerCommitted
+	l.Record(entry)
+
+	history := l.History("u1")
+	if len(history) != 2 || history[0].Status != LedgerCommitted || history[1].Status != LedgerReserved {
+		t.Fatalf("history = %+v; want committed then reserved", history)
+	}
+	if got := l.Status("k1"); got != LedgerCommitted {
+		t.Errorf("Status = %q; want committed", got)
+	}
+	if owned := l.Owned("u1", "103", "15"); owned == nil || owned.IdempotencyKey != "k1" {
+		t.Errorf("Owned = %+v; want the committed entry", owned)
+	}
+	if len(l.Pending("u1")) != 0 {
+		t.Error("a committed reservation is not pending")
+	}
+
+	// El libro se vuelve a leer igual desde disco
+	reloaded, err := NewTokenLedger(l.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.History("")) != 2 {
+		t.Errorf("reloaded ledger has %d entries; want 2", len(reloaded.History("")))
+	}
+}
+
+func TestLedgerPendingUsesLatestStatus(t *testing.T) {
+	l := newTestLedger(t)
+	l.Record(TokenLedgerEntry{IdempotencyKey: "k1", UserId: "u1", SkinId: "1", Status: LedgerReserved})
+	l.Record(TokenLedgerEntry{IdempotencyKey: "k2", UserId: "u1", SkinId: "2", Status: LedgerReserved})
+	l.Record(TokenLedgerEntry{IdempotencyKey: "k1", UserId: "u1", SkinId: "1", Status: LedgerRefunded})
+
+	pending := l.Pending("u1")
+	if len(pending) != 1 || pending[0].IdempotencyKey != "k2" {
+		t.Errorf("Pending = %+v; want only k2", pending)
+	}
+	if l.Owned("u1", "", "1") != nil {
+		t.Error("a refunded reservation is not owned")
+	}
+}
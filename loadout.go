@@ -0,0 +1,554 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Formato de los paquetes de loadout (.shloadout, un zip con loadout.json y mods/)
+const (
+	LoadoutFormatVersion = 1
+	LoadoutManifestName  = "loadout.json"
+	LoadoutModsDir       = "mods/"
+	LoadoutFileExtension = ".shloadout"
+)
+
+// LoadoutSkin es una skin dentro del manifiesto de un loadout
+type LoadoutSkin struct {
+	Order       int    `json:"order"`
+	ChampionId  string `json:"championId"`
+	SkinId      string `json:"skinId"`
+	SkinName    string `json:"skinName"`
+	ChromaName  string `json:"chromaName"`
+	ImageUrl    string `json:"imageUrl"`
+	FileName    string `json:"fileName"`
+	Source      string `json:"source"`
+	Author      string `json:"author,omitempty"`
+	Version     string `json:"version,omitempty"`
+	CatalogPath string `json:"catalogPath,omitempty"` // Ruta en CatalogSkinsBucket (solo catálogo)
+	BundledFile string `json:"bundledFile,omitempty"` // Ruta dentro del paquete (solo custom incluidos)
+	SHA256      string `json:"sha256,omitempty"`      // Hash del archivo incluido
+}
+
+// LoadoutManifest es el contenido de loadout.json
+type LoadoutManifest struct {
+	FormatVersion int           `json:"formatVersion"`
+	CreatedAt     string        `json:"createdAt"`
+	Skins         []LoadoutSkin `json:"skins"`
+}
+
+// LoadoutChange es una skin del loadout que reemplaza a otra ya instalada para el mismo campeón
+type LoadoutChange struct {
+	ChampionId string      `json:"championId"`
+	Current    SkinInfo    `json:"current"`
+	Incoming   LoadoutSkin `json:"incoming"`
+}
+
+// LoadoutUnavailable es una skin del loadout que no se puede aplicar
+type LoadoutUnavailable struct {
+	Skin   LoadoutSkin `json:"skin"`
+	Reason string      `json:"reason"`
+}
+
+// LoadoutDiff compara un loadout con lo instalado actualmente
+type LoadoutDiff struct {
+	Add          []LoadoutSkin        `json:"add"`
+	Replace      []LoadoutChange      `json:"replace"`
+	Unchanged    []LoadoutSkin        `json:"unchanged"`
+	Unavailable  []LoadoutUnavailable `json:"unavailable"`
+	NotInLoadout []string             `json:"notInLoadout"` // Campeones instalados que el loadout no menciona
+	// AlreadyOwned son las skins del catálogo de Add y Replace que el usuario ya pagó
+	// y se vuelven a descargar sin gastar fichas
+	AlreadyOwned []LoadoutSkin `json:"alreadyOwned"`
+	TokenCost    int           `json:"tokenCost"` // Fichas que gasta aplicar el loadout
+}
+
+// SelectLoadoutSavePath abre un diálogo para elegir dónde exportar el loadout
+func (a *App) SelectLoadoutSavePath() (string, error) {
+	return runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export loadout",
+		DefaultFilename: "skinhunter-loadout" + LoadoutFileExtension,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Skin Hunter loadout (*" + LoadoutFileExtension + ")", Pattern: "*" + LoadoutFileExtension},
+		},
+	})
+}
+
+// SelectLoadoutFile abre un diálogo para elegir un loadout a importar
+func (a *App) SelectLoadoutFile() (string, error) {
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import loadout",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Skin Hunter loadout (*" + LoadoutFileExtension + ")", Pattern: "*" + LoadoutFileExtension},
+		},
+	})
+}
+
+// LoadoutResult es la respuesta de ExportLoadout e ImportLoadout
+type LoadoutResult struct {
+	Result
+	Path   string               `json:"path,omitempty"` // Archivo exportado
+	Diff   *LoadoutDiff         `json:"diff,omitempty"`
+	Failed []LoadoutUnavailable `json:"failed,omitempty"` // Skins del loadout que no se pudieron aplicar
+}
+
+// ExportLoadout escribe en destPath un paquete con el manifiesto de las skins
+// instaladas. Con includeCustomFiles también se incluyen los archivos de los mods
+// locales, que no se pueden volver a descargar del catálogo.
+func (a *App) ExportLoadout(destPath string, includeCustomFiles bool) LoadoutResult {
+	if strings.TrimSpace(destPath) == "" {
+		return LoadoutResult{Result: failResult(ErrInvalidArgument, "No destination selected", nil)}
+	}
+	installed := a.installedSkins.Snapshot()
+	a.logger(LogSubsystemStorage).Infof("ExportLoadout: Exporting %d skins to %s", len(installed), destPath)
+
+	championIds := make([]string, 0, len(installed))
+	for championId := range installed {
+		championIds = append(championIds, championId)
+	}
+	sort.Strings(championIds)
+
+	manifest := LoadoutManifest{
+		FormatVersion: LoadoutFormatVersion,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+		Skins:         []LoadoutSkin{},
+	}
+	bundled := make(map[string]string) // Ruta en el paquete -> ruta absoluta en disco
+	for i, championId := range championIds {
+		skin := installed[championId]
+		entry := LoadoutSkin{
+			Order:      i,
+			ChampionId: championId,
+			SkinId:     skin.SkinId,
+			SkinName:   skin.SkinName,
+			ChromaName: skin.ChromaName,
+			ImageUrl:   skin.ImageUrl,
+			FileName:   skin.FileName,
+			Source:     skin.Source,
+			Author:     skin.Author,
+			Version:    skin.Version,
+		}
+		if skin.Source == SkinSourceCustom {
+			if includeCustomFiles {
+				absFilePath := filepath.Join(absInstalledPath, skin.FileName)
+				sum, err := fileSHA256(absFilePath)
+				if err != nil {
+					return LoadoutResult{Result: failResult(ErrStorageFailed, fmt.Sprintf("Failed to read %s", skin.FileName), err)}
+				}
+				entry.BundledFile = LoadoutModsDir + skin.FileName
+				entry.SHA256 = sum
+				bundled[entry.BundledFile] = absFilePath
+			}
+		} else if skinNum, err := catalogSkinNum(skin.SkinId); err == nil {
+			entry.CatalogPath = catalogSkinPath(championId, skinNum)
+		}
+		manifest.Skins = append(manifest.Skins, entry)
+	}
+
+	if err := writeLoadoutArchive(destPath, manifest, bundled); err != nil {
+		a.logger(LogSubsystemStorage).Errorf("ExportLoadout: %v", err)
+		return LoadoutResult{Result: failResult(ErrStorageFailed, "Could not write loadout", err)}
+	}
+	return LoadoutResult{
+		Result: okResult(MsgLoadoutExported, len(manifest.Skins)),
+		Path:   destPath,
+	}
+}
+
+func writeLoadoutArchive(destPath string, manifest LoadoutManifest, bundled map[string]string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".loadout-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file: %v", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op tras el rename
+
+	zw := zip.NewWriter(tmp)
+	w, err := zw.Create(LoadoutManifestName)
+	if err == nil {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(manifest)
+	}
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s: %v", LoadoutManifestName, err)
+	}
+	for name, absPath := range bundled {
+		if err := copyModEntry(zw, fileEntry(name, absPath)); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error finishing loadout: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing loadout: %v", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return fmt.Errorf("error moving loadout to %s: %v", destPath, err)
+	}
+	return nil
+}
+
+// readLoadoutArchive abre un paquete y valida su manifiesto. El llamador debe cerrar el lector.
+func readLoadoutArchive(srcPath string) (*zip.ReadCloser, *LoadoutManifest, error) {
+	zr, err := zip.OpenReader(srcPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening loadout %s: %v", srcPath, err)
+	}
+	var manifest *LoadoutManifest
+	for _, f := range zr.File {
+		if f.Name != LoadoutManifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			zr.Close()
+			return nil, nil, fmt.Errorf("error reading %s: %v", LoadoutManifestName, err)
+		}
+		manifest = &LoadoutManifest{}
+		err = json.NewDecoder(rc).Decode(manifest)
+		rc.Close()
+		if err != nil {
+			zr.Close()
+			return nil, nil, fmt.Errorf("invalid %s: %v", LoadoutManifestName, err)
+		}
+		break
+	}
+	if manifest == nil {
+		zr.Close()
+		return nil, nil, fmt.Errorf("%s not found in loadout", LoadoutManifestName)
+	}
+	if manifest.FormatVersion > LoadoutFormatVersion {
+		zr.Close()
+		return nil, nil, fmt.Errorf("loadout format version %d is newer than supported (%d)", manifest.FormatVersion, LoadoutFormatVersion)
+	}
+	sort.SliceStable(manifest.Skins, func(i, j int) bool { return manifest.Skins[i].Order < manifest.Skins[j].Order })
+	return zr, manifest, nil
+}
+
+// diffLoadout compara el manifiesto con installedSkins. files son las rutas incluidas
+// en el paquete y userId el usuario con el que se calcula el costo en fichas.
+func (a *App) diffLoadout(manifest *LoadoutManifest, files map[string]*zip.File, userId string) *LoadoutDiff {
+	diff := &LoadoutDiff{
+		Add:          []LoadoutSkin{},
+		Replace:      []LoadoutChange{},
+		Unchanged:    []LoadoutSkin{},
+		Unavailable:  []LoadoutUnavailable{},
+		NotInLoadout: []string{},
+		AlreadyOwned: []LoadoutSkin{},
+	}
+	installedSkins := a.installedSkins.Snapshot()
+	inLoadout := make(map[string]bool)
+	for _, skin := range manifest.Skins {
+		inLoadout[skin.ChampionId] = true
+		if skin.ChampionId == "" || !isPlainFileName(skin.FileName) || strings.HasPrefix(skin.FileName, stagingFilePrefix) {
+			diff.Unavailable = append(diff.Unavailable, LoadoutUnavailable{Skin: skin, Reason: "Invalid entry"})
+			continue
+		}
+		skinNum := ""
+		if skin.Source == SkinSourceCustom {
+			if skin.BundledFile == "" || files[skin.BundledFile] == nil {
+				diff.Unavailable = append(diff.Unavailable, LoadoutUnavailable{Skin: skin, Reason: "Custom mod file not included in loadout"})
+				continue
+			}
+		} else {
+			var err error
+			if skinNum, err = catalogSkinNum(skin.SkinId); err != nil {
+				diff.Unavailable = append(diff.Unavailable, LoadoutUnavailable{Skin: skin, Reason: "Invalid entry"})
+				continue
+			}
+		}
+
+		current, installed := installedSkins[skin.ChampionId]
+		switch {
+		case !installed:
+			diff.Add = append(diff.Add, skin)
+		case current.SkinId == skin.SkinId && current.ChromaName == skin.ChromaName && current.FileName == skin.FileName:
+			diff.Unchanged = append(diff.Unchanged, skin)
+			continue
+		default:
+			diff.Replace = append(diff.Replace, LoadoutChange{ChampionId: skin.ChampionId, Current: current, Incoming: skin})
+		}
+		if skin.Source != SkinSourceCustom {
+			if userId != "" && a.ledger.Owned(userId, skin.ChampionId, skinNum) != nil {
+				diff.AlreadyOwned = append(diff.AlreadyOwned, skin)
+			} else {
+				diff.TokenCost++
+			}
+		}
+	}
+	for championId := range installedSkins {
+		if !inLoadout[championId] {
+			diff.NotInLoadout = append(diff.NotInLoadout, championId)
+		}
+	}
+	sort.Strings(diff.NotInLoadout)
+	return diff
+}
+
+// loadoutUserId devuelve userId o, si viene vacío, el usuario de la sesión guardada
+func (a *App) loadoutUserId(userId string) string {
+	if userId != "" {
+		return userId
+	}
+	if session := a.session.Current(); session != nil {
+		return session.UserID
+	}
+	return ""
+}
+
+// PreviewLoadout lee un paquete y devuelve qué cambiaría al aplicarlo y cuántas
+// fichas gastaría para userId (o el usuario de la sesión), sin tocar nada
+func (a *App) PreviewLoadout(srcPath, userId string) LoadoutResult {
+	zr, manifest, err := readLoadoutArchive(srcPath)
+	if err != nil {
+		return LoadoutResult{Result: failResult(ErrLoadoutInvalid, "Invalid loadout", err)}
+	}
+	defer zr.Close()
+	return LoadoutResult{Result: okResult(""), Diff: a.diffLoadout(manifest, loadoutFiles(zr), a.loadoutUserId(userId))}
+}
+
+func loadoutFiles(zr *zip.ReadCloser) map[string]*zip.File {
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		if strings.HasPrefix(f.Name, LoadoutModsDir) && path.Base(f.Name) == strings.TrimPrefix(f.Name, LoadoutModsDir) {
+			files[f.Name] = f
+		}
+	}
+	return files
+}
+
+// loadoutStaged es una skin del loadout ya descargada o extraída en installed/
+// con un nombre de staging, lista para moverse a su nombre final
+type loadoutStaged struct {
+	skin        LoadoutSkin
+	stagingPath string
+}
+
+// ImportLoadout aplica un paquete: descarga del catálogo las skins que faltan o
+// cambian, copia los mods locales incluidos y recrea el overlay. Con removeOthers
+// también desinstala las skins que el loadout no menciona.
+//
+// acceptedTokenCost es el TokenCost que el usuario vio en PreviewLoadout; si aplicar
+// el loadout cuesta más, no se descarga nada. Todas las skins se descargan primero
+// con un nombre de staging y solo se instalan si ninguna falló, así un error a mitad
+// de camino no deja el loadout aplicado a medias. Las fichas de las skins que sí se
+// descargaron quedan gastadas, pero un reintento las vuelve a bajar sin cobrar.
+func (a *App) ImportLoadout(srcPath, userId, token string, removeOthers bool, acceptedTokenCost int) LoadoutResult {
+	zr, manifest, err := readLoadoutArchive(srcPath)
+	if err != nil {
+		return LoadoutResult{Result: failResult(ErrLoadoutInvalid, "Invalid loadout", err)}
+	}
+	defer zr.Close()
+
+	files := loadoutFiles(zr)
+	diff := a.diffLoadout(manifest, files, a.loadoutUserId(userId))
+	a.logger(LogSubsystemStorage).Infof("ImportLoadout: %d to add, %d to replace, %d unchanged, %d unavailable, %d tokens",
+		len(diff.Add), len(diff.Replace), len(diff.Unchanged), len(diff.Unavailable), diff.TokenCost)
+	if diff.TokenCost > acceptedTokenCost {
+		return LoadoutResult{Result: failResult(ErrLoadoutCostChanged,
+			fmt.Sprintf("Loadout costs %d tokens, %d accepted", diff.TokenCost, acceptedTokenCost), nil), Diff: diff}
+	}
+
+	pending := append([]LoadoutSkin{}, diff.Add...)
+	for _, change := range diff.Replace {
+		pending = append(pending, change.Incoming)
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].Order < pending[j].Order })
+	if len(pending) == 0 && (!removeOthers || len(diff.NotInLoadout) == 0) {
+		return loadoutImportResult(okResult(MsgLoadoutNothingToApply), diff, nil)
+	}
+	if err := a.checkOverlayNotInGame(); err != nil {
+		return LoadoutResult{Result: errorResult(err), Diff: diff}
+	}
+
+	staged := make([]loadoutStaged, 0, len(pending))
+	discardStaged := func() {
+		for _, s := range staged {
+			os.Remove(s.stagingPath)
+		}
+	}
+	failed := []LoadoutUnavailable{}
+	for i, skin := range pending {
+		stagingName := fmt.Sprintf("%s%d-%s", stagingFilePrefix, i, skin.FileName)
+		if err := a.fetchLoadoutSkin(skin, files, stagingName, userId, token); err != nil {
+			a.logger(LogSubsystemStorage).Warningf("ImportLoadout: Could not fetch %s for champion %s: %v", skin.FileName, skin.ChampionId, err)
+			failed = append(failed, LoadoutUnavailable{Skin: skin, Reason: err.Error()})
+			os.Remove(filepath.Join(absInstalledPath, stagingName))
+			continue
+		}
+		staged = append(staged, loadoutStaged{skin: skin, stagingPath: filepath.Join(absInstalledPath, stagingName)})
+	}
+	if len(failed) > 0 {
+		discardStaged()
+		return loadoutImportResult(failResult(ErrDownloadFailed, "Loadout not applied", nil), diff, failed)
+	}
+
+	a.logger(LogSubsystemStorage).Info("ImportLoadout: Stopping overlay before applying loadout...")
+	if killed, killErr := a.killModTools(); !killed {
+		a.logger(LogSubsystemStorage).Warningf("Failed to stop overlay before loadout import: %v. Proceeding anyway.", killErr)
+	}
+
+	// El nombre del manifiesto no es de fiar: nunca pisa un archivo de otra skin instalada
+	previous := a.installedSkins.Snapshot()
+	installed := make([]string, 0, len(staged))
+	for _, s := range staged {
+		fileName := a.uniqueInstalledFileName(s.skin.FileName, previous[s.skin.ChampionId].FileName)
+		if err := os.Rename(s.stagingPath, filepath.Join(absInstalledPath, fileName)); err != nil {
+			discardStaged()
+			for j, name := range installed {
+				if name != previous[staged[j].skin.ChampionId].FileName {
+					os.Remove(filepath.Join(absInstalledPath, name))
+				}
+			}
+			return LoadoutResult{Result: failResult(ErrStorageFailed, "Could not install loadout files", err), Diff: diff}
+		}
+		installed = append(installed, fileName)
+	}
+	for i, s := range staged {
+		source := s.skin.Source
+		if source == "" {
+			source = SkinSourceCatalog
+		}
+		a.installedSkins.Set(s.skin.ChampionId, SkinInfo{
+			SkinId:     s.skin.SkinId,
+			FileName:   installed[i],
+			ProcessId:  "0",
+			ChromaName: s.skin.ChromaName,
+			SkinName:   s.skin.SkinName,
+			ImageUrl:   s.skin.ImageUrl,
+			Source:     source,
+			Author:     s.skin.Author,
+			Version:    s.skin.Version,
+		})
+	}
+
+	removed := 0
+	if removeOthers {
+		for _, championId := range diff.NotInLoadout {
+			a.installedSkins.Delete(championId)
+			removed++
+		}
+	}
+
+	if err := a.saveInstalledSkins(); err != nil {
+		return LoadoutResult{Result: failResult(ErrStorageFailed, "Failed to save installed skins", err), Diff: diff}
+	}
+	// Los archivos reemplazados o quitados se borran solo cuando installed.json ya no los nombra
+	for _, skin := range previous {
+		if skin.FileName != "" && !a.isInstalledFileReferenced(skin.FileName) {
+			os.Remove(filepath.Join(absInstalledPath, skin.FileName))
+		}
+	}
+
+	a.logger(LogSubsystemStorage).Info("ImportLoadout: Creating overlay...")
+	if err := a.buildOverlay(); err != nil {
+		return LoadoutResult{Result: errorResult(err), Diff: diff}
+	}
+	if _, err := a.restartModTools(); err != nil {
+		return LoadoutResult{Result: failResult(ErrModToolsStartFailed, "Failed to start overlay after loadout import", err), Diff: diff}
+	}
+
+	return loadoutImportResult(okResult(MsgLoadoutApplied, len(staged), removed, 0), diff, nil)
+}
+
+// loadoutImportResult es la respuesta final de ImportLoadout: falla si alguna skin no se pudo aplicar
+func loadoutImportResult(notice Result, diff *LoadoutDiff, failed []LoadoutUnavailable) LoadoutResult {
+	result := LoadoutResult{Result: notice, Diff: diff, Failed: failed}
+	if len(failed) > 0 {
+		result.Result = failResult(ErrDownloadFailed, notice.Message, nil)
+	}
+	return result
+}
+
+// fetchLoadoutSkin deja en installed/stagingName el archivo de una skin del loadout:
+// los mods locales se copian del paquete y los del catálogo se descargan. Las skins
+// que el usuario ya pagó no gastan otra ficha (ver DownloadSkin); el libro de
+// fichas anota skin.FileName, no el nombre de staging.
+func (a *App) fetchLoadoutSkin(skin LoadoutSkin, files map[string]*zip.File, stagingName, userId, token string) error {
+	absFilePath := filepath.Join(absInstalledPath, stagingName)
+	if skin.Source == SkinSourceCustom {
+		if err := extractLoadoutFile(files[skin.BundledFile], absFilePath, skin.SHA256); err != nil {
+			return err
+		}
+	} else {
+		skinNum, err := catalogSkinNum(skin.SkinId)
+		if err != nil {
+			return err
+		}
+		result := a.downloadSkinAs(stagingName, skin.ChampionId, skinNum, userId, token, skin.ChromaName, skin.FileName, skin.SkinName)
+		if err := result.Err(); err != nil {
+			return err
+		}
+	}
+	return a.importModFile(absFilePath)
+}
+
+// extractLoadoutFile copia un mod incluido en el paquete a destPath verificando su hash
+func extractLoadoutFile(f *zip.File, destPath, expectedSHA256 string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("error reading %s from loadout: %v", f.Name, err)
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".loadout-mod-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file: %v", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op tras el rename
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hasher), rc); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error extracting %s: %v", f.Name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error extracting %s: %v", f.Name, err)
+	}
+	if expectedSHA256 != "" && hex.EncodeToString(hasher.Sum(nil)) != expectedSHA256 {
+		return fmt.Errorf("checksum mismatch for %s", f.Name)
+	}
+	return os.Rename(tmpPath, destPath)
+}
+
+// isInstalledFileReferenced indica si alguna entrada de installedSkins usa fileName
+func (a *App) isInstalledFileReferenced(fileName string) bool {
+	for _, skin := range a.installedSkins.Snapshot() {
+		if skin.FileName == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+// fileSHA256 devuelve el hash SHA-256 en hexadecimal del archivo en p
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestLoadout escribe un paquete con manifest y los mods de bundled (ruta en el paquete -> contenido)
+func writeTestLoadout(t *testing.T, skins []LoadoutSkin, bundled map[string][]byte) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := make(map[string]string)
+	for name, content := range bundled {
+		p := filepath.Join(dir, filepath.Base(name))
+		if err := os.WriteFile(p, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		files[name] = p
+	}
+	destPath := filepath.Join(dir, "test"+LoadoutFileExtension)
+	manifest := LoadoutManifest{FormatVersion: LoadoutFormatVersion, Skins: skins}
+	if err := writeLoadoutArchive(destPath, manifest, files); err != nil {
+		t.Fatal(err)
+	}
+	return destPath
+}
+
+func TestPreviewLoadoutTokenCost(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.ledger = newTestLedger(t)
+	// El libro anota el número de skin del catálogo; installed.json y el loadout, el ID completo
+	a.ledger.Record(TokenLedgerEntry{IdempotencyKey: "k1", UserId: "u1", ChampionId: "222", SkinId: "3", Status: LedgerCommitted})
+	a.installedSkins.Set("103", SkinInfo{SkinId: "103015", FileName: "ahri.fantome"})
+
+	path := writeTestLoadout(t, []LoadoutSkin{
+		{Order: 0, ChampionId: "103", SkinId: "103015", FileName: "ahri.fantome"},
+		{Order: 1, ChampionId: "222", SkinId: "222003", FileName: "jinx.fantome"},
+		{Order: 2, ChampionId: "157", SkinId: "157001", FileName: "yasuo.fantome"},
+		{Order: 3, ChampionId: CustomSkinKeyPrefix + "mod", FileName: "mod.fantome", Source: SkinSourceCustom, BundledFile: LoadoutModsDir + "mod.fantome"},
+		{Order: 4, ChampionId: "1", SkinId: "1002", FileName: "../annie.fantome"},
+		{Order: 5, ChampionId: "2", SkinId: "2002", FileName: stagingFilePrefix + "olaf.fantome"},
+		{Order: 6, ChampionId: "3", SkinId: "galio", FileName: "galio.fantome"},
+	}, map[string][]byte{LoadoutModsDir + "mod.fantome": []byte("mod")})
+
+	preview := a.PreviewLoadout(path, "u1")
+	if !preview.Success {
+		t.Fatalf("PreviewLoadout = %+v", preview.Result)
+	}
+	diff := preview.Diff
+	if len(diff.Add) != 3 || len(diff.Unchanged) != 1 || len(diff.Unavailable) != 3 {
+		t.Errorf("add=%d unchanged=%d unavailable=%d", len(diff.Add), len(diff.Unchanged), len(diff.Unavailable))
+	}
+	if diff.TokenCost != 1 {
+		t.Errorf("TokenCost = %d, want 1 (owned, custom and unchanged skins are free)", diff.TokenCost)
+	}
+	if len(diff.AlreadyOwned) != 1 || diff.AlreadyOwned[0].ChampionId != "222" {
+		t.Errorf("AlreadyOwned = %+v", diff.AlreadyOwned)
+	}
+
+	// Otro usuario no tiene la skin pagada
+	if diff := a.PreviewLoadout(path, "u2").Diff; diff.TokenCost != 2 {
+		t.Errorf("TokenCost for another user = %d, want 2", diff.TokenCost)
+	}
+}
+
+func TestImportLoadoutRefusesHigherCost(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.ledger = newTestLedger(t)
+	path := writeTestLoadout(t, []LoadoutSkin{{ChampionId: "157", SkinId: "157001", FileName: "yasuo.fantome"}}, nil)
+
+	result := a.ImportLoadout(path, "u1", "", false, 0)
+	if result.Code != ErrLoadoutCostChanged {
+		t.Fatalf("code = %q, want %q", result.Code, ErrLoadoutCostChanged)
+	}
+	if result.Diff == nil || result.Diff.TokenCost != 1 {
+		t.Errorf("diff = %+v", result.Diff)
+	}
+}
+
+// Si una skin no se puede descargar no se instala ninguna, y el mod incluido con el
+// nombre de un archivo ya instalado no lo pisa
+func TestImportLoadoutFailedFetchAppliesNothing(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.ledger = newTestLedger(t)
+	a.backend = NewMemoryBackend() // No reconoce el token
+	ahri := SkinInfo{SkinId: "103015", FileName: "ahri.fantome"}
+	a.installedSkins.Set("103", ahri)
+	if err := os.WriteFile(filepath.Join(absInstalledPath, ahri.FileName), []byte("ahri"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := writeTestLoadout(t, []LoadoutSkin{
+		{Order: 0, ChampionId: CustomSkinKeyPrefix + "mod", FileName: "ahri.fantome", Source: SkinSourceCustom, BundledFile: LoadoutModsDir + "ahri.fantome"},
+		{Order: 1, ChampionId: "157", SkinId: "157001", FileName: "yasuo.fantome"},
+	}, map[string][]byte{LoadoutModsDir + "ahri.fantome": []byte("not ahri")})
+
+	result := a.ImportLoadout(path, "u1", "not-a-jwt", true, 1)
+	if result.Success || len(result.Failed) != 2 {
+		t.Fatalf("expected both skins to fail, got %+v", result)
+	}
+	if a.installedSkins.Len() != 1 {
+		t.Errorf("installed skins changed: %+v", a.installedSkins.Snapshot())
+	}
+	if got, _ := a.installedSkins.Get("103"); got != ahri {
+		t.Errorf("skin 103 = %+v", got)
+	}
+	entries, err := os.ReadDir(absInstalledPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != ahri.FileName {
+		t.Errorf("installed/ has leftovers: %v", entries)
+	}
+	if content, _ := os.ReadFile(filepath.Join(absInstalledPath, ahri.FileName)); string(content) != "ahri" {
+		t.Errorf("ahri.fantome was overwritten: %q", content)
+	}
+}
+
+func TestExportLoadoutCatalogPath(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	a.installedSkins.Set("103", SkinInfo{SkinId: "103015", FileName: "ahri.fantome", Source: SkinSourceCatalog})
+	a.installedSkins.Set(CustomSkinKeyPrefix+"mod", SkinInfo{FileName: "mod.fantome", Source: SkinSourceCustom})
+
+	dest := filepath.Join(t.TempDir(), "out"+LoadoutFileExtension)
+	if result := a.ExportLoadout(dest, false); !result.Success {
+		t.Fatalf("ExportLoadout = %+v", result)
+	}
+	zr, manifest, err := readLoadoutArchive(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr.Close()
+	paths := map[string]string{}
+	for _, skin := range manifest.Skins {
+		paths[skin.ChampionId] = skin.CatalogPath
+	}
+	if paths["103"] != "campeones/103/15.fantome" || paths[CustomSkinKeyPrefix+"mod"] != "" {
+		t.Errorf("catalog paths = %v", paths)
+	}
+}
+
+// Aplicar un loadout descarga con el número de skin y el libro de fichas anota el
+// nombre final del archivo, no el de staging
+func TestImportLoadoutRecordsFinalFileName(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 1)
+	useFakeModTools(t)
+	login(t, a)
+	backend.PutObject(a.currentSettings().Backend.SkinsBucket, catalogSkinPath("157", "1"), []byte("yasuo"))
+	path := writeTestLoadout(t, []LoadoutSkin{{ChampionId: "157", SkinId: "157001", FileName: "yasuo.fantome"}}, nil)
+
+	if preview := a.PreviewLoadout(path, ""); !preview.Success || preview.Diff.TokenCost != 1 {
+		t.Fatalf("PreviewLoadout = %+v", preview)
+	}
+	// Arrancar el overlay usa cmd.exe: fuera de Windows solo falla ese último paso
+	if result := a.ImportLoadout(path, "", "", false, 1); !result.Success && result.Code != ErrModToolsStartFailed {
+		t.Fatalf("ImportLoadout = %+v", result)
+	}
+	if content, _ := os.ReadFile(filepath.Join(absInstalledPath, "yasuo.fantome")); string(content) != "yasuo" {
+		t.Errorf("yasuo.fantome = %q", content)
+	}
+	owned := a.ledger.Owned(testUserID, "157", "1")
+	if owned == nil || owned.FileName != "yasuo.fantome" {
+		t.Errorf("ledger entry = %+v", owned)
+	}
+	// Ya pagada: volver a aplicarla después de quitarla no cuesta fichas
+	a.installedSkins.Delete("157")
+	if diff := a.PreviewLoadout(path, "").Diff; diff.TokenCost != 0 || len(diff.AlreadyOwned) != 1 {
+		t.Errorf("after import: TokenCost = %d, AlreadyOwned = %+v", diff.TokenCost, diff.AlreadyOwned)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package main
+
+import "os"
+
+// systemLocaleName devuelve el idioma de las variables de entorno POSIX (p. ej. "es_AR.UTF-8")
+func systemLocaleName() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" && value != "C" && value != "POSIX" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package main
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// systemLocaleName devuelve el idioma del usuario de Windows (p. ej. "es-MX")
+func systemLocaleName() string {
+	const localeNameMaxLength = 85 // LOCALE_NAME_MAX_LENGTH
+	buf := make([]uint16, localeNameMaxLength)
+	kernel32 := windows.NewLazySystemDLL("kernel32.dll")
+	ret, _, _ := kernel32.NewProc("GetUserDefaultLocaleName").Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if ret == 0 {
+		return ""
+	}
+	return windows.UTF16ToString(buf)
+}
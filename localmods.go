@@ -0,0 +1,415 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// stagingFilePrefix antecede los archivos a medio importar dentro de installed/
+const stagingFilePrefix = ".staging-"
+
+// CustomSkinKeyPrefix antecede la clave de installedSkins de los mods locales,
+// que no están atados a un campeón del catálogo.
+const CustomSkinKeyPrefix = "custom:"
+
+// modInfo es el contenido de META/info.json dentro de un .fantome
+type modInfo struct {
+	Name        string `json:"Name"`
+	Author      string `json:"Author"`
+	Version     string `json:"Version"`
+	Description string `json:"Description"`
+}
+
+// modArchiveEntry es un archivo que terminará dentro del .fantome normalizado
+type modArchiveEntry struct {
+	name string // Ruta dentro del zip (META/..., WAD/..., RAW/...)
+	open func() (io.ReadCloser, error)
+}
+
+var regexSlugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify convierte un nombre de mod en un nombre de archivo seguro
+func slugify(name string) string {
+	slug := strings.Trim(regexSlugInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = "custom-mod"
+	}
+	return slug
+}
+
+// SelectLocalMod abre un diálogo para elegir un mod local (.fantome, .zip, .wad.client)
+func (a *App) SelectLocalMod() (string, error) {
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import local mod",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "League mods (*.fantome;*.zip;*.wad.client)", Pattern: "*.fantome;*.zip;*.wad.client"},
+		},
+	})
+}
+
+// SelectLocalModFolder abre un diálogo para elegir la carpeta de un mod descomprimido
+func (a *App) SelectLocalModFolder() (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{Title: "Import unpacked mod folder"})
+}
+
+// LocalModResult es la respuesta de ImportLocalMod
+type LocalModResult struct {
+	Result
+	ChampionId string    `json:"championId,omitempty"` // Clave de la entrada en installed.json
+	Skin       *SkinInfo `json:"skin,omitempty"`
+}
+
+// ImportLocalMod importa un mod del usuario (.fantome, .zip, .wad.client o carpeta
+// descomprimida). Lo normaliza a un .fantome en installed/, lo pasa por
+// "mod-tools import" y lo registra como entrada "custom" junto a las del catálogo.
+func (a *App) ImportLocalMod(srcPath string) LocalModResult {
+	srcPath = strings.TrimSpace(srcPath)
+	if srcPath == "" {
+		return LocalModResult{Result: failResult(ErrInvalidArgument, "No mod selected", nil)}
+	}
+	if err := a.checkOverlayNotInGame(); err != nil {
+		return LocalModResult{Result: errorResult(err)}
+	}
+	a.logger(LogSubsystemSkins).Infof("ImportLocalMod: Importing %s", srcPath)
+
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return LocalModResult{Result: failResult(ErrNotFound, fmt.Sprintf("Mod not found at %s", srcPath), nil)}
+	}
+
+	var entries []modArchiveEntry
+	var closer io.Closer
+	lower := strings.ToLower(srcPath)
+	switch {
+	case info.IsDir():
+		entries, err = modEntriesFromDir(srcPath)
+	case strings.HasSuffix(lower, ".wad.client"):
+		entries = []modArchiveEntry{fileEntry("WAD/"+filepath.Base(srcPath), srcPath)}
+	case strings.HasSuffix(lower, ".fantome"), strings.HasSuffix(lower, ".zip"):
+		var zr *zip.ReadCloser
+		zr, err = zip.OpenReader(srcPath)
+		if err == nil {
+			closer = zr
+			entries, err = modEntriesFromZip(&zr.Reader)
+		}
+	default:
+		err = fmt.Errorf("unsupported mod type: %s", filepath.Base(srcPath))
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+	if err != nil {
+		a.logger(LogSubsystemSkins).Errorf("ImportLocalMod: %v", err)
+		return LocalModResult{Result: failResult(ErrModInvalid, "Invalid mod", err)}
+	}
+
+	meta, err := readModInfo(entries)
+	if err != nil {
+		return LocalModResult{Result: failResult(ErrModInvalid, "Invalid mod", err)}
+	}
+	if meta.Name == "" {
+		meta.Name = defaultModName(srcPath)
+	}
+
+	key := CustomSkinKeyPrefix + slugify(meta.Name)
+	previous, replacing := a.installedSkins.Get(key)
+	fileName := a.uniqueInstalledFileName(slugify(meta.Name)+".fantome", previous.FileName)
+	absFilePath := filepath.Join(absInstalledPath, fileName)
+
+	// Se escribe e importa con otro nombre: si fileName es el del mod que se
+	// reemplaza, el archivo anterior sigue intacto hasta que la importación sale bien
+	stagingPath := filepath.Join(absInstalledPath, stagingFilePrefix+fileName)
+	defer os.Remove(stagingPath) // No-op tras el rename
+	if err := writeFantome(stagingPath, entries, meta); err != nil {
+		a.logger(LogSubsystemSkins).Errorf("ImportLocalMod: %v", err)
+		return LocalModResult{Result: failResult(ErrStorageFailed, "Could not write mod", err)}
+	}
+
+	a.logger(LogSubsystemSkins).Info("ImportLocalMod: Running mod-tools import...")
+	if err := a.importModFile(stagingPath); err != nil {
+		return LocalModResult{Result: errorResult(err)}
+	}
+	if err := os.Rename(stagingPath, absFilePath); err != nil {
+		a.logger(LogSubsystemSkins).Errorf("ImportLocalMod: %v", err)
+		return LocalModResult{Result: failResult(ErrStorageFailed, "Could not write mod", err)}
+	}
+
+	skin := SkinInfo{
+		FileName:  fileName,
+		ProcessId: "0",
+		SkinName:  meta.Name,
+		Source:    SkinSourceCustom,
+		Author:    meta.Author,
+		Version:   meta.Version,
+	}
+	a.installedSkins.Set(key, skin)
+	if err := a.saveInstalledSkins(); err != nil {
+		return LocalModResult{Result: failResult(ErrStorageFailed, "Failed to save installed skins", err)}
+	}
+	if replacing && previous.FileName != "" && previous.FileName != fileName {
+		os.Remove(filepath.Join(absInstalledPath, previous.FileName))
+	}
+
+	a.logger(LogSubsystemSkins).Info("ImportLocalMod: Creating overlay...")
+	if err := a.buildOverlay(); err != nil {
+		return LocalModResult{Result: errorResult(err)}
+	}
+	if _, err := a.restartModTools(); err != nil {
+		return LocalModResult{Result: failResult(ErrModToolsStartFailed, "Failed to start overlay after import", err)}
+	}
+
+	return LocalModResult{
+		Result:     okResult(MsgModImported),
+		ChampionId: key,
+		Skin:       &skin,
+	}
+}
+
+// uniqueInstalledFileName devuelve name o name-2, name-3... si ya existe en
+// installed/. keep es el archivo de la entrada que se está reemplazando y sí se puede reutilizar.
+func (a *App) uniqueInstalledFileName(name, keep string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; ; i++ {
+		if candidate == keep {
+			return candidate
+		}
+		if _, err := os.Stat(filepath.Join(absInstalledPath, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
+
+// defaultModName deriva un nombre legible cuando el mod no trae META/info.json
+func defaultModName(srcPath string) string {
+	name := filepath.Base(srcPath)
+	for _, ext := range []string{".wad.client", ".fantome", ".zip"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+func fileEntry(name, absPath string) modArchiveEntry {
+	return modArchiveEntry{name: name, open: func() (io.ReadCloser, error) { return os.Open(absPath) }}
+}
+
+// modEntriesFromDir recorre una carpeta de mod descomprimida. Acepta la
+// estructura de fantome (META/, WAD/, RAW/) o una carpeta con .wad.client sueltos.
+func modEntriesFromDir(dir string) ([]modArchiveEntry, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading mod folder %s: %v", dir, err)
+	}
+
+	names, err := normalizeModLayout(files)
+	if err != nil {
+		return nil, err
+	}
+	var entries []modArchiveEntry
+	for _, rel := range files {
+		if target, ok := names[rel]; ok {
+			entries = append(entries, fileEntry(target, filepath.Join(dir, filepath.FromSlash(rel))))
+		}
+	}
+	return entries, nil
+}
+
+// modEntriesFromZip lee un .fantome o .zip, incluso si el contenido viene dentro de una carpeta raíz
+func modEntriesFromZip(zr *zip.Reader) ([]modArchiveEntry, error) {
+	var files []string
+	byName := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, f.Name)
+		byName[f.Name] = f
+	}
+
+	names, err := normalizeModLayout(files)
+	if err != nil {
+		return nil, err
+	}
+	var entries []modArchiveEntry
+	for _, name := range files {
+		target, ok := names[name]
+		if !ok {
+			continue
+		}
+		f := byName[name]
+		entries = append(entries, modArchiveEntry{name: target, open: f.Open})
+	}
+	return entries, nil
+}
+
+// normalizeModLayout decide la ruta dentro del .fantome de cada archivo del mod.
+// Quita una carpeta raíz común ("MiMod/WAD/..." -> "WAD/...") y mueve los
+// .wad.client sueltos a WAD/. Los archivos fuera de META/, WAD/ y RAW/ se descartan.
+// Dos archivos con el mismo destino (p. ej. dos .wad.client sueltos con el mismo
+// nombre en carpetas distintas) son un error: el overlay cargaría solo uno.
+func normalizeModLayout(files []string) (map[string]string, error) {
+	prefix, found := "", false
+	for _, f := range files {
+		clean := path.Clean(f)
+		if clean == ".." || strings.HasPrefix(clean, "../") || path.IsAbs(clean) {
+			return nil, fmt.Errorf("invalid path in mod: %s", f)
+		}
+		for _, dir := range []string{"META/", "WAD/", "RAW/"} {
+			if i := strings.Index(strings.ToUpper(clean), dir); i >= 0 && (i == 0 || clean[i-1] == '/') {
+				if !found || len(clean[:i]) < len(prefix) {
+					prefix, found = clean[:i], true
+				}
+			}
+		}
+	}
+
+	names := make(map[string]string)
+	targets := make(map[string]string) // Destino en minúsculas -> archivo de origen
+	for _, f := range files {
+		clean := strings.TrimPrefix(path.Clean(f), prefix)
+		upper := strings.ToUpper(clean)
+		var target string
+		switch {
+		case strings.HasPrefix(upper, "META/"), strings.HasPrefix(upper, "WAD/"), strings.HasPrefix(upper, "RAW/"):
+			top := upper[:strings.Index(upper, "/")]
+			target = top + clean[len(top):]
+		case strings.HasSuffix(strings.ToLower(clean), ".wad.client"):
+			target = "WAD/" + path.Base(clean)
+		default:
+			continue
+		}
+		if other, ok := targets[strings.ToLower(target)]; ok {
+			return nil, fmt.Errorf("duplicate file in mod: %s and %s both map to %s", other, f, target)
+		}
+		targets[strings.ToLower(target)] = f
+		names[f] = target
+	}
+
+	hasContent := false
+	for _, target := range names {
+		if !strings.HasPrefix(target, "META/") {
+			hasContent = true
+			break
+		}
+	}
+	if !hasContent {
+		return nil, fmt.Errorf("no WAD or RAW content found in mod")
+	}
+	return names, nil
+}
+
+// readModInfo lee META/info.json si el mod lo trae
+func readModInfo(entries []modArchiveEntry) (modInfo, error) {
+	var meta modInfo
+	for _, e := range entries {
+		if !strings.EqualFold(e.name, "META/info.json") {
+			continue
+		}
+		rc, err := e.open()
+		if err != nil {
+			return meta, fmt.Errorf("error reading META/info.json: %v", err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return meta, fmt.Errorf("error reading META/info.json: %v", err)
+		}
+		// Algunos editores guardan info.json con BOM
+		data = []byte(strings.TrimPrefix(string(data), "\ufeff"))
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return meta, fmt.Errorf("invalid META/info.json: %v", err)
+		}
+		break
+	}
+	return meta, nil
+}
+
+// writeFantome escribe el .fantome normalizado en destPath con un META/info.json
+// completo. Se escribe a un temporal y se renombra para no dejar archivos a medias.
+func writeFantome(destPath string, entries []modArchiveEntry, meta modInfo) error {
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".import-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file: %v", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op tras el rename
+
+	zw := zip.NewWriter(tmp)
+	metaWriter, err := zw.Create("META/info.json")
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing META/info.json: %v", err)
+	}
+	metaJson, _ := json.MarshalIndent(meta, "", "  ")
+	if _, err := metaWriter.Write(metaJson); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing META/info.json: %v", err)
+	}
+
+	for _, e := range entries {
+		if strings.EqualFold(e.name, "META/info.json") {
+			continue // Reemplazado arriba
+		}
+		if err := copyModEntry(zw, e); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error finishing fantome: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error syncing fantome: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error closing fantome: %v", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return fmt.Errorf("error moving fantome to %s: %v", destPath, err)
+	}
+	return nil
+}
+
+func copyModEntry(zw *zip.Writer, e modArchiveEntry) error {
+	rc, err := e.open()
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", e.name, err)
+	}
+	defer rc.Close()
+	w, err := zw.Create(e.name)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", e.name, err)
+	}
+	if _, err := io.Copy(w, rc); err != nil {
+		return fmt.Errorf("error writing %s: %v", e.name, err)
+	}
+	return nil
+}
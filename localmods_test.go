@@ -0,0 +1,197 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeModLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  map[string]string
+		err   string
+	}{
+		{"fantome", []string{"META/info.json", "WAD/Ahri.wad.client", "RAW/assets/x.bin"},
+			map[string]string{"META/info.json": "META/info.json", "WAD/Ahri.wad.client": "WAD/Ahri.wad.client", "RAW/assets/x.bin": "RAW/assets/x.bin"}, ""},
+		{"nested root", []string{"MyMod/meta/info.json", "MyMod/wad/Ahri.wad.client", "MyMod/README.txt"},
+			map[string]string{"MyMod/meta/info.json": "META/info.json", "MyMod/wad/Ahri.wad.client": "WAD/Ahri.wad.client"}, ""},
+		{"bare wad", []string{"Ahri.wad.client"}, map[string]string{"Ahri.wad.client": "WAD/Ahri.wad.client"}, ""},
+		{"loose wads in folders", []string{"a/Ahri.wad.client", "b/Jinx.WAD.CLIENT"},
+			map[string]string{"a/Ahri.wad.client": "WAD/Ahri.wad.client", "b/Jinx.WAD.CLIENT": "WAD/Jinx.WAD.CLIENT"}, ""},
+		{"traversal", []string{"WAD/Ahri.wad.client", "WAD/../../evil.dll"}, nil, "invalid path"},
+		{"dotdot", []string{"WAD/Ahri.wad.client", ".."}, nil, "invalid path"},
+		{"absolute", []string{"/WAD/Ahri.wad.client"}, nil, "invalid path"},
+		{"duplicate loose wads", []string{"a/Ahri.wad.client", "b/Ahri.wad.client"}, nil, "duplicate file"},
+		{"duplicate ignoring case", []string{"WAD/Ahri.wad.client", "ahri.WAD.client"}, nil, "duplicate file"},
+		{"only metadata", []string{"META/info.json", "README.txt"}, nil, "no WAD or RAW content"},
+	}
+	for _, tt := range tests {
+		got, err := normalizeModLayout(tt.files)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: err = %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultModName(t *testing.T) {
+	tests := map[string]string{
+		"C:/mods/Ahri.wad.client":   "Ahri",
+		"/mods/Star Guardian.ZIP":   "Star Guardian",
+		"mods/jinx.fantome":         "jinx",
+		"mods/My Mod":               "My Mod",
+		"mods/notes.wad":            "notes.wad",
+		"mods/arcane.fantome.zip":   "arcane.fantome",
+		"mods/Dynasty.Wad.Client":   "Dynasty",
+		"mods/pack.client.fantome/": "pack.client",
+	}
+	for srcPath, want := range tests {
+		if got := defaultModName(srcPath); got != want {
+			t.Errorf("defaultModName(%q) = %q, want %q", srcPath, got, want)
+		}
+	}
+}
+
+// modEntryNames lee cada entrada y devuelve destino -> contenido
+func modEntryNames(t *testing.T, entries []modArchiveEntry) map[string]string {
+	t.Helper()
+	got := make(map[string]string)
+	for _, e := range entries {
+		rc, err := e.open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[e.name] = string(data)
+	}
+	return got
+}
+
+func TestModEntriesFromZip(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+		err   bool
+	}{
+		{"fantome", []string{"META/info.json", "WAD/Ahri.wad.client"}, []string{"META/info.json", "WAD/Ahri.wad.client"}, false},
+		{"nested root", []string{"Ahri Mod/", "Ahri Mod/WAD/Ahri.wad.client", "Ahri Mod/preview.png"}, []string{"WAD/Ahri.wad.client"}, false},
+		{"loose wad", []string{"Ahri.wad.client"}, []string{"WAD/Ahri.wad.client"}, false},
+		{"traversal", []string{"WAD/Ahri.wad.client", "../evil.dll"}, nil, true},
+		{"duplicate wads", []string{"x/Ahri.wad.client", "y/Ahri.wad.client"}, nil, true},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for _, name := range tt.files {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(name, "/") {
+				w.Write([]byte(name))
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := modEntriesFromZip(zr)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %d entries", tt.name, len(entries))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		got := modEntryNames(t, entries)
+		var names []string
+		for name := range got {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s: entries = %v, want %v", tt.name, names, tt.want)
+		}
+	}
+}
+
+func TestModEntriesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Ahri Mod/META/info.json":        `{"Name": "Ahri"}`,
+		"Ahri Mod/WAD/Ahri.wad.client":   "wad",
+		"Ahri Mod/RAW/assets/ahri.bin":   "raw",
+		"Ahri Mod/notes/changelog.txt":   "ignored",
+		"Ahri Mod/WAD/.hidden/extra.txt": "extra",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := modEntriesFromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"META/info.json":        `{"Name": "Ahri"}`,
+		"WAD/Ahri.wad.client":   "wad",
+		"RAW/assets/ahri.bin":   "raw",
+		"WAD/.hidden/extra.txt": "extra",
+	}
+	if got := modEntryNames(t, entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+	if meta, err := readModInfo(entries); err != nil || meta.Name != "Ahri" {
+		t.Errorf("readModInfo = %+v, %v", meta, err)
+	}
+
+	// Dos .wad.client sueltos con el mismo nombre no se pueden importar
+	loose := t.TempDir()
+	for _, name := range []string{"a/Ahri.wad.client", "b/Ahri.wad.client"} {
+		p := filepath.Join(loose, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := modEntriesFromDir(loose); err == nil || !strings.Contains(err.Error(), "duplicate file") {
+		t.Errorf("duplicate loose wads: err = %v", err)
+	}
+	if _, err := modEntriesFromDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing folder: expected an error")
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RelativeLogsDir es la carpeta de los registros, dentro de la ruta base
+const RelativeLogsDir = "logs"
+
+const (
+	logFileName       = "skinhunter.log"
+	logFilePrefix     = "skinhunter-" // Archivos rotados: skinhunter-20060102-150405.log
+	logFileExtension  = ".log"
+	logRotationLayout = "20060102-150405"
+)
+
+// Subsistemas de los registros, cada uno con su nivel configurable
+const (
+	LogSubsystemApp      = "app"      // Arranque, rutas y lo que no encaja en otro
+	LogSubsystemAuth     = "auth"     // Sesión, login y cuenta
+	LogSubsystemSkins    = "skins"    // Descargas, instalación, fichas y ruleta
+	LogSubsystemOverlay  = "overlay"  // Ciclo de vida del overlay
+	LogSubsystemModTools = "modtools" // Comandos de mod-tools y su salida
+	LogSubsystemStorage  = "storage"  // Backups, loadouts, settings y reconciliación
+	LogSubsystemLCU      = "lcu"      // Cliente de League
+	LogSubsystemControl  = "control"  // API local de control
+)
+
+var logSubsystems = []string{
+	LogSubsystemApp, LogSubsystemAuth, LogSubsystemSkins, LogSubsystemOverlay,
+	LogSubsystemModTools, LogSubsystemStorage, LogSubsystemLCU, LogSubsystemControl,
+}
+
+// LogSettings configura el archivo de registro
+type LogSettings struct {
+	Level      string            `json:"level"`      // debug, info, warning o error
+	Subsystems map[string]string `json:"subsystems"` // Nivel por subsistema; los que faltan usan Level
+	MaxSizeMB  int               `json:"maxSizeMb"`  // Tamaño al que se rota el archivo actual
+	MaxAgeDays int               `json:"maxAgeDays"` // Antigüedad máxima de los archivos rotados
+	MaxFiles   int               `json:"maxFiles"`   // Archivos rotados a conservar
+}
+
+func defaultLogSettings() LogSettings {
+	return LogSettings{Level: "info", MaxSizeMB: 5, MaxAgeDays: 14, MaxFiles: 10}
+}
+
+// normalize corrige niveles desconocidos y límites fuera de rango
+func (s *LogSettings) normalize() {
+	defaults := defaultLogSettings()
+	if _, ok := parseLogLevel(s.Level); !ok {
+		s.Level = defaults.Level
+	}
+	for subsystem, level := range s.Subsystems {
+		if _, ok := parseLogLevel(level); !ok {
+			delete(s.Subsystems, subsystem)
+		}
+	}
+	if s.MaxSizeMB < 1 {
+		s.MaxSizeMB = defaults.MaxSizeMB
+	}
+	if s.MaxAgeDays < 1 {
+		s.MaxAgeDays = defaults.MaxAgeDays
+	}
+	if s.MaxFiles < 1 {
+		s.MaxFiles = defaults.MaxFiles
+	}
+}
+
+// levelFor devuelve el nivel mínimo que se registra para subsystem
+func (s LogSettings) levelFor(subsystem string) int {
+	if level, ok := parseLogLevel(s.Subsystems[subsystem]); ok {
+		return level
+	}
+	level, _ := parseLogLevel(s.Level)
+	return level
+}
+
+// parseLogLevel convierte "debug", "info", "warning" o "error" en un nivel
+func parseLogLevel(name string) (int, bool) {
+	for level, levelName := range logLevelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, true
+		}
+	}
+	return LogLevelInfo, false
+}
+
+// logEntry es una línea del archivo de registro
+type logEntry struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+	Message   string `json:"message"`
+}
+
+// RotatingLog escribe entradas JSON, una por línea, en dir/skinhunter.log. Al
+// superar el tamaño máximo el archivo se renombra con la fecha y se empieza otro;
+// los rotados se borran por antigüedad y cantidad.
+type RotatingLog struct {
+	mu       sync.Mutex
+	dir      string
+	file     *os.File
+	size     int64
+	maxSize  int64
+	maxAge   time.Duration
+	maxFiles int
+}
+
+// OpenRotatingLog abre (o crea) el registro actual en dir
+func OpenRotatingLog(dir string, settings LogSettings) (*RotatingLog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	l := &RotatingLog{dir: dir}
+	l.Configure(settings)
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	l.prune()
+	return l, nil
+}
+
+// Configure aplica los límites de settings a las próximas escrituras
+func (l *RotatingLog) Configure(settings LogSettings) {
+	settings.normalize()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxSize = int64(settings.MaxSizeMB) * 1024 * 1024
+	l.maxAge = time.Duration(settings.MaxAgeDays) * 24 * time.Hour
+	l.maxFiles = settings.MaxFiles
+}
+
+func (l *RotatingLog) open() error {
+	f, err := os.OpenFile(filepath.Join(l.dir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// Write agrega una entrada; los errores de escritura se ignoran para no romper a quien registra
+func (l *RotatingLog) Write(entry logEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		l.rotate()
+	}
+	if l.file == nil {
+		return
+	}
+	n, _ := l.file.Write(line)
+	l.size += int64(n)
+}
+
+// rotate renombra el archivo actual y abre uno nuevo. Se llama con l.mu tomado.
+func (l *RotatingLog) rotate() {
+	l.file.Close()
+	l.file = nil
+	current := filepath.Join(l.dir, logFileName)
+	rotated := filepath.Join(l.dir, logFilePrefix+time.Now().Format(logRotationLayout)+logFileExtension)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(rotated); os.IsNotExist(err) {
+			break
+		}
+		rotated = filepath.Join(l.dir, fmt.Sprintf("%s%s-%d%s", logFilePrefix, time.Now().Format(logRotationLayout), i, logFileExtension))
+	}
+	os.Rename(current, rotated)
+	if err := l.open(); err != nil {
+		fmt.Fprintf(os.Stderr, "could not reopen log file: %v\n", err)
+	}
+	go l.prune()
+}
+
+// prune borra los archivos rotados más viejos que maxAge o que excedan maxFiles
+func (l *RotatingLog) prune() {
+	l.mu.Lock()
+	maxAge, maxFiles := l.maxAge, l.maxFiles
+	l.mu.Unlock()
+
+	rotated := l.rotatedFiles()
+	for i, name := range rotated {
+		p := filepath.Join(l.dir, name)
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		if i >= maxFiles || time.Since(info.ModTime()) > maxAge {
+			os.Remove(p)
+		}
+	}
+}
+
+// rotatedFiles devuelve los archivos rotados, del más nuevo al más viejo
+func (l *RotatingLog) rotatedFiles() []string {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && strings.HasPrefix(name, logFilePrefix) && strings.HasSuffix(name, logFileExtension) {
+			names = append(names, name)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names
+}
+
+// Files devuelve las rutas del registro actual y los rotados, del más nuevo al más viejo
+func (l *RotatingLog) Files() []string {
+	files := []string{filepath.Join(l.dir, logFileName)}
+	for _, name := range l.rotatedFiles() {
+		files = append(files, filepath.Join(l.dir, name))
+	}
+	return files
+}
+
+// Close cierra el archivo actual
+func (l *RotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// openLogFile abre el registro en la carpeta de logs. Sin él la app sigue
+// registrando en Wails o en la consola.
+func (a *App) openLogFile() {
+	logFile, err := OpenRotatingLog(filepath.Join(absBasePath, RelativeLogsDir), a.currentSettings().Logging)
+	if err != nil {
+		a.logger(LogSubsystemApp).Warningf("Could not open log file: %v", err)
+		return
+	}
+	a.logMu.Lock()
+	a.logFile = logFile
+	a.logMu.Unlock()
+}
+
+// applyLogSettings aplica los límites de rotación tras cargar o cambiar la configuración
+func (a *App) applyLogSettings() {
+	if logFile := a.currentLogFile(); logFile != nil {
+		logFile.Configure(a.currentSettings().Logging)
+	}
+}
+
+func (a *App) currentLogFile() *RotatingLog {
+	a.logMu.Lock()
+	defer a.logMu.Unlock()
+	return a.logFile
+}
+
+// lineLogWriter registra cada línea escrita por un proceso externo (p. ej. la
+// salida de mod-tools) como una entrada del subsistema indicado
+type lineLogWriter struct {
+	app       *App
+	subsystem string
+	level     int
+	prefix    string
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+func (w *lineLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		if line := strings.TrimRight(string(w.pending[:i]), "\r"); line != "" {
+			w.app.logAt(w.subsystem, w.level, w.prefix+line)
+		}
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readLogEntries decodifica las entradas de un archivo de registro
+func readLogEntries(t *testing.T, path string) []logEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []logEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRotatingLogRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	l, err := OpenRotatingLog(dir, defaultLogSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.mu.Lock()
+	l.maxSize = 150 // Un par de entradas por archivo
+	l.mu.Unlock()
+
+	for i := 0; i < 3; i++ {
+		l.Write(logEntry{Time: "t", Level: "info", Subsystem: LogSubsystemApp, Message: strings.Repeat("x", 50)})
+	}
+	files := l.Files()
+	if len(files) < 2 || filepath.Base(files[0]) != logFileName {
+		t.Fatalf("files = %v, want the current log and at least one rotated", files)
+	}
+	for _, p := range files[1:] {
+		if name := filepath.Base(p); !strings.HasPrefix(name, logFilePrefix) || !strings.HasSuffix(name, logFileExtension) {
+			t.Errorf("rotated file %q", name)
+		}
+	}
+	total := 0
+	for _, p := range files {
+		if info, err := os.Stat(p); err != nil || info.Size() > 150 {
+			t.Errorf("%s exceeds the max size: %v", p, info)
+		}
+		total += len(readLogEntries(t, p))
+	}
+	if total != 3 {
+		t.Errorf("entries across files = %d, want 3", total)
+	}
+}
+
+func TestRotatingLogPrune(t *testing.T) {
+	dir := t.TempDir()
+	settings := defaultLogSettings()
+	settings.MaxFiles = 2
+	settings.MaxAgeDays = 1
+	l, err := OpenRotatingLog(dir, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	old := time.Now().Add(-48 * time.Hour)
+	rotated := map[string]time.Time{
+		"skinhunter-20240105-100000.log": time.Now(),
+		"skinhunter-20240104-100000.log": time.Now(),
+		"skinhunter-20240103-100000.log": time.Now(), // Excede MaxFiles
+		"skinhunter-20240102-100000.log": old,
+	}
+	for name, modTime := range rotated {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Otros archivos de la carpeta no se tocan
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l.prune()
+	got := l.rotatedFiles()
+	want := []string{"skinhunter-20240105-100000.log", "skinhunter-20240104-100000.log"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("rotated files after prune = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("notes.txt was removed: %v", err)
+	}
+
+	// Un archivo nuevo pero vencido también se borra
+	recent := filepath.Join(dir, want[0])
+	if err := os.Chtimes(recent, old, old); err != nil {
+		t.Fatal(err)
+	}
+	l.prune()
+	if got := l.rotatedFiles(); len(got) != 1 || got[0] != want[1] {
+		t.Errorf("rotated files after age prune = %v", got)
+	}
+}
+
+// Cada entrada lleva el subsistema del logger y se filtra con su nivel
+func TestAppLoggerSubsystems(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	settings := a.currentSettings()
+	settings.Logging.Subsystems = map[string]string{LogSubsystemModTools: "error"}
+	a.setSettings(settings)
+	a.openLogFile()
+	defer a.currentLogFile().Close()
+
+	a.logger(LogSubsystemAuth).Infof("Login: %s", "ahri")
+	a.logger(LogSubsystemModTools).Warning("filtered")
+	a.logger(LogSubsystemModTools).Error("kept")
+	a.logger(LogSubsystemApp).Debugf("below the default level")
+
+	entries := readLogEntries(t, filepath.Join(absBasePath, RelativeLogsDir, logFileName))
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if e := entries[0]; e.Subsystem != LogSubsystemAuth || e.Level != "info" || e.Message != "Login: ahri" {
+		t.Errorf("first entry = %+v", e)
+	}
+	if e := entries[1]; e.Subsystem != LogSubsystemModTools || e.Level != "error" || e.Message != "kept" {
+		t.Errorf("second entry = %+v", e)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Niveles de registro
+const (
+	LogLevelDebug = iota
+	LogLevelInfo
+	LogLevelWarning
+	LogLevelError
+)
+
+var logLevelNames = map[int]string{
+	LogLevelDebug:   "debug",
+	LogLevelInfo:    "info",
+	LogLevelWarning: "warning",
+	LogLevelError:   "error",
+}
+
+var logLevelPrefixes = map[int]string{
+	LogLevelDebug:   "DEB | ",
+	LogLevelInfo:    "INF | ",
+	LogLevelWarning: "WAR | ",
+	LogLevelError:   "ERR | ",
+}
+
+// logAt escribe en el archivo de registro, en el logger de Wails o, en modo consola,
+// en stderr a partir de a.cliLogLevel. El runtime de Wails termina el proceso si se
+// usa sin su contexto, por eso todo el registro de la app pasa por aquí. Las líneas
+// también llegan al feed de la API de control. Los niveles por subsistema de
+// settings.Logging solo filtran el archivo.
+func (a *App) logAt(subsystem string, level int, message string) {
+	if a.headless && level >= a.cliLogLevel {
+		fmt.Fprintln(os.Stderr, logLevelPrefixes[level]+message)
+	}
+	if logFile := a.currentLogFile(); logFile != nil && level >= a.currentSettings().Logging.levelFor(subsystem) {
+		logFile.Write(logEntry{
+			Time:      time.Now().Format(time.RFC3339Nano),
+			Level:     logLevelNames[level],
+			Subsystem: subsystem,
+			Message:   message,
+		})
+	}
+	if control := a.controlServer(); control != nil && level >= LogLevelInfo {
+		control.Broadcast("log", map[string]interface{}{"level": logLevelNames[level], "subsystem": subsystem, "message": message})
+	}
+	if a.headless {
+		return
+	}
+	switch level {
+	case LogLevelDebug:
+		runtime.LogDebug(a.ctx, message)
+	case LogLevelInfo:
+		runtime.LogInfo(a.ctx, message)
+	case LogLevelWarning:
+		runtime.LogWarning(a.ctx, message)
+	default:
+		runtime.LogError(a.ctx, message)
+	}
+}
+
+// appLogger registra con un subsistema fijo; se obtiene con App.logger
+type appLogger struct {
+	app       *App
+	subsystem string
+}
+
+// logger devuelve el registro de subsystem: a.logger(LogSubsystemAuth).Infof(...)
+func (a *App) logger(subsystem string) appLogger {
+	return appLogger{app: a, subsystem: subsystem}
+}
+
+func (l appLogger) Debugf(format string, args ...interface{}) {
+	l.app.logAt(l.subsystem, LogLevelDebug, fmt.Sprintf(format, args...))
+}
+
+func (l appLogger) Info(message string) { l.app.logAt(l.subsystem, LogLevelInfo, message) }
+
+func (l appLogger) Infof(format string, args ...interface{}) {
+	l.app.logAt(l.subsystem, LogLevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (l appLogger) Warning(message string) { l.app.logAt(l.subsystem, LogLevelWarning, message) }
+
+func (l appLogger) Warningf(format string, args ...interface{}) {
+	l.app.logAt(l.subsystem, LogLevelWarning, fmt.Sprintf(format, args...))
+}
+
+func (l appLogger) Error(message string) { l.app.logAt(l.subsystem, LogLevelError, message) }
+
+func (l appLogger) Errorf(format string, args ...interface{}) {
+	l.app.logAt(l.subsystem, LogLevelError, fmt.Sprintf(format, args...))
+}
+
+// emit envía un evento al frontend y a los clientes de la API de control.
+// En modo consola no hay frontend.
+func (a *App) emit(event string, data ...interface{}) {
+	if control := a.controlServer(); control != nil {
+		control.Broadcast(event, data...)
+	}
+	if !a.headless {
+		runtime.EventsEmit(a.ctx, event, data...)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -12,6 +13,12 @@ import (
 var assets embed.FS
 
 func main() {
+	// Con un comando (p. ej. "skinhunter list --json" o "skinhunter --json list")
+	// se ejecuta la CLI sin webview
+	if isCLIInvocation(os.Args[1:]) {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
 	// Crear una instancia de la estructura App
 	app := NewApp()
 
@@ -28,6 +35,9 @@ func main() {
 		Bind: []interface{}{
 			app,
 		},
+		EnumBind: []interface{}{
+			AllErrorCodes,
+		},
 	})
 
 	if err != nil {
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Endpoints del LCU con el inventario del jugador
+const (
+	inventorySkinsPath  = "/lol-inventory/v2/inventory/CHAMPION_SKIN"
+	currentSummonerPath = "/lol-summoner/v1/current-summoner"
+	skinsMinimalPath    = "/lol-champions/v1/inventories/%d/skins-minimal"
+)
+
+// OwnedSkins son los IDs de skins y chromas (campeón*1000 + número) que el jugador
+// tiene en su cuenta, según el último inventario leído del cliente.
+type OwnedSkins struct {
+	mu        sync.Mutex
+	ids       map[int]bool
+	fetchedAt time.Time
+}
+
+// Set reemplaza el inventario
+func (o *OwnedSkins) Set(ids map[int]bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ids = ids
+	o.fetchedAt = time.Now()
+}
+
+// Snapshot devuelve una copia del inventario, o nil si nunca se leyó
+func (o *OwnedSkins) Snapshot() map[int]bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.ids == nil {
+		return nil
+	}
+	ids := make(map[int]bool, len(o.ids))
+	for id := range o.ids {
+		ids[id] = true
+	}
+	return ids
+}
+
+// inventoryItem es un elemento de /lol-inventory/v2/inventory/CHAMPION_SKIN
+type inventoryItem struct {
+	ItemID        int    `json:"itemId"`
+	OwnershipType string `json:"ownershipType"`
+}
+
+// parseInventorySkins lee la respuesta del inventario. Las skins alquiladas o
+// gratis por rotación no cuentan como propias.
+func parseInventorySkins(data []byte) (map[int]bool, error) {
+	var items []inventoryItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid inventory response: %v", err)
+	}
+	owned := map[int]bool{}
+	for _, item := range items {
+		if item.OwnershipType == "OWNED" || item.OwnershipType == "LOYALTY" {
+			owned[item.ItemID] = true
+		}
+	}
+	return owned, nil
+}
+
+// skinMinimal es un elemento de skins-minimal, que también trae los chromas
+type skinMinimal struct {
+	ID        int `json:"id"`
+	Ownership struct {
+		Owned bool `json:"owned"`
+	} `json:"ownership"`
+	Chromas []struct {
+		ID        int `json:"id"`
+		Ownership struct {
+			Owned bool `json:"owned"`
+		} `json:"ownership"`
+	} `json:"chromas"`
+}
+
+// parseSkinsMinimal lee la respuesta de /lol-champions/v1/inventories/<id>/skins-minimal
+func parseSkinsMinimal(data []byte) (map[int]bool, error) {
+	var skins []skinMinimal
+	if err := json.Unmarshal(data, &skins); err != nil {
+		return nil, fmt.Errorf("invalid skins response: %v", err)
+	}
+	owned := map[int]bool{}
+	for _, skin := range skins {
+		if skin.Ownership.Owned {
+			owned[skin.ID] = true
+		}
+		for _, chroma := range skin.Chromas {
+			if chroma.Ownership.Owned {
+				owned[chroma.ID] = true
+			}
+		}
+	}
+	return owned, nil
+}
+
+// fetchOwnedSkins lee el inventario del jugador conectado. Usa skins-minimal, que
+// incluye chromas, y el inventario genérico si ese endpoint no responde.
+func fetchOwnedSkins(client *LCUClient) (map[int]bool, error) {
+	var summoner struct {
+		SummonerID int64 `json:"summonerId"`
+	}
+	if err := client.Get(currentSummonerPath, &summoner); err == nil && summoner.SummonerID != 0 {
+		var raw json.RawMessage
+		if err := client.Get(fmt.Sprintf(skinsMinimalPath, summoner.SummonerID), &raw); err == nil {
+			return parseSkinsMinimal(raw)
+		}
+	}
+	var raw json.RawMessage
+	if err := client.Get(inventorySkinsPath, &raw); err != nil {
+		return nil, err
+	}
+	return parseInventorySkins(raw)
+}
+
+// registerOwnedSkinsHandlers lee el inventario al conectar y al volver a la sala
+// (al conectar, el jugador puede no haber iniciado sesión todavía)
+func (a *App) registerOwnedSkinsHandlers() {
+	a.lcu.OnConnect(func(client *LCUClient) {
+		a.refreshOwnedSkins(client)
+	})
+	a.lcu.Handle(gameflowEvent, gameflowURI, func(event LCUEvent) {
+		var phase string
+		if json.Unmarshal(event.Data, &phase) == nil && phase == PhaseLobby {
+			if client := a.lcu.Client(); client != nil {
+				a.refreshOwnedSkins(client)
+			}
+		}
+	})
+}
+
+func (a *App) refreshOwnedSkins(client *LCUClient) error {
+	owned, err := fetchOwnedSkins(client)
+	if err != nil {
+		a.logger(LogSubsystemLCU).Warningf("Could not read owned skins: %v", err)
+		return err
+	}
+	a.ownedSkins.Set(owned)
+	a.emit("owned-skins-updated", map[string]interface{}{"count": len(owned)})
+	return nil
+}
+
+// RefreshOwnedSkins vuelve a leer el inventario del cliente de League
+func (a *App) RefreshOwnedSkins() Result {
+	var client *LCUClient
+	if a.lcu != nil {
+		client = a.lcu.Client()
+	}
+	if client == nil {
+		return failResult(ErrLCUNotRunning, "League client is not running", nil)
+	}
+	if err := a.refreshOwnedSkins(client); err != nil {
+		return failResult(ErrLCUNotRunning, "Could not read owned skins", err)
+	}
+	return okResult("")
+}
+
+// OwnedSkinsStatus es la respuesta de GetOwnedSkins
+type OwnedSkinsStatus struct {
+	Available bool  `json:"available"` // false si todavía no se pudo leer el inventario
+	SkinIds   []int `json:"skinIds"`
+	HideOwned bool  `json:"hideOwned"`
+}
+
+// GetOwnedSkins devuelve los IDs de skins y chromas propios, para marcar resultados de búsqueda
+func (a *App) GetOwnedSkins() OwnedSkinsStatus {
+	owned := a.ownedSkins.Snapshot()
+	ids := make([]int, 0, len(owned))
+	for id := range owned {
+		ids = append(ids, id)
+	}
+	return OwnedSkinsStatus{
+		Available: owned != nil,
+		SkinIds:   ids,
+		HideOwned: a.currentSettings().HideOwnedSkins,
+	}
+}
+
+// annotateOwnedSkins marca con "owned" cada skin y chroma del JSON de un campeón
+// y, si hide es true, quita las que el jugador ya tiene (nunca la skin base).
+// Los elementos que no son objetos se dejan tal cual, sean skins o chromas.
+func annotateOwnedSkins(champion map[string]interface{}, owned map[int]bool, hide bool) {
+	skins, ok := champion["skins"].([]interface{})
+	if !ok || owned == nil {
+		return
+	}
+	kept := make([]interface{}, 0, len(skins))
+	for _, item := range skins {
+		skin, ok := item.(map[string]interface{})
+		if !ok {
+			kept = append(kept, item)
+			continue
+		}
+		isOwned := owned[jsonInt(skin["id"])]
+		skin["owned"] = isOwned
+		if chromas, ok := skin["chromas"].([]interface{}); ok {
+			keptChromas := make([]interface{}, 0, len(chromas))
+			for _, c := range chromas {
+				chroma, ok := c.(map[string]interface{})
+				if !ok {
+					keptChromas = append(keptChromas, c)
+					continue
+				}
+				chromaOwned := owned[jsonInt(chroma["id"])]
+				chroma["owned"] = chromaOwned
+				if !(hide && chromaOwned) {
+					keptChromas = append(keptChromas, chroma)
+				}
+			}
+			skin["chromas"] = keptChromas
+		}
+		isBase, _ := skin["isBase"].(bool)
+		if hide && isOwned && !isBase {
+			continue
+		}
+		kept = append(kept, skin)
+	}
+	champion["skins"] = kept
+}
+
+// jsonInt convierte un número decodificado de JSON a int
+func jsonInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case int:
+		return n
+	}
+	return 0
+}
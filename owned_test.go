@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// readFixture lee testdata/name
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// ownedIds devuelve los IDs de owned ordenados
+func ownedIds(owned map[int]bool) []int {
+	ids := make([]int, 0, len(owned))
+	for id := range owned {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func TestParseInventorySkins(t *testing.T) {
+	owned, err := parseInventorySkins(readFixture(t, "inventory_champion_skin.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Alquiladas (RENTED) y gratis por rotación (F2P) no cuentan
+	if got, want := ownedIds(owned), []int{103000, 103001, 157002}; !reflect.DeepEqual(got, want) {
+		t.Errorf("owned = %v, want %v", got, want)
+	}
+
+	if owned, err := parseInventorySkins([]byte(`[]`)); err != nil || len(owned) != 0 {
+		t.Errorf("empty inventory = %v, %v", owned, err)
+	}
+	for _, bad := range []string{`{"errorCode":"RPC_ERROR"}`, `not json`, ``} {
+		if _, err := parseInventorySkins([]byte(bad)); err == nil {
+			t.Errorf("parseInventorySkins(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestParseSkinsMinimal(t *testing.T) {
+	owned, err := parseSkinsMinimal(readFixture(t, "skins_minimal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Un chroma propio cuenta aunque la skin sea alquilada (103016 de 103015)
+	if got, want := ownedIds(owned), []int{103000, 103001, 103002, 103016}; !reflect.DeepEqual(got, want) {
+		t.Errorf("owned = %v, want %v", got, want)
+	}
+
+	if owned, err := parseSkinsMinimal([]byte(`[{"id": 103000, "chromas": null}]`)); err != nil || len(owned) != 0 {
+		t.Errorf("skin without ownership = %v, %v", owned, err)
+	}
+	for _, bad := range []string{`{"httpStatus":404}`, `[{"id": "103000"}]`} {
+		if _, err := parseSkinsMinimal([]byte(bad)); err == nil {
+			t.Errorf("parseSkinsMinimal(%q): expected an error", bad)
+		}
+	}
+}
+
+// loadChampionFixture decodifica testdata/champion_103.json como lo hace FetchChampionJson
+func loadChampionFixture(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var champion map[string]interface{}
+	if err := json.Unmarshal(readFixture(t, "champion_103.json"), &champion); err != nil {
+		t.Fatal(err)
+	}
+	return champion
+}
+
+// skinSummary devuelve el ID de cada skin que queda con los IDs de sus chromas
+func skinSummary(champion map[string]interface{}) map[int][]int {
+	summary := map[int][]int{}
+	for _, item := range champion["skins"].([]interface{}) {
+		skin := item.(map[string]interface{})
+		chromas := []int{}
+		if list, ok := skin["chromas"].([]interface{}); ok {
+			for _, c := range list {
+				chromas = append(chromas, jsonInt(c.(map[string]interface{})["id"]))
+			}
+		}
+		summary[jsonInt(skin["id"])] = chromas
+	}
+	return summary
+}
+
+func TestAnnotateOwnedSkins(t *testing.T) {
+	owned, err := parseSkinsMinimal(readFixture(t, "skins_minimal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	champion := loadChampionFixture(t)
+	annotateOwnedSkins(champion, owned, false)
+	wantOwned := map[int]bool{103000: true, 103001: true, 103002: true, 103003: false, 103015: false, 103016: true}
+	for _, item := range champion["skins"].([]interface{}) {
+		skin := item.(map[string]interface{})
+		if got := skin["owned"]; got != wantOwned[jsonInt(skin["id"])] {
+			t.Errorf("skin %v owned = %v", skin["id"], got)
+		}
+		chromas, _ := skin["chromas"].([]interface{})
+		for _, c := range chromas {
+			chroma := c.(map[string]interface{})
+			if got := chroma["owned"]; got != wantOwned[jsonInt(chroma["id"])] {
+				t.Errorf("chroma %v owned = %v", chroma["id"], got)
+			}
+		}
+	}
+	if got := len(skinSummary(champion)); got != 3 {
+		t.Errorf("without hide %d skins remain, want 3", got)
+	}
+
+	// Con hide se quitan las propias salvo la base; los chromas propios también
+	champion = loadChampionFixture(t)
+	annotateOwnedSkins(champion, owned, true)
+	want := map[int][]int{103000: {}, 103015: {}}
+	if got := skinSummary(champion); !reflect.DeepEqual(got, want) {
+		t.Errorf("with hide = %v, want %v", got, want)
+	}
+}
+
+// Sin inventario leído no se marca nada
+func TestAnnotateOwnedSkinsWithoutInventory(t *testing.T) {
+	champion := loadChampionFixture(t)
+	annotateOwnedSkins(champion, nil, true)
+	if !reflect.DeepEqual(champion, loadChampionFixture(t)) {
+		t.Errorf("champion changed without an inventory: %v", champion)
+	}
+}
+
+// Skins y chromas que no son objetos se conservan igual, sin anotar
+func TestAnnotateOwnedSkinsKeepsNonObjectEntries(t *testing.T) {
+	var champion map[string]interface{}
+	json.Unmarshal([]byte(`{"skins": [
+		null,
+		"103999",
+		{"id": 103001, "chromas": [null, 103002, {"id": 103003}]}
+	]}`), &champion)
+
+	for _, hide := range []bool{false, true} {
+		annotateOwnedSkins(champion, map[int]bool{103002: true}, hide)
+		skins := champion["skins"].([]interface{})
+		if len(skins) != 3 || skins[0] != nil || skins[1] != "103999" {
+			t.Fatalf("hide=%v: skins = %v", hide, skins)
+		}
+		chromas := skins[2].(map[string]interface{})["chromas"].([]interface{})
+		if len(chromas) != 3 || chromas[0] != nil || chromas[1] != float64(103002) {
+			t.Errorf("hide=%v: chromas = %v", hide, chromas)
+		}
+		if owned := chromas[2].(map[string]interface{})["owned"]; owned != false {
+			t.Errorf("hide=%v: chroma 103003 owned = %v", hide, owned)
+		}
+	}
+}
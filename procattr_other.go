@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// newConsoleProcAttr no cambia nada fuera de Windows, donde no hay consolas nuevas
+func newConsoleProcAttr() *syscall.SysProcAttr { return &syscall.SysProcAttr{} }
+
+// hiddenProcAttr no cambia nada fuera de Windows: los procesos no abren ventana
+func hiddenProcAttr() *syscall.SysProcAttr { return &syscall.SysProcAttr{} }
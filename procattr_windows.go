@@ -0,0 +1,18 @@
+//go:build windows
+
+package main
+
+import "syscall"
+
+// newConsoleProcAttr abre el proceso en una consola nueva (CREATE_NEW_CONSOLE)
+func newConsoleProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: 0x00000010}
+}
+
+// hiddenProcAttr ejecuta el proceso en segundo plano, en su propio grupo y sin ventana
+func hiddenProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | 0x08000000, // CREATE_NO_WINDOW
+		HideWindow:    true,
+	}
+}
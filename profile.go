@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/supabase-community/gotrue-go/types"
+)
+
+// ProfileUpdate son los únicos cambios de perfil que acepta la app. Los campos
+// vacíos no se tocan; fichas y compras solo las cambia el servidor.
+type ProfileUpdate struct {
+	DisplayName     string `json:"displayName"`     // Nombre visible (columna login de public.users)
+	Email           string `json:"email"`           // Email nuevo; se aplica cuando el usuario lo confirma
+	CurrentPassword string `json:"currentPassword"` // Obligatoria para cambiar la contraseña
+	NewPassword     string `json:"newPassword"`
+}
+
+// UpdateProfile actualiza el perfil del usuario dueño del token (o de la sesión
+// guardada). Si algún campo no es válido no se cambia nada y se devuelve
+// "fieldErrors". Un email nuevo queda pendiente hasta que se confirma desde el correo.
+func (a *App) UpdateProfile(token string, update ProfileUpdate) AuthResult {
+	token, err := a.accessToken(token)
+	if err != nil {
+		return AuthResult{Result: failResult(ErrAuthInvalidToken, "Invalid token", nil)}
+	}
+	claims, err := a.backend.Auth().VerifyAccessToken(token)
+	if err != nil {
+		return AuthResult{Result: failResult(ErrAuthInvalidToken, "Invalid token", nil)}
+	}
+
+	update.DisplayName = strings.TrimSpace(update.DisplayName)
+	update.Email = strings.TrimSpace(update.Email)
+	if strings.EqualFold(update.Email, claims.Email) {
+		update.Email = ""
+	}
+	if update.DisplayName == "" && update.Email == "" && update.NewPassword == "" {
+		return AuthResult{Result: failResult(ErrInvalidArgument, "Nothing to update", nil)}
+	}
+
+	fieldErrors := validateProfileUpdate(update, claims.Email)
+	if update.DisplayName != "" && len(fieldErrors) == 0 {
+		// El nombre también sirve para iniciar sesión, así que no puede repetirse
+		taken, err := a.checkLoginAvailable("displayName", update.DisplayName, token, claims.UserID)
+		if err != nil {
+			return AuthResult{Result: failResult(ErrAuthUnavailable, "Could not check display name", err)}
+		}
+		if taken != nil {
+			fieldErrors = append(fieldErrors, *taken)
+		}
+	}
+	if update.NewPassword != "" && len(fieldErrors) == 0 {
+		// Confirmar la contraseña actual con el servidor antes de cambiarla. Cuenta
+		// como un intento de login, así este formulario no sirve para probar contraseñas.
+		if err := a.loginThrottle.Check(claims.Email); err != nil {
+			return a.loginThrottledResult(claims.Email, err)
+		}
+		probe, err := a.backend.Auth().SignIn(claims.Email, update.CurrentPassword)
+		if err != nil {
+			var authErr *AuthError
+			if errors.As(err, &authErr) && authErr.Status == 429 {
+				return AuthResult{Result: failResult(ErrAuthRateLimited, "Too many password checks", err)}
+			}
+			if !errors.As(err, &authErr) || !authErr.InvalidCredentials() {
+				return AuthResult{Result: failResult(ErrAuthUnavailable, "Could not check current password", err)}
+			}
+			a.loginThrottle.RecordFailure(claims.Email)
+			fieldErrors = append(fieldErrors, FieldError{Field: "currentPassword", Code: ValidationWrongPassword, Message: "Current password is incorrect"})
+		} else {
+			a.loginThrottle.RecordSuccess(claims.Email)
+			// La sesión de la prueba no se usa: se revoca solo esa, no la del usuario
+			if err := a.backend.Auth().RevokeSession(probe.AccessToken); err != nil {
+				a.logger(LogSubsystemAuth).Warningf("UpdateProfile: could not revoke password check session: %v", err)
+			}
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrorsResult(fieldErrors)
+	}
+
+	result := AuthResult{Result: okResult(MsgProfileUpdated)}
+	if update.Email != "" || update.NewPassword != "" {
+		req := types.UpdateUserRequest{Email: update.Email}
+		if update.NewPassword != "" {
+			req.Password = &update.NewPassword
+		}
+		user, err := a.backend.Auth().UpdateUser(token, req)
+		if err != nil {
+			if fieldErr := fieldErrorFromAuth(err, "email", "newPassword"); fieldErr != nil {
+				return fieldErrorsResult([]FieldError{*fieldErr})
+			}
+			return AuthResult{Result: failResult(ErrAuthUnavailable, "Could not update account", err)}
+		}
+		if update.Email != "" {
+			result.PendingEmail = user.EmailChange
+			result.Message = tr(MsgProfileEmailPending)
+		}
+	}
+
+	if update.DisplayName != "" {
+		if err := a.backend.Users().UpdateLogin(token, claims.UserID, update.DisplayName); err != nil {
+			return AuthResult{Result: failResult(ErrAuthUnavailable, "Error updating profile", err)}
+		}
+	}
+
+	user, err := a.backend.Users().FindByID(token, claims.UserID)
+	if err == nil {
+		user.Email = claims.Email
+		result.User = user
+	}
+	return result
+}
+
+// validateProfileUpdate revisa el formato de cada campo presente en update con
+// las mismas reglas que el registro
+func validateProfileUpdate(update ProfileUpdate, currentEmail string) []FieldError {
+	var displayName, email, currentPassword, newPassword *FieldError
+	if update.DisplayName != "" {
+		displayName = validateLogin("displayName", update.DisplayName)
+	}
+	if update.Email != "" {
+		email = validateEmail("email", update.Email)
+	}
+	if update.NewPassword != "" {
+		if update.CurrentPassword == "" {
+			currentPassword = &FieldError{Field: "currentPassword", Code: ValidationRequired, Message: "Current password is required"}
+		}
+		if update.NewPassword == update.CurrentPassword {
+			newPassword = &FieldError{Field: "newPassword", Code: ValidationSamePassword, Message: "New password must be different from the current one"}
+		} else {
+			newPassword = validatePassword("newPassword", update.NewPassword, currentEmail, update.DisplayName)
+		}
+	}
+	return collectFieldErrors(displayName, email, currentPassword, newPassword)
+}
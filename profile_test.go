@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testUserID = "6f1c7d1e-0000-4000-8000-000000000001"
+
+// stubGoTrue es un servidor de Supabase Auth falso con un único usuario
+type stubGoTrue struct {
+	*httptest.Server
+	password string
+
+	mu             sync.Mutex
+	passwordGrants int
+	revoked        []string // "scope token" de cada logout
+}
+
+func newStubGoTrue(t *testing.T, password string) *stubGoTrue {
+	t.Helper()
+	s := &stubGoTrue{password: password}
+	user := map[string]string{"id": testUserID, "email": "ahri@example.com"}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case r.URL.Path == "/auth/v1/user":
+			json.NewEncoder(w).Encode(user)
+		case r.URL.Path == "/auth/v1/token" && r.URL.Query().Get("grant_type") == "password":
+			s.passwordGrants++
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["password"] != s.password {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error_code":"invalid_credentials","msg":"Invalid login credentials"}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "probe", "refresh_token": "rt", "expires_in": 3600, "user": user})
+		case r.URL.Path == "/auth/v1/logout":
+			s.revoked = append(s.revoked, r.URL.Query().Get("scope")+" "+r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newProfileTestApp crea una App con auth falso, backend en memoria y su token de usuario
+func newProfileTestApp(t *testing.T, password string) (*App, *stubGoTrue, string) {
+	t.Helper()
+	a := newTestApp(t)
+	gotrue := newStubGoTrue(t, password)
+	backend := NewMemoryBackend().WithAuth(NewAuthService(gotrue.URL, "anon", gotrue.Client()))
+	backend.AddUser(UserProfile{ID: testUserID, Login: "ahri"}, "ahri@example.com", password)
+	a.backend = backend
+	a.loginThrottle, _ = NewLoginThrottle(filepath.Join(t.TempDir(), "throttle.json"))
+	// Token HS256: se verifica con GET /user, como en un proyecto sin claves asimétricas
+	token := signTestToken(t, jwt.SigningMethodHS256, []byte("secret"), jwt.MapClaims{
+		"sub": testUserID, "email": "ahri@example.com", "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	return a, gotrue, token
+}
+
+func TestUpdateProfilePasswordCheckIsThrottled(t *testing.T) {
+	a, gotrue, token := newProfileTestApp(t, "correct horse battery")
+	update := ProfileUpdate{CurrentPassword: "wrong", NewPassword: "Tr0ub4dor&3-new"}
+
+	for i := 0; i < loginFreeAttempts; i++ {
+		result := a.UpdateProfile(token, update)
+		if len(result.FieldErrors) != 1 || result.FieldErrors[0].Code != ValidationWrongPassword {
+			t.Fatalf("attempt %d: %+v", i, result)
+		}
+	}
+	result := a.UpdateProfile(token, update)
+	if result.Code != ErrAuthRateLimited || result.RetryAfter == 0 {
+		t.Errorf("after %d failures: code %q, retryAfter %d", loginFreeAttempts, result.Code, result.RetryAfter)
+	}
+	if gotrue.passwordGrants != loginFreeAttempts {
+		t.Errorf("server checked the password %d times, want %d", gotrue.passwordGrants, loginFreeAttempts)
+	}
+}
+
+func TestUpdateProfileRevokesPasswordCheckSession(t *testing.T) {
+	a, gotrue, token := newProfileTestApp(t, "correct horse battery")
+
+	result := a.UpdateProfile(token, ProfileUpdate{CurrentPassword: "correct horse battery", NewPassword: "Tr0ub4dor&3-new"})
+	if len(result.FieldErrors) > 0 {
+		t.Fatalf("unexpected field errors: %+v", result.FieldErrors)
+	}
+	if len(gotrue.revoked) != 1 || gotrue.revoked[0] != "local Bearer probe" {
+		t.Errorf("revoked sessions = %q, want only the probe session with scope=local", gotrue.revoked)
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Acciones de reparación que ofrece ReconcileInstalled
+const (
+	RepairRedownload = "redownload" // Volver a descargar el archivo de una entrada cuyo archivo falta
+	RepairAdopt      = "adopt"      // Registrar un archivo no rastreado como skin de un campeón
+	RepairDrop       = "drop"       // Quitar de installed.json una entrada cuyo archivo falta
+	RepairDelete     = "delete"     // Borrar un archivo o directorio sobrante de installed/
+)
+
+// MissingSkinFile es una entrada de installed.json cuyo archivo ya no está en disco
+type MissingSkinFile struct {
+	ChampionId string   `json:"championId"`
+	SkinId     string   `json:"skinId"`
+	FileName   string   `json:"fileName"`
+	SkinName   string   `json:"skinName"`
+	ChromaName string   `json:"chromaName"`
+	Actions    []string `json:"actions"`
+}
+
+// StrayEntry es un archivo o directorio de installed/ que ninguna entrada referencia
+type StrayEntry struct {
+	Name    string   `json:"name"`
+	Size    int64    `json:"size"`
+	Actions []string `json:"actions"`
+}
+
+// ReconcileReport es el resultado de comparar installed.json con installed/
+type ReconcileReport struct {
+	CheckedAt      string            `json:"checkedAt"`
+	Consistent     bool              `json:"consistent"`
+	MissingFiles   []MissingSkinFile `json:"missingFiles"`
+	UntrackedFiles []StrayEntry      `json:"untrackedFiles"`
+	LeftoverDirs   []StrayEntry      `json:"leftoverDirs"`
+}
+
+// RepairRequest describe una acción de reparación elegida por el usuario.
+// Target es el championId para redownload/drop y el nombre de archivo o directorio para adopt/delete.
+type RepairRequest struct {
+	Action     string `json:"action"`
+	Target     string `json:"target"`
+	ChampionId string `json:"championId"` // Solo adopt; con CustomSkinKeyPrefix queda como mod local
+	SkinId     string `json:"skinId"`     // Solo adopt (opcional)
+	SkinName   string `json:"skinName"`   // Solo adopt (opcional)
+	UserId     string `json:"userId"`     // Solo redownload
+	Token      string `json:"token"`      // Solo redownload
+}
+
+// isInstalledMetadataFile indica si name es un archivo propio de la app dentro de installed/
+// (installed.json, sus copias .bakN, temporales e importaciones en curso) y no un mod.
+func isInstalledMetadataFile(name string) bool {
+	if name == "installed.json" || strings.HasPrefix(name, "installed.json.bak") {
+		return true
+	}
+	return strings.HasSuffix(name, ".tmp") || strings.HasPrefix(name, stagingFilePrefix)
+}
+
+// ReconcileResult es la respuesta de ReconcileInstalled
+type ReconcileResult struct {
+	Result
+	Report *ReconcileReport `json:"report,omitempty"`
+}
+
+// ReconcileInstalled compara installed.json con el contenido de installed/ y
+// devuelve qué entradas no tienen archivo, qué archivos no están registrados y
+// qué directorios de importaciones anteriores quedaron sueltos. No modifica nada;
+// las correcciones se aplican con RepairInstalled.
+func (a *App) ReconcileInstalled() ReconcileResult {
+	report, err := a.reconcileInstalled()
+	if err != nil {
+		return ReconcileResult{Result: failResult(ErrStorageFailed, "Could not check installed skins", err)}
+	}
+	return ReconcileResult{Result: okResult(""), Report: report}
+}
+
+func (a *App) reconcileInstalled() (*ReconcileReport, error) {
+	report := &ReconcileReport{
+		CheckedAt:      time.Now().Format(time.RFC3339),
+		MissingFiles:   []MissingSkinFile{},
+		UntrackedFiles: []StrayEntry{},
+		LeftoverDirs:   []StrayEntry{},
+	}
+
+	entries, err := os.ReadDir(absInstalledPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading %s: %w", absInstalledPath, err)
+	}
+
+	// Nombres referenciados por installed.json; un directorio con el nombre del
+	// archivo (con o sin extensión) se considera parte de esa entrada.
+	installed := a.installedSkins.Snapshot()
+	tracked := make(map[string]bool)
+	for _, skin := range installed {
+		if skin.FileName == "" {
+			continue
+		}
+		tracked[skin.FileName] = true
+		tracked[strings.TrimSuffix(skin.FileName, filepath.Ext(skin.FileName))] = true
+	}
+
+	onDisk := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		onDisk[name] = true
+		if tracked[name] {
+			continue
+		}
+		if entry.IsDir() {
+			report.LeftoverDirs = append(report.LeftoverDirs, StrayEntry{
+				Name:    name,
+				Size:    dirSize(filepath.Join(absInstalledPath, name)),
+				Actions: []string{RepairDelete},
+			})
+			continue
+		}
+		if isInstalledMetadataFile(name) {
+			continue
+		}
+		var size int64
+		if info, err := entry.Info(); err == nil {
+			size = info.Size()
+		}
+		report.UntrackedFiles = append(report.UntrackedFiles, StrayEntry{
+			Name:    name,
+			Size:    size,
+			Actions: []string{RepairAdopt, RepairDelete},
+		})
+	}
+
+	for championId, skin := range installed {
+		if skin.FileName != "" && onDisk[skin.FileName] {
+			continue
+		}
+		// Los mods locales no están en el catálogo: solo se puede quitar la entrada
+		actions := []string{RepairRedownload, RepairDrop}
+		if !canRedownload(championId, skin) {
+			actions = []string{RepairDrop}
+		}
+		report.MissingFiles = append(report.MissingFiles, MissingSkinFile{
+			ChampionId: championId,
+			SkinId:     skin.SkinId,
+			FileName:   skin.FileName,
+			SkinName:   skin.SkinName,
+			ChromaName: skin.ChromaName,
+			Actions:    actions,
+		})
+	}
+
+	sort.Slice(report.MissingFiles, func(i, j int) bool {
+		return report.MissingFiles[i].ChampionId < report.MissingFiles[j].ChampionId
+	})
+	sort.Slice(report.UntrackedFiles, func(i, j int) bool { return report.UntrackedFiles[i].Name < report.UntrackedFiles[j].Name })
+	sort.Slice(report.LeftoverDirs, func(i, j int) bool { return report.LeftoverDirs[i].Name < report.LeftoverDirs[j].Name })
+
+	report.Consistent = len(report.MissingFiles) == 0 && len(report.UntrackedFiles) == 0 && len(report.LeftoverDirs) == 0
+	return report, nil
+}
+
+// reconcileAtStartup corre ReconcileInstalled y avisa al frontend si hay inconsistencias
+func (a *App) reconcileAtStartup() {
+	report, err := a.reconcileInstalled()
+	if err != nil {
+		a.logger(LogSubsystemStorage).Errorf("Startup reconcile failed: %v", err)
+		return
+	}
+	if report.Consistent {
+		a.logger(LogSubsystemStorage).Info("Startup reconcile: installed.json matches installed folder.")
+		return
+	}
+	a.logger(LogSubsystemStorage).Warningf("Startup reconcile: %d missing files, %d untracked files, %d leftover directories.",
+		len(report.MissingFiles), len(report.UntrackedFiles), len(report.LeftoverDirs))
+	a.emit("installed-reconciled", report)
+}
+
+// canRedownload indica si la entrada key de installedSkins tiene un objeto en el
+// catálogo: no es un mod local y su SkinId es un ID completo de skin
+func canRedownload(key string, skin SkinInfo) bool {
+	if strings.HasPrefix(key, CustomSkinKeyPrefix) || skin.Source == SkinSourceCustom {
+		return false
+	}
+	_, err := strconv.Atoi(skin.SkinId)
+	return err == nil
+}
+
+// RepairInstalled aplica una de las acciones propuestas por ReconcileInstalled.
+// Las que cambian las skins instaladas recrean el overlay, así que no se pueden
+// aplicar durante una partida.
+func (a *App) RepairInstalled(req RepairRequest) Result {
+	switch req.Action {
+	case RepairRedownload:
+		skin, exists := a.installedSkins.Get(req.Target)
+		if !exists {
+			return failResult(ErrNotFound, "Skin not found", nil)
+		}
+		if !canRedownload(req.Target, skin) {
+			return failResult(ErrInvalidArgument, "Only catalog skins can be downloaded again", nil)
+		}
+		if err := a.checkOverlayNotInGame(); err != nil {
+			return errorResult(err)
+		}
+		// installed.json guarda el ID completo (103015); el catálogo y el libro de
+		// fichas usan el número de skin dentro del campeón (15)
+		id, _ := strconv.Atoi(skin.SkinId)
+		result := a.DownloadSkin(req.Target, strconv.Itoa(id%1000), req.UserId, req.Token, skin.SkinName, skin.FileName, skin.ChromaName, skin.ImageUrl, skin.SkinName)
+		if !result.Success {
+			return result.Result
+		}
+		if err := a.importModFile(filepath.Join(absInstalledPath, skin.FileName)); err != nil {
+			return errorResult(err)
+		}
+		if err := a.rebuildInstalledOverlay(); err != nil {
+			return errorResult(err)
+		}
+		a.logger(LogSubsystemStorage).Infof("RepairInstalled: Re-downloaded %s for champion %s", skin.FileName, req.Target)
+		return okResult(MsgRepairRedownloaded)
+
+	case RepairDrop:
+		if err := a.checkOverlayNotInGame(); err != nil {
+			return errorResult(err)
+		}
+		if _, exists := a.installedSkins.Delete(req.Target); !exists {
+			return failResult(ErrNotFound, "Skin not found", nil)
+		}
+		if err := a.saveInstalledSkins(); err != nil {
+			return failResult(ErrStorageFailed, "Failed to save installed skins", err)
+		}
+		if err := a.rebuildInstalledOverlay(); err != nil {
+			return errorResult(err)
+		}
+		a.logger(LogSubsystemStorage).Infof("RepairInstalled: Dropped entry for champion %s", req.Target)
+		return okResult(MsgRepairDropped)
+
+	case RepairAdopt:
+		if !isPlainFileName(req.Target) || isInstalledMetadataFile(req.Target) {
+			return failResult(ErrInvalidArgument, "Invalid file name", nil)
+		}
+		if req.ChampionId == "" {
+			return failResult(ErrInvalidArgument, "A champion is required to adopt a file", nil)
+		}
+		absFilePath := filepath.Join(absInstalledPath, req.Target)
+		info, err := os.Stat(absFilePath)
+		if err != nil || info.IsDir() {
+			return failResult(ErrNotFound, "File not found", nil)
+		}
+		// Reemplazar una entrada cuyo archivo sigue en disco lo dejaría huérfano
+		if previous, exists := a.installedSkins.Get(req.ChampionId); exists && previous.FileName != req.Target {
+			if _, err := os.Stat(filepath.Join(absInstalledPath, previous.FileName)); previous.FileName != "" && err == nil {
+				return failResult(ErrInvalidArgument, fmt.Sprintf("Champion %s already has %s installed", req.ChampionId, previous.FileName), nil)
+			}
+		}
+		if err := a.checkOverlayNotInGame(); err != nil {
+			return errorResult(err)
+		}
+		if err := a.importModFile(absFilePath); err != nil {
+			return errorResult(err)
+		}
+		skinName := req.SkinName
+		if skinName == "" {
+			skinName = strings.TrimSuffix(req.Target, filepath.Ext(req.Target))
+		}
+		source := SkinSourceCatalog
+		if strings.HasPrefix(req.ChampionId, CustomSkinKeyPrefix) {
+			source = SkinSourceCustom
+		}
+		a.installedSkins.Set(req.ChampionId, SkinInfo{
+			SkinId:    req.SkinId,
+			FileName:  req.Target,
+			ProcessId: "0",
+			SkinName:  skinName,
+			Source:    source,
+		})
+		if err := a.saveInstalledSkins(); err != nil {
+			return failResult(ErrStorageFailed, "Failed to save installed skins", err)
+		}
+		if err := a.rebuildInstalledOverlay(); err != nil {
+			return errorResult(err)
+		}
+		a.logger(LogSubsystemStorage).Infof("RepairInstalled: Adopted %s for champion %s", req.Target, req.ChampionId)
+		return okResult(MsgRepairAdopted)
+
+	case RepairDelete:
+		if !isPlainFileName(req.Target) || isInstalledMetadataFile(req.Target) {
+			return failResult(ErrInvalidArgument, "Invalid file name", nil)
+		}
+		for _, skin := range a.installedSkins.Snapshot() {
+			if skin.FileName == req.Target {
+				return failResult(ErrInvalidArgument, "File is referenced by an installed skin", nil)
+			}
+		}
+		if err := os.RemoveAll(filepath.Join(absInstalledPath, req.Target)); err != nil {
+			return failResult(ErrStorageFailed, fmt.Sprintf("Failed to delete %s", req.Target), err)
+		}
+		a.logger(LogSubsystemStorage).Infof("RepairInstalled: Deleted %s", req.Target)
+		return okResult(MsgRepairDeleted)
+	}
+	return failResult(ErrInvalidArgument, fmt.Sprintf("Unknown repair action: %s", req.Action), nil)
+}
+
+// rebuildInstalledOverlay recrea el perfil del overlay con las skins instaladas y
+// espera a mkoverlay; si mod-tools estaba corriendo lo reinicia con el perfil nuevo
+func (a *App) rebuildInstalledOverlay() error {
+	running := a.CheckModToolsRunning()
+	if running {
+		a.killModTools()
+	}
+	if err := a.buildOverlay(); err != nil {
+		return err
+	}
+	if running {
+		if _, err := a.restartModTools(); err != nil {
+			return newAppError(ErrModToolsStartFailed, "Failed to restart overlay", err)
+		}
+	}
+	return nil
+}
+
+// isPlainFileName evita que un nombre recibido del frontend salga de installed/
+func isPlainFileName(name string) bool {
+	return name != "" && name != "." && name != ".." && filepath.Base(name) == name
+}
+
+// dirSize suma el tamaño de los archivos bajo dir (0 si no se puede recorrer)
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// useFakeModTools pone en absModToolsPath un script que anota cada comando en un
+// archivo y apunta absGamePath a una carpeta temporal. Devuelve una función que
+// lee los comandos anotados. Llamar después de useTempPaths.
+func useFakeModTools(t *testing.T) func() []string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake mod-tools is a shell script")
+	}
+	savedGamePath := absGamePath
+	t.Cleanup(func() { absGamePath = savedGamePath })
+	absGamePath = t.TempDir()
+
+	logPath := filepath.Join(t.TempDir(), "mod-tools.log")
+	script := "#!/bin/sh\necho \"$@\" >> '" + logPath + "'\n"
+	if err := os.MkdirAll(filepath.Dir(absModToolsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(absModToolsPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return func() []string {
+		data, _ := os.ReadFile(logPath)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
+
+// installEntries registra skins en installedSkins y guarda installed.json
+func installEntries(t *testing.T, a *App, skins map[string]SkinInfo) {
+	t.Helper()
+	for key, skin := range skins {
+		a.installedSkins.Set(key, skin)
+	}
+	if err := a.saveInstalledSkins(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeInstalledFile crea installed/name con content
+func writeInstalledFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(absInstalledPath, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// hasCommand indica si algún comando anotado por el mod-tools falso empieza con prefix
+func hasCommand(commands []string, prefix string) bool {
+	for _, c := range commands {
+		if strings.HasPrefix(c, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReconcileInstalledReport(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	installEntries(t, a, map[string]SkinInfo{
+		"103":          {SkinId: "103015", FileName: "ahri.fantome", Source: SkinSourceCatalog},
+		"157":          {SkinId: "157001", FileName: "yasuo.fantome", Source: SkinSourceCatalog},
+		"custom:cloud": {FileName: "cloud.fantome", Source: SkinSourceCustom},
+	})
+	writeInstalledFile(t, "ahri.fantome", "ahri")
+	writeInstalledFile(t, "stray.fantome", "stray")
+	writeInstalledFile(t, stagingFilePrefix+"half.fantome", "half")
+	writeInstalledFile(t, "leftover.tmp", "tmp")
+	for _, dir := range []string{"ahri", "old-import"} {
+		if err := os.MkdirAll(filepath.Join(absInstalledPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := a.ReconcileInstalled()
+	if !result.Success {
+		t.Fatalf("ReconcileInstalled = %+v", result.Result)
+	}
+	report := result.Report
+	if report.Consistent {
+		t.Error("report is consistent")
+	}
+	wantMissing := []MissingSkinFile{
+		{ChampionId: "157", SkinId: "157001", FileName: "yasuo.fantome", Actions: []string{RepairRedownload, RepairDrop}},
+		{ChampionId: "custom:cloud", FileName: "cloud.fantome", Actions: []string{RepairDrop}},
+	}
+	if !reflect.DeepEqual(report.MissingFiles, wantMissing) {
+		t.Errorf("missing = %+v, want %+v", report.MissingFiles, wantMissing)
+	}
+	wantUntracked := []StrayEntry{{Name: "stray.fantome", Size: 5, Actions: []string{RepairAdopt, RepairDelete}}}
+	if !reflect.DeepEqual(report.UntrackedFiles, wantUntracked) {
+		t.Errorf("untracked = %+v, want %+v", report.UntrackedFiles, wantUntracked)
+	}
+	if len(report.LeftoverDirs) != 1 || report.LeftoverDirs[0].Name != "old-import" {
+		t.Errorf("leftover dirs = %+v", report.LeftoverDirs)
+	}
+
+	// Sin installed/ todo está vacío y es consistente
+	os.RemoveAll(absInstalledPath)
+	a.installedSkins.Replace(map[string]SkinInfo{})
+	if result := a.ReconcileInstalled(); !result.Success || !result.Report.Consistent {
+		t.Errorf("empty = %+v", result)
+	}
+}
+
+// Volver a descargar usa el número de skin del catálogo y no cobra una skin ya pagada
+func TestRepairRedownload(t *testing.T) {
+	a, backend := newMemoryTestApp(t, 2)
+	commands := useFakeModTools(t)
+	login(t, a)
+	backend.PutObject(a.currentSettings().Backend.SkinsBucket, catalogSkinPath("103", "15"), []byte("spirit blossom ahri"))
+
+	if result := a.DownloadSkin("103", "15", "", "", "Spirit Blossom Ahri", "ahri-15.fantome", "", "", "Spirit Blossom Ahri"); !result.Success {
+		t.Fatalf("DownloadSkin = %+v", result)
+	}
+	installEntries(t, a, map[string]SkinInfo{
+		"103":          {SkinId: "103015", FileName: "ahri-15.fantome", SkinName: "Spirit Blossom Ahri", Source: SkinSourceCatalog},
+		"custom:cloud": {FileName: "cloud.fantome", Source: SkinSourceCustom},
+	})
+	os.Remove(filepath.Join(absInstalledPath, "ahri-15.fantome"))
+
+	if result := a.RepairInstalled(RepairRequest{Action: RepairRedownload, Target: "103"}); !result.Success {
+		t.Fatalf("RepairInstalled = %+v", result)
+	}
+	if content, _ := os.ReadFile(filepath.Join(absInstalledPath, "ahri-15.fantome")); string(content) != "spirit blossom ahri" {
+		t.Errorf("file = %q", content)
+	}
+	if profile, _ := backend.Profile(testUserID); profile.FichasPorSkin != 1 {
+		t.Errorf("tokens left = %d, want 1", profile.FichasPorSkin)
+	}
+	got := commands()
+	if !hasCommand(got, "import "+filepath.Join(absInstalledPath, "ahri-15.fantome")) || !hasCommand(got, "mkoverlay ") {
+		t.Errorf("mod-tools commands = %q", got)
+	}
+
+	if result := a.RepairInstalled(RepairRequest{Action: RepairRedownload, Target: "custom:cloud"}); result.Code != ErrInvalidArgument {
+		t.Errorf("redownload a local mod = %+v", result)
+	}
+	if result := a.RepairInstalled(RepairRequest{Action: RepairRedownload, Target: "999"}); result.Code != ErrNotFound {
+		t.Errorf("redownload an unknown entry = %+v", result)
+	}
+}
+
+func TestRepairAdopt(t *testing.T) {
+	useTempPaths(t)
+	commands := useFakeModTools(t)
+	a := newTestApp(t)
+	installEntries(t, a, map[string]SkinInfo{
+		"103": {SkinId: "103015", FileName: "ahri.fantome", Source: SkinSourceCatalog},
+		"157": {SkinId: "157001", FileName: "yasuo.fantome", Source: SkinSourceCatalog},
+	})
+	writeInstalledFile(t, "ahri.fantome", "ahri")
+	for _, name := range []string{"stray.fantome", "mine.fantome", "yasuo-new.fantome"} {
+		writeInstalledFile(t, name, name)
+	}
+
+	tests := []struct {
+		name       string
+		req        RepairRequest
+		wantSource string
+	}{
+		{"catalog champion", RepairRequest{Target: "stray.fantome", ChampionId: "266", SkinId: "266001"}, SkinSourceCatalog},
+		{"local mod", RepairRequest{Target: "mine.fantome", ChampionId: "custom:mine", SkinName: "Mine"}, SkinSourceCustom},
+		{"replaces an entry whose file is missing", RepairRequest{Target: "yasuo-new.fantome", ChampionId: "157"}, SkinSourceCatalog},
+	}
+	for _, tt := range tests {
+		tt.req.Action = RepairAdopt
+		if result := a.RepairInstalled(tt.req); !result.Success {
+			t.Errorf("%s: %+v", tt.name, result)
+			continue
+		}
+		skin, _ := a.installedSkins.Get(tt.req.ChampionId)
+		if skin.FileName != tt.req.Target || skin.Source != tt.wantSource {
+			t.Errorf("%s: entry = %+v", tt.name, skin)
+		}
+		if got := commands(); !hasCommand(got, "import "+filepath.Join(absInstalledPath, tt.req.Target)) {
+			t.Errorf("%s: %s was not imported: %q", tt.name, tt.req.Target, got)
+		}
+	}
+	if got := commands(); !hasCommand(got, "mkoverlay ") {
+		t.Errorf("overlay was not rebuilt: %q", got)
+	}
+
+	// Una entrada cuyo archivo sigue en disco no se reemplaza
+	writeInstalledFile(t, "other.fantome", "other")
+	if result := a.RepairInstalled(RepairRequest{Action: RepairAdopt, Target: "other.fantome", ChampionId: "103"}); result.Code != ErrInvalidArgument {
+		t.Errorf("adopt over an installed skin = %+v", result)
+	}
+	if skin, _ := a.installedSkins.Get("103"); skin.FileName != "ahri.fantome" {
+		t.Errorf("entry 103 = %+v", skin)
+	}
+
+	for _, req := range []RepairRequest{
+		{Target: "other.fantome"},
+		{Target: "../other.fantome", ChampionId: "1"},
+		{Target: "installed.json", ChampionId: "1"},
+		{Target: "gone.fantome", ChampionId: "1"},
+	} {
+		req.Action = RepairAdopt
+		if result := a.RepairInstalled(req); result.Success {
+			t.Errorf("adopt %+v succeeded", req)
+		}
+	}
+}
+
+func TestRepairDropRebuildsOverlay(t *testing.T) {
+	useTempPaths(t)
+	commands := useFakeModTools(t)
+	a := newTestApp(t)
+	installEntries(t, a, map[string]SkinInfo{
+		"103": {SkinId: "103015", FileName: "ahri.fantome", Source: SkinSourceCatalog},
+		"157": {SkinId: "157001", FileName: "yasuo.fantome", Source: SkinSourceCatalog},
+	})
+	writeInstalledFile(t, "ahri.fantome", "ahri")
+
+	if result := a.RepairInstalled(RepairRequest{Action: RepairDrop, Target: "157"}); !result.Success {
+		t.Fatalf("drop = %+v", result)
+	}
+	if _, exists := a.installedSkins.Get("157"); exists {
+		t.Error("entry 157 is still installed")
+	}
+	if err := a.loadInstalledSkins(); err != nil || a.installedSkins.Len() != 1 {
+		t.Errorf("installed.json after drop: %d entries, %v", a.installedSkins.Len(), err)
+	}
+	got := commands()
+	if !hasCommand(got, "mkoverlay ") || !strings.HasSuffix(got[len(got)-1], "--mods:ahri.fantome") {
+		t.Errorf("mod-tools commands = %q", got)
+	}
+	if result := a.RepairInstalled(RepairRequest{Action: RepairDrop, Target: "157"}); result.Code != ErrNotFound {
+		t.Errorf("drop twice = %+v", result)
+	}
+}
+
+func TestRepairDelete(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	installEntries(t, a, map[string]SkinInfo{"103": {SkinId: "103015", FileName: "ahri.fantome"}})
+	writeInstalledFile(t, "ahri.fantome", "ahri")
+	writeInstalledFile(t, "stray.fantome", "stray")
+	if err := os.MkdirAll(filepath.Join(absInstalledPath, "old-import", "WAD"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{"stray.fantome", "old-import"} {
+		if result := a.RepairInstalled(RepairRequest{Action: RepairDelete, Target: target}); !result.Success {
+			t.Errorf("delete %s = %+v", target, result)
+		}
+		if _, err := os.Stat(filepath.Join(absInstalledPath, target)); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", target)
+		}
+	}
+	for _, target := range []string{"ahri.fantome", "installed.json", "..", "../x"} {
+		if result := a.RepairInstalled(RepairRequest{Action: RepairDelete, Target: target}); result.Code != ErrInvalidArgument {
+			t.Errorf("delete %s = %+v", target, result)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(absInstalledPath, "ahri.fantome")); err != nil {
+		t.Errorf("referenced file was deleted: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+// Result es la respuesta base de los métodos expuestos al frontend. Las respuestas
+// con datos la embeben, así que en JSON siempre están success, code, error y message.
+// error y message están en el idioma activo; detail es el texto técnico en inglés.
+type Result struct {
+	Success bool      `json:"success"`
+	Code    ErrorCode `json:"code,omitempty"`    // Solo si falló
+	Error   string    `json:"error,omitempty"`   // Texto para mostrar, según el código
+	Detail  string    `json:"detail,omitempty"`  // Causa concreta, para registros y soporte
+	Message string    `json:"message,omitempty"` // Aviso opcional si salió bien
+}
+
+// okResult es una respuesta exitosa con el aviso key (puede ser "")
+func okResult(key MessageKey, args ...interface{}) Result {
+	return Result{Success: true, Message: tr(key, args...)}
+}
+
+// errorResult es la respuesta para err, con el código que le asigna toAppError
+func errorResult(err error) Result {
+	appErr := toAppError(err)
+	return Result{Code: appErr.Code, Error: tr(MessageKey(appErr.Code)), Detail: appErr.Error()}
+}
+
+// failResult es una respuesta fallida con código y causa opcional
+func failResult(code ErrorCode, message string, cause error) Result {
+	return errorResult(newAppError(code, message, cause))
+}
+
+// Err devuelve el error de una respuesta fallida, o nil
+func (r Result) Err() error {
+	if r.Success {
+		return nil
+	}
+	message := r.Detail
+	if message == "" {
+		message = r.Error
+	}
+	return &AppError{Code: r.Code, Message: message}
+}
+
+// OverlayResult es la respuesta de los métodos que arrancan o detienen el overlay
+type OverlayResult struct {
+	Result
+	PID            int  `json:"pid,omitempty"`            // PID de mod-tools.exe, si se conoce
+	AlreadyRunning bool `json:"alreadyRunning,omitempty"` // Se pidió arrancarlo y ya estaba en marcha
+}
+
+// CommandResult es la respuesta de un comando de mod-tools
+type CommandResult struct {
+	Result
+	Output string `json:"output,omitempty"`
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxRouletteHistory es cuántas elecciones recientes se recuerdan por campeón
+const maxRouletteHistory = 20
+
+// RouletteSettings configura la ruleta de skins
+type RouletteSettings struct {
+	OnLockIn       bool               `json:"onLockIn"`       // Sortear la skin al confirmar el campeón
+	ExcludeRecent  int                `json:"excludeRecent"`  // Cuántas elecciones recientes evitar por campeón
+	IncludeChromas bool               `json:"includeChromas"` // Los chromas cuentan como candidatos propios
+	Weights        map[string]float64 `json:"weights"`        // Peso por nombre de archivo; 0 excluye
+}
+
+// defaultRouletteSettings no sortea sola y evita repetir las dos últimas
+func defaultRouletteSettings() RouletteSettings {
+	return RouletteSettings{
+		ExcludeRecent:  2,
+		IncludeChromas: true,
+		Weights:        map[string]float64{},
+	}
+}
+
+// normalize corrige valores fuera de rango
+func (s *RouletteSettings) normalize() {
+	if s.ExcludeRecent < 0 {
+		s.ExcludeRecent = 0
+	}
+	if s.ExcludeRecent > maxRouletteHistory {
+		s.ExcludeRecent = maxRouletteHistory
+	}
+	if s.Weights == nil {
+		s.Weights = map[string]float64{}
+	}
+}
+
+// RouletteCandidate es una skin o chroma que la ruleta puede elegir para un campeón.
+// FileName identifica al candidato (pesos e historial se indexan por él).
+type RouletteCandidate struct {
+	ChampionId string `json:"championId"`
+	SkinId     string `json:"skinId"` // ID completo (campeón*1000 + número), como en installedSkins
+	SkinName   string `json:"skinName"`
+	ChromaName string `json:"chromaName"`
+	FileName   string `json:"fileName"`
+	ImageUrl   string `json:"imageUrl"`
+	Source     string `json:"source"`
+	Installed  bool   `json:"installed"` // El archivo ya está en installed/ y no hace falta descargarlo
+}
+
+// RouletteOptions son los parámetros de un sorteo
+type RouletteOptions struct {
+	ChampionId     string             `json:"championId"`     // Vacío: el campeón confirmado en la selección
+	AllChampions   bool               `json:"allChampions"`   // Sortear para cada campeón con skin instalada
+	IncludeCatalog bool               `json:"includeCatalog"` // Incluir skins del catálogo no descargadas (gasta fichas)
+	IncludeChromas *bool              `json:"includeChromas"` // nil: usar la configuración
+	ExcludeRecent  *int               `json:"excludeRecent"`  // nil: usar la configuración
+	Weights        map[string]float64 `json:"weights"`        // Se suman a los de la configuración
+	Token          string             `json:"token"`          // Solo para descargar del catálogo
+}
+
+// Roulette elige candidatos al azar con pesos y evita repetir los recientes.
+// La fuente aleatoria se puede sembrar para obtener sorteos reproducibles.
+type Roulette struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	recent map[string][]string // Campeón -> nombres de archivo elegidos, del más viejo al más nuevo
+}
+
+// NewRoulette crea una ruleta con la semilla dada
+func NewRoulette(seed int64) *Roulette {
+	return &Roulette{
+		rng:    rand.New(rand.NewSource(seed)),
+		recent: map[string][]string{},
+	}
+}
+
+// Pick elige un candidato de championId. Los de peso <= 0 nunca salen, el peso por
+// defecto es 1 y se evitan las últimas excludeRecent elecciones mientras quede otro.
+// No cambia el historial: quien aplica la elección llama a Record si salió bien.
+func (r *Roulette) Pick(championId string, candidates []RouletteCandidate, weights map[string]float64, excludeRecent int) (RouletteCandidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pool := make([]RouletteCandidate, 0, len(candidates))
+	poolWeights := make([]float64, 0, len(candidates))
+	for _, c := range candidates {
+		w, ok := weights[c.FileName]
+		if !ok {
+			w = 1
+		}
+		if w > 0 {
+			pool = append(pool, c)
+			poolWeights = append(poolWeights, w)
+		}
+	}
+	if len(pool) == 0 {
+		return RouletteCandidate{}, fmt.Errorf("no skins available for champion %s", championId)
+	}
+
+	// Evitar los recientes solo si no vacía el sorteo
+	if recent := r.recentLocked(championId, excludeRecent); len(recent) > 0 {
+		fresh := pool[:0:0]
+		freshWeights := poolWeights[:0:0]
+		for i, c := range pool {
+			if !recent[c.FileName] {
+				fresh = append(fresh, c)
+				freshWeights = append(freshWeights, poolWeights[i])
+			}
+		}
+		if len(fresh) > 0 {
+			pool, poolWeights = fresh, freshWeights
+		}
+	}
+
+	total := 0.0
+	for _, w := range poolWeights {
+		total += w
+	}
+	target := r.rng.Float64() * total
+	picked := pool[len(pool)-1]
+	for i, w := range poolWeights {
+		if target < w {
+			picked = pool[i]
+			break
+		}
+		target -= w
+	}
+	return picked, nil
+}
+
+// Record agrega fileName al historial de championId
+func (r *Roulette) Record(championId, fileName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history := append(r.recent[championId], fileName)
+	if len(history) > maxRouletteHistory {
+		history = history[len(history)-maxRouletteHistory:]
+	}
+	r.recent[championId] = history
+}
+
+// Recent devuelve las últimas elecciones de championId, de la más vieja a la más nueva
+func (r *Roulette) Recent(championId string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.recent[championId]...)
+}
+
+func (r *Roulette) recentLocked(championId string, n int) map[string]bool {
+	history := r.recent[championId]
+	if n <= 0 || len(history) == 0 {
+		return nil
+	}
+	if n < len(history) {
+		history = history[len(history)-n:]
+	}
+	recent := make(map[string]bool, len(history))
+	for _, fileName := range history {
+		recent[fileName] = true
+	}
+	return recent
+}
+
+var regexFileNameInvalid = regexp.MustCompile(`(?i)[^a-z0-9\s-]`)
+var regexFileNameSpaces = regexp.MustCompile(`\s+`)
+
+// catalogFileName arma el nombre de archivo de una skin del catálogo igual que el frontend
+// ("<skin>[-<chroma>].fantome")
+func catalogFileName(skinName, chromaName string) string {
+	sanitize := func(name string) string {
+		name = regexFileNameInvalid.ReplaceAllString(name, "")
+		return strings.ToLower(regexFileNameSpaces.ReplaceAllString(name, "-"))
+	}
+	if chromaName == "" {
+		return sanitize(skinName) + ".fantome"
+	}
+	return sanitize(skinName) + "-" + sanitize(chromaName) + ".fantome"
+}
+
+// communityDragonChampionsURL es el prefijo que el frontend quita de tilePath al guardar imageUrl
+const communityDragonChampionsURL = "https://raw.communitydragon.org/latest/plugins/rcp-be-lol-game-data/global/default/v1/champions/"
+
+// catalogChampion es la parte del JSON de un campeón que usa la ruleta
+type catalogChampion struct {
+	Skins []struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		IsBase   bool   `json:"isBase"`
+		TilePath string `json:"tilePath"`
+		Chromas  []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"chromas"`
+	} `json:"skins"`
+}
+
+// parseCatalogCandidates lee las skins (sin la base) y chromas del JSON de un campeón
+func parseCatalogCandidates(championId string, data []byte, includeChromas bool) ([]RouletteCandidate, error) {
+	var champion catalogChampion
+	if err := json.Unmarshal(data, &champion); err != nil {
+		return nil, fmt.Errorf("invalid champion data: %v", err)
+	}
+	candidates := []RouletteCandidate{}
+	for _, skin := range champion.Skins {
+		if skin.IsBase {
+			continue
+		}
+		candidates = append(candidates, RouletteCandidate{
+			ChampionId: championId,
+			SkinId:     strconv.Itoa(skin.ID),
+			SkinName:   skin.Name,
+			FileName:   catalogFileName(skin.Name, ""),
+			ImageUrl:   strings.TrimPrefix(skin.TilePath, communityDragonChampionsURL),
+			Source:     SkinSourceCatalog,
+		})
+		if !includeChromas {
+			continue
+		}
+		for _, chroma := range skin.Chromas {
+			candidates = append(candidates, RouletteCandidate{
+				ChampionId: championId,
+				SkinId:     strconv.Itoa(chroma.ID),
+				SkinName:   skin.Name,
+				ChromaName: chroma.Name,
+				FileName:   catalogFileName(skin.Name, chroma.Name),
+				ImageUrl:   strings.TrimPrefix(skin.TilePath, communityDragonChampionsURL),
+				Source:     SkinSourceCatalog,
+			})
+		}
+	}
+	return candidates, nil
+}
+
+// rouletteCandidates junta los candidatos de un campeón: la skin instalada, las que
+// ya se descargaron antes (según el libro de fichas) y, si se pide, el catálogo
+func (a *App) rouletteCandidates(championId string, includeCatalog, includeChromas bool) ([]RouletteCandidate, error) {
+	byFile := map[string]RouletteCandidate{}
+	add := func(c RouletteCandidate) {
+		if !includeChromas && c.ChromaName != "" {
+			return
+		}
+		if existing, ok := byFile[c.FileName]; ok && existing.Installed {
+			return
+		}
+		byFile[c.FileName] = c
+	}
+
+	if skin, ok := a.installedSkins.Get(championId); ok {
+		add(RouletteCandidate{
+			ChampionId: championId,
+			SkinId:     skin.SkinId,
+			SkinName:   skin.SkinName,
+			ChromaName: skin.ChromaName,
+			FileName:   skin.FileName,
+			ImageUrl:   skin.ImageUrl,
+			Source:     skin.Source,
+			Installed:  true,
+		})
+	}
+	if a.ledger != nil {
+		champNum, _ := strconv.Atoi(championId)
+		for _, e := range a.ledger.History("") {
+			if e.ChampionId != championId || e.Status != LedgerCommitted || !isPlainFileName(e.FileName) {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(absInstalledPath, e.FileName)); err != nil {
+				continue
+			}
+			skinId := e.SkinId
+			if num, err := strconv.Atoi(e.SkinId); err == nil && num < 1000 {
+				skinId = strconv.Itoa(champNum*1000 + num) // El libro guarda el número de skin
+			}
+			add(RouletteCandidate{
+				ChampionId: championId,
+				SkinId:     skinId,
+				SkinName:   e.SkinName,
+				ChromaName: e.ChromaName,
+				FileName:   e.FileName,
+				Source:     SkinSourceCatalog,
+				Installed:  true,
+			})
+		}
+	}
+
+	if includeCatalog {
+		data, err := a.backend.Catalog().ChampionJSON(championId)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching champion data: %v", err)
+		}
+		catalog, err := parseCatalogCandidates(championId, data, includeChromas)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range catalog {
+			add(c)
+		}
+	}
+
+	candidates := make([]RouletteCandidate, 0, len(byFile))
+	for _, c := range byFile {
+		candidates = append(candidates, c)
+	}
+	// Orden estable para que un sorteo con la misma semilla dé el mismo resultado
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].FileName < candidates[j].FileName })
+	return candidates, nil
+}
+
+// RouletteResult es la respuesta de GetRouletteCandidates y SpinRoulette
+type RouletteResult struct {
+	Result
+	Candidates []RouletteCandidate `json:"candidates,omitempty"`
+	Recent     []string            `json:"recent,omitempty"` // Archivos sorteados hace poco
+	Picks      []RouletteCandidate `json:"picks,omitempty"`
+	Failed     map[string]string   `json:"failed,omitempty"` // Campeón -> motivo
+}
+
+// GetRouletteCandidates devuelve los candidatos de un campeón, para mostrar y ajustar pesos
+func (a *App) GetRouletteCandidates(championId string, includeCatalog bool) RouletteResult {
+	candidates, err := a.rouletteCandidates(championId, includeCatalog, a.currentSettings().Roulette.IncludeChromas)
+	if err != nil {
+		return RouletteResult{Result: failResult(ErrCatalogUnavailable, "Could not list roulette candidates", err)}
+	}
+	return RouletteResult{
+		Result:     okResult(""),
+		Candidates: candidates,
+		Recent:     a.roulette.Recent(championId),
+	}
+}
+
+// SpinRoulette sortea una skin para el campeón confirmado, uno elegido o cada campeón
+// con skin instalada, la registra como instalada y rearma el overlay
+func (a *App) SpinRoulette(opts RouletteOptions) RouletteResult {
+	if a.overlayLockedByGame() {
+		return RouletteResult{Result: errorResult(errOverlayInGame())}
+	}
+
+	settings := a.currentSettings().Roulette
+	includeChromas := settings.IncludeChromas
+	if opts.IncludeChromas != nil {
+		includeChromas = *opts.IncludeChromas
+	}
+	excludeRecent := settings.ExcludeRecent
+	if opts.ExcludeRecent != nil {
+		excludeRecent = *opts.ExcludeRecent
+	}
+	weights := map[string]float64{}
+	for k, v := range settings.Weights {
+		weights[k] = v
+	}
+	for k, v := range opts.Weights {
+		weights[k] = v
+	}
+
+	lockedChampion := a.currentLockedChampion()
+	var champions []string
+	switch {
+	case opts.AllChampions:
+		if opts.IncludeCatalog {
+			return RouletteResult{Result: failResult(ErrInvalidArgument, "Catalog skins can only be included for a single champion", nil)}
+		}
+		for championId := range a.installedSkins.Snapshot() {
+			champions = append(champions, championId)
+		}
+		sort.Strings(champions)
+	case opts.ChampionId != "":
+		champions = []string{opts.ChampionId}
+	case lockedChampion != 0:
+		champions = []string{strconv.Itoa(lockedChampion)}
+	default:
+		return RouletteResult{Result: failResult(ErrInvalidArgument, "No champion selected", nil)}
+	}
+
+	picks := []RouletteCandidate{}
+	failed := map[string]string{}
+	for _, championId := range champions {
+		candidates, err := a.rouletteCandidates(championId, opts.IncludeCatalog, includeChromas)
+		if err == nil {
+			var picked RouletteCandidate
+			picked, err = a.roulette.Pick(championId, candidates, weights, excludeRecent)
+			if err == nil {
+				err = a.installRoulettePick(picked, opts.Token)
+			}
+			if err == nil {
+				picks = append(picks, picked)
+				continue
+			}
+		}
+		a.logger(LogSubsystemSkins).Warningf("Roulette: champion %s: %v", championId, err)
+		failed[championId] = err.Error()
+	}
+	if len(picks) == 0 {
+		return RouletteResult{Result: failResult(ErrNotFound, "No skin could be picked", nil), Failed: failed}
+	}
+	if err := a.saveInstalledSkins(); err != nil {
+		return RouletteResult{Result: failResult(ErrStorageFailed, "Failed to save installed skins", err), Picks: picks, Failed: failed}
+	}
+	for _, picked := range picks {
+		a.roulette.Record(picked.ChampionId, picked.FileName)
+	}
+	a.emit("roulette-picked", picks)
+
+	// Durante la selección el overlay lleva solo la skin del campeón confirmado
+	if !opts.AllChampions && len(picks) == 1 && picks[0].ChampionId == strconv.Itoa(lockedChampion) {
+		if err := a.applyChampionOverlay(lockedChampion); err != nil {
+			return RouletteResult{Result: errorResult(err), Picks: picks, Failed: failed}
+		}
+	} else if err := a.rebuildOverlayForRoulette(); err != nil {
+		return RouletteResult{Result: errorResult(err), Picks: picks, Failed: failed}
+	}
+
+	// Las skins elegidas ya están aplicadas: con algún campeón fallido sigue siendo
+	// un éxito y Failed dice cuáles quedaron como estaban
+	if len(failed) > 0 {
+		return RouletteResult{Result: okResult(MsgRoulettePickedPartial, len(picks), len(failed)), Picks: picks, Failed: failed}
+	}
+	return RouletteResult{Result: okResult(MsgRoulettePicked, len(picks)), Picks: picks}
+}
+
+// installRoulettePick deja el archivo elegido en installed/ (descargándolo si hace
+// falta) y lo registra en installedSkins; no guarda installed.json
+func (a *App) installRoulettePick(picked RouletteCandidate, token string) error {
+	if !picked.Installed {
+		num, err := strconv.Atoi(picked.SkinId)
+		if err != nil {
+			return fmt.Errorf("invalid skin id %q", picked.SkinId)
+		}
+		result := a.DownloadSkin(picked.ChampionId, strconv.Itoa(num%1000), "", token, picked.SkinName, picked.FileName, picked.ChromaName, picked.ImageUrl, picked.SkinName)
+		if err := result.Err(); err != nil {
+			return err
+		}
+		if err := a.importModFile(filepath.Join(absInstalledPath, picked.FileName)); err != nil {
+			return err
+		}
+	}
+	source := picked.Source
+	if source == "" {
+		source = SkinSourceCatalog
+	}
+	a.installedSkins.Set(picked.ChampionId, SkinInfo{
+		SkinId:     picked.SkinId,
+		FileName:   picked.FileName,
+		ProcessId:  "0",
+		ChromaName: picked.ChromaName,
+		SkinName:   picked.SkinName,
+		ImageUrl:   picked.ImageUrl,
+		Source:     source,
+	})
+	return nil
+}
+
+// rebuildOverlayForRoulette recrea el overlay con las skins instaladas; si mod-tools
+// estaba corriendo lo reinicia, si no solo deja el perfil listo con createOverlayOnly
+func (a *App) rebuildOverlayForRoulette() error {
+	if !a.CheckModToolsRunning() {
+		return a.createOverlayOnly().Err()
+	}
+	a.killModTools()
+	if err := a.buildOverlay(); err != nil {
+		return err
+	}
+	if _, err := a.restartModTools(); err != nil {
+		return fmt.Errorf("failed to restart overlay: %v", err)
+	}
+	return nil
+}
+
+// spinLockedChampion sortea entre las skins ya descargadas del campeón recién
+// confirmado; applyChampionOverlay arma después el overlay con la elegida
+func (a *App) spinLockedChampion(championId int) {
+	id := strconv.Itoa(championId)
+	settings := a.currentSettings().Roulette
+	candidates, err := a.rouletteCandidates(id, false, settings.IncludeChromas)
+	if err != nil || len(candidates) < 2 {
+		return // Nada que sortear
+	}
+	picked, err := a.roulette.Pick(id, candidates, settings.Weights, settings.ExcludeRecent)
+	if err != nil {
+		a.logger(LogSubsystemSkins).Warningf("Roulette: champion %s: %v", id, err)
+		return
+	}
+	if err := a.installRoulettePick(picked, ""); err != nil {
+		a.logger(LogSubsystemSkins).Warningf("Roulette: champion %s: %v", id, err)
+		return
+	}
+	if err := a.saveInstalledSkins(); err != nil {
+		a.logger(LogSubsystemSkins).Warningf("Roulette: could not save installed skins: %v", err)
+	} else {
+		a.roulette.Record(id, picked.FileName)
+	}
+	a.emit("roulette-picked", []RouletteCandidate{picked})
+}
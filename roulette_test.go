@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func rouletteFiles(names ...string) []RouletteCandidate {
+	candidates := make([]RouletteCandidate, len(names))
+	for i, name := range names {
+		candidates[i] = RouletteCandidate{ChampionId: "103", FileName: name, Installed: true}
+	}
+	return candidates
+}
+
+func TestRoulettePickIsReproducible(t *testing.T) {
+	candidates := rouletteFiles("a.fantome", "b.fantome", "c.fantome", "d.fantome")
+	r1, r2 := NewRoulette(42), NewRoulette(42)
+	for i := 0; i < 20; i++ {
+		p1, err1 := r1.Pick("103", candidates, nil, 0)
+		p2, err2 := r2.Pick("103", candidates, nil, 0)
+		if err1 != nil || err2 != nil {
+			t.Fatal(err1, err2)
+		}
+		if p1.FileName != p2.FileName {
+			t.Fatalf("pick %d: %s != %s with the same seed", i, p1.FileName, p2.FileName)
+		}
+	}
+}
+
+func TestRouletteAvoidsRecentPicks(t *testing.T) {
+	candidates := rouletteFiles("a.fantome", "b.fantome", "c.fantome")
+	r := NewRoulette(1)
+	for i := 0; i < 50; i++ {
+		recent := r.Recent("103")
+		picked, err := r.Pick("103", candidates, nil, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, fileName := range recent[max(0, len(recent)-2):] {
+			if picked.FileName == fileName {
+				t.Fatalf("pick %d: %s repeats one of the last two (%v)", i, fileName, recent)
+			}
+		}
+		r.Record("103", picked.FileName)
+	}
+	if n := len(r.Recent("103")); n != maxRouletteHistory {
+		t.Errorf("history has %d entries, want %d", n, maxRouletteHistory)
+	}
+}
+
+func TestRouletteRepeatsWhenNothingElseIsLeft(t *testing.T) {
+	r := NewRoulette(1)
+	r.Record("103", "a.fantome")
+	picked, err := r.Pick("103", rouletteFiles("a.fantome"), nil, 2)
+	if err != nil || picked.FileName != "a.fantome" {
+		t.Errorf("got %q, %v; want the only candidate", picked.FileName, err)
+	}
+}
+
+func TestRouletteWeightsExclude(t *testing.T) {
+	candidates := rouletteFiles("a.fantome", "b.fantome", "c.fantome")
+	r := NewRoulette(7)
+	weights := map[string]float64{"b.fantome": 0, "c.fantome": -1}
+	for i := 0; i < 50; i++ {
+		picked, err := r.Pick("103", candidates, weights, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if picked.FileName != "a.fantome" {
+			t.Fatalf("pick %d: got excluded %s", i, picked.FileName)
+		}
+	}
+	weights["a.fantome"] = 0
+	if _, err := r.Pick("103", candidates, weights, 0); err == nil {
+		t.Error("expected an error when every candidate is excluded")
+	}
+}
+
+func TestRoulettePickDoesNotRecord(t *testing.T) {
+	r := NewRoulette(1)
+	if _, err := r.Pick("103", rouletteFiles("a.fantome", "b.fantome"), nil, 2); err != nil {
+		t.Fatal(err)
+	}
+	if recent := r.Recent("103"); len(recent) != 0 {
+		t.Errorf("Pick recorded %v", recent)
+	}
+}
+
+// Una skin del catálogo que no se puede descargar no cuenta como elegida
+func TestSpinRouletteFailedInstallIsNotRecorded(t *testing.T) {
+	a := newTestApp(t)
+	backend := NewMemoryBackend()
+	backend.PutObject(ChampionJSONBucket, "103.json", []byte(`{"skins": [
+		{"id": 103000, "name": "Ahri", "isBase": true},
+		{"id": 103001, "name": "Dynasty Ahri"}
+	]}`))
+	a.backend = backend
+
+	result := a.SpinRoulette(RouletteOptions{ChampionId: "103", IncludeCatalog: true, Token: "not-a-jwt"})
+	if result.Success || result.Failed["103"] == "" {
+		t.Fatalf("expected the download to fail, got %+v", result)
+	}
+	if recent := a.roulette.Recent("103"); len(recent) != 0 {
+		t.Errorf("failed pick was recorded: %v", recent)
+	}
+}
+
+// Con varios campeones, los que fallan no anulan las skins ya aplicadas
+func TestSpinRoulettePartialSuccess(t *testing.T) {
+	useTempPaths(t)
+	a := newTestApp(t)
+	useFakeModTools(t)
+	for championId, fileName := range map[string]string{"103": "ahri.fantome", "222": "jinx.fantome"} {
+		a.installedSkins.Set(championId, SkinInfo{SkinId: championId + "001", FileName: fileName, Source: SkinSourceCatalog})
+		if err := os.WriteFile(filepath.Join(absInstalledPath, fileName), []byte(fileName), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Sin candidatos con peso, Jinx no puede salir
+	result := a.SpinRoulette(RouletteOptions{AllChampions: true, Weights: map[string]float64{"jinx.fantome": 0}})
+	if !result.Success || result.Code != "" {
+		t.Fatalf("SpinRoulette = %+v", result)
+	}
+	if len(result.Picks) != 1 || result.Picks[0].ChampionId != "103" || len(result.Failed) != 1 || result.Failed["222"] == "" {
+		t.Errorf("picks = %+v, failed = %v", result.Picks, result.Failed)
+	}
+	if result.Message != tr(MsgRoulettePickedPartial, 1, 1) {
+		t.Errorf("message = %q", result.Message)
+	}
+	if recent := a.roulette.Recent("103"); len(recent) != 1 {
+		t.Errorf("recent picks for 103 = %v", recent)
+	}
+
+	result = a.SpinRoulette(RouletteOptions{AllChampions: true})
+	if !result.Success || len(result.Picks) != 2 || result.Failed != nil || result.Message != tr(MsgRoulettePicked, 2) {
+		t.Errorf("full SpinRoulette = %+v", result)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// RPCError es un error devuelto por PostgREST al llamar una función de la base
+type RPCError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+	Hint    string `json:"hint"`
+}
+
+func (e *RPCError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("(%s) %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("status %d: %s", e.Status, e.Message)
+}
+
+// callRPC llama a /rest/v1/rpc/<name> con el token del usuario (o la anon key si
+// accessToken está vacío) para que las políticas RLS y SECURITY DEFINER de la base
+// decidan. A diferencia de supabase.Client.Rpc, los errores no se pierden.
+func callRPC(httpClient *http.Client, projectURL, apiKey, accessToken, name string, body, out interface{}) error {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error encoding %s arguments: %v", name, err)
+	}
+	req, err := http.NewRequest("POST", strings.TrimRight(projectURL, "/")+"/rest/v1/rpc/"+name, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	bearer := apiKey
+	if accessToken != "" {
+		bearer = accessToken
+	}
+	req.Header.Set("apikey", apiKey)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %v", name, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading %s response: %v", name, err)
+	}
+	if resp.StatusCode >= 400 {
+		rpcErr := &RPCError{Status: resp.StatusCode}
+		if json.Unmarshal(respBody, rpcErr) != nil || rpcErr.Message == "" {
+			rpcErr.Message = strings.TrimSpace(string(respBody))
+		}
+		return rpcErr
+	}
+	if out == nil || len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error decoding %s response: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+//go:build !windows
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Fuera de Windows no hay DPAPI: se usa AES-GCM con una clave aleatoria guardada
+// en el directorio de configuración del usuario, legible solo por él (0600).
+
+func secretKey() ([]byte, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	keyPath := filepath.Join(dir, "SkinHunter", "session.key")
+	if key, err := os.ReadFile(keyPath); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func secretAEAD() (cipher.AEAD, error) {
+	key, err := secretKey()
+	if err != nil {
+		return nil, fmt.Errorf("error loading session key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// protectSecret cifra data con la clave del usuario actual
+func protectSecret(data []byte) ([]byte, error) {
+	aead, err := secretAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// unprotectSecret descifra datos de protectSecret
+func unprotectSecret(data []byte) ([]byte, error) {
+	aead, err := secretAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid protected data")
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, nil)
+}
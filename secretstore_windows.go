@@ -0,0 +1,44 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// secretEntropy se mezcla con la clave de DPAPI para que otros programas del
+// mismo usuario no puedan descifrar el archivo con una llamada genérica.
+var secretEntropy = []byte("skinhunter-session-v1")
+
+// protectSecret cifra data con DPAPI, atado a la cuenta de Windows actual
+func protectSecret(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("nothing to protect")
+	}
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	entropy := windows.DataBlob{Size: uint32(len(secretEntropy)), Data: &secretEntropy[0]}
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(&in, nil, &entropy, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, fmt.Errorf("CryptProtectData failed: %v", err)
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), nil
+}
+
+// unprotectSecret descifra datos de protectSecret; falla si los cifró otro usuario
+func unprotectSecret(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("nothing to unprotect")
+	}
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	entropy := windows.DataBlob{Size: uint32(len(secretEntropy)), Data: &secretEntropy[0]}
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(&in, nil, &entropy, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, fmt.Errorf("CryptUnprotectData failed: %v", err)
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), nil
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// sessionRefreshLeeway es cuánto antes del vencimiento se refresca el access token
+	sessionRefreshLeeway = 2 * time.Minute
+	// sessionRetryDelay es la espera entre reintentos cuando el refresco falla por red
+	sessionRetryDelay = 30 * time.Second
+	// SessionFileName es el archivo cifrado con la sesión, en el directorio de configuración del usuario
+	SessionFileName = "session.dat"
+)
+
+// ErrNoSession indica que no hay una sesión iniciada
+var ErrNoSession = errors.New("not logged in")
+
+// SessionManager guarda los tokens de Supabase Auth del lado de Go, los refresca
+// antes de que venzan y los persiste cifrados para el usuario del sistema operativo.
+// El frontend nunca ve el refresh token.
+type SessionManager struct {
+	auth AuthProvider
+	path string
+	emit func(event string, data interface{})
+
+	mu         sync.Mutex
+	session    *AuthSession
+	timer      *time.Timer
+	refreshing *sessionRefresh // Refresco en curso, nil si no hay
+}
+
+// sessionRefresh es un refresco en curso; quien llega mientras tanto espera done
+// y recibe el mismo err en vez de gastar el refresh token otra vez
+type sessionRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// NewSessionManager crea el gestor. emit recibe "session-refreshed" y "session-expired".
+func NewSessionManager(auth AuthProvider, path string, emit func(event string, data interface{})) *SessionManager {
+	return &SessionManager{auth: auth, path: path, emit: emit}
+}
+
+// defaultSessionPath es %AppData%\SkinHunter\session.dat (o el equivalente del SO),
+// fuera de la carpeta de la app para que cada usuario tenga su propia sesión.
+func defaultSessionPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = absBasePath
+	}
+	return filepath.Join(dir, "SkinHunter", SessionFileName)
+}
+
+// Start reemplaza la sesión actual, la persiste y programa su refresco
+func (m *SessionManager) Start(session *AuthSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.session = session
+	m.scheduleLocked()
+	return m.persistLocked()
+}
+
+// Restore carga la sesión guardada. Si ya venció o está por vencer, la refresca enseguida.
+func (m *SessionManager) Restore() error {
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading %s: %v", m.path, err)
+	}
+	plain, err := unprotectSecret(data)
+	if err != nil {
+		os.Remove(m.path) // Cifrada por otro usuario o dañada: no sirve
+		return fmt.Errorf("error decrypting session: %v", err)
+	}
+	var session AuthSession
+	if err := json.Unmarshal(plain, &session); err != nil || session.RefreshToken == "" {
+		os.Remove(m.path)
+		return fmt.Errorf("invalid stored session")
+	}
+
+	m.mu.Lock()
+	m.session = &session
+	m.scheduleLocked()
+	m.mu.Unlock()
+	return nil
+}
+
+// Current devuelve una copia de la sesión actual, o nil
+func (m *SessionManager) Current() *AuthSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.session == nil {
+		return nil
+	}
+	s := *m.session
+	return &s
+}
+
+// AccessToken devuelve un access token vigente, refrescándolo primero si está por vencer
+func (m *SessionManager) AccessToken() (string, error) {
+	m.mu.Lock()
+	session := m.session
+	m.mu.Unlock()
+	if session == nil {
+		return "", ErrNoSession
+	}
+	if time.Until(time.Unix(session.ExpiresAt, 0)) > sessionRefreshLeeway {
+		return session.AccessToken, nil
+	}
+	if err := m.Refresh(); err != nil {
+		return "", err
+	}
+	return m.Current().AccessToken, nil
+}
+
+// Refresh pide tokens nuevos ahora. Si el servidor rechaza el refresh token la
+// sesión termina y se emite session-expired; los errores de red se reintentan.
+// La llamada al servidor se hace sin el lock, así Current y AccessToken no esperan
+// a la red, y los refrescos simultáneos comparten una sola llamada.
+func (m *SessionManager) Refresh() error {
+	m.mu.Lock()
+	if m.session == nil {
+		m.mu.Unlock()
+		return ErrNoSession
+	}
+	if r := m.refreshing; r != nil {
+		m.mu.Unlock()
+		<-r.done
+		return r.err
+	}
+	r := &sessionRefresh{done: make(chan struct{})}
+	m.refreshing = r
+	current := m.session
+	m.mu.Unlock()
+
+	refreshed, err := m.auth.Refresh(current.RefreshToken)
+
+	m.mu.Lock()
+	r.err = m.finishRefreshLocked(current, refreshed, err)
+	m.refreshing = nil
+	m.mu.Unlock()
+	close(r.done)
+	return r.err
+}
+
+// finishRefreshLocked aplica el resultado del refresco de current
+func (m *SessionManager) finishRefreshLocked(current, refreshed *AuthSession, err error) error {
+	if m.session != current {
+		// Logout o un login nuevo mientras se refrescaba: el resultado ya no aplica
+		if m.session == nil {
+			return ErrNoSession
+		}
+		return nil
+	}
+	if err != nil {
+		var authErr *AuthError
+		if errors.As(err, &authErr) && authErr.Status >= 400 && authErr.Status < 500 {
+			m.expireLocked("refresh_rejected")
+			return ErrNoSession
+		}
+		if time.Now().After(time.Unix(m.session.ExpiresAt, 0)) {
+			// Sin red y con el token ya vencido: se sigue reintentando, pero el token no sirve
+			m.retryLocked()
+			return fmt.Errorf("session expired and refresh failed: %v", err)
+		}
+		m.retryLocked()
+		return fmt.Errorf("session refresh failed: %v", err)
+	}
+
+	m.session = refreshed
+	m.scheduleLocked()
+	if err := m.persistLocked(); err != nil {
+		return err
+	}
+	m.emit("session-refreshed", map[string]interface{}{
+		"userId":    refreshed.UserID,
+		"expiresAt": refreshed.ExpiresAt,
+	})
+	return nil
+}
+
+// Logout borra la copia local y revoca la sesión en Supabase Auth
+func (m *SessionManager) Logout() error {
+	m.mu.Lock()
+	session := m.session
+	if session != nil {
+		m.clearLocked()
+	}
+	m.mu.Unlock()
+	if session == nil {
+		return nil
+	}
+	return m.auth.SignOut(session.AccessToken)
+}
+
+func (m *SessionManager) scheduleLocked() {
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	delay := time.Until(time.Unix(m.session.ExpiresAt, 0)) - sessionRefreshLeeway
+	if delay < 0 {
+		delay = 0
+	}
+	m.timer = time.AfterFunc(delay, func() { m.Refresh() })
+}
+
+func (m *SessionManager) retryLocked() {
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.timer = time.AfterFunc(sessionRetryDelay, func() { m.Refresh() })
+}
+
+func (m *SessionManager) expireLocked(reason string) {
+	userId := m.session.UserID
+	m.clearLocked()
+	m.emit("session-expired", map[string]interface{}{
+		"userId": userId,
+		"reason": reason,
+	})
+}
+
+func (m *SessionManager) clearLocked() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	m.session = nil
+	os.Remove(m.path)
+}
+
+func (m *SessionManager) persistLocked() error {
+	plain, err := json.Marshal(m.session)
+	if err != nil {
+		return fmt.Errorf("error encoding session: %v", err)
+	}
+	sealed, err := protectSecret(plain)
+	if err != nil {
+		return fmt.Errorf("error encrypting session: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(m.path), err)
+	}
+	return writeFileAtomic(m.path, sealed, 0600, 0)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestSessionManager crea un gestor contra un GoTrue falso cuyo refresco espera a release
+func newTestSessionManager(t *testing.T, release <-chan struct{}) (*SessionManager, *int32) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // Clave de cifrado de la sesión
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/v1/token" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.Write([]byte(`{"access_token":"new","refresh_token":"rt2","expires_in":3600,"user":{"id":"6f1c7d1e-0000-4000-8000-000000000001"}}`))
+	}))
+	t.Cleanup(server.Close)
+	auth := NewAuthService(server.URL, "anon", server.Client())
+	m := NewSessionManager(auth, filepath.Join(t.TempDir(), SessionFileName), func(string, interface{}) {})
+	session := &AuthSession{AccessToken: "old", RefreshToken: "rt1", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+	if err := m.Start(session); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		m.mu.Lock()
+		if m.timer != nil {
+			m.timer.Stop()
+		}
+		m.mu.Unlock()
+	})
+	return m, &calls
+}
+
+func TestSessionRefreshDoesNotHoldLock(t *testing.T) {
+	release := make(chan struct{})
+	m, calls := newTestSessionManager(t, release)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	refresh := func(i int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = m.Refresh()
+		}()
+	}
+	refresh(0)
+	waitFor(t, "refresh request", func() bool { return atomic.LoadInt32(calls) == 1 })
+	refresh(1) // Llega con el primero en curso y debe esperarlo
+	time.Sleep(50 * time.Millisecond)
+
+	// Con el refresco esperando a la red, la sesión se sigue pudiendo leer
+	current := make(chan *AuthSession)
+	go func() { current <- m.Current() }()
+	select {
+	case s := <-current:
+		if s.AccessToken != "old" {
+			t.Errorf("access token during refresh = %q", s.AccessToken)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Current blocked while refreshing")
+	}
+
+	close(release)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Refresh %d: %v", i, err)
+		}
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Errorf("refresh token used %d times, want 1", n)
+	}
+	if s := m.Current(); s.AccessToken != "new" || s.RefreshToken != "rt2" {
+		t.Errorf("session after refresh = %+v", s)
+	}
+}
+
+// Un logout durante el refresco gana: la sesión refrescada no vuelve
+func TestSessionLogoutDuringRefresh(t *testing.T) {
+	release := make(chan struct{})
+	m, calls := newTestSessionManager(t, release)
+
+	done := make(chan error)
+	go func() { done <- m.Refresh() }()
+	waitFor(t, "refresh request", func() bool { return atomic.LoadInt32(calls) == 1 })
+
+	m.mu.Lock()
+	m.clearLocked()
+	m.mu.Unlock()
+	close(release)
+	if err := <-done; err != ErrNoSession {
+		t.Errorf("Refresh = %v, want ErrNoSession", err)
+	}
+	if s := m.Current(); s != nil {
+		t.Errorf("session came back after logout: %+v", s)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Settings son las preferencias del usuario, guardadas en settings.json
+type Settings struct {
+	BackupRetention int             `json:"backupRetention"` // Snapshots de LoLModInstaller a conservar
+	Backend         BackendSettings `json:"backend"`         // Proyecto y almacenamiento del catálogo
+	// AutoApplyOnLockIn arma el overlay con la skin del campeón al confirmarlo en la selección
+	AutoApplyOnLockIn bool `json:"autoApplyOnLockIn"`
+	// Overlay decide cuándo se arranca, protege y detiene el overlay según la fase de la partida
+	Overlay OverlayPolicy `json:"overlay"`
+	// HideOwnedSkins oculta del catálogo las skins que el jugador ya tiene en su cuenta
+	HideOwnedSkins bool `json:"hideOwnedSkins"`
+	// Roulette configura el sorteo de skins ("sorpréndeme")
+	Roulette RouletteSettings `json:"roulette"`
+	// ControlAPI expone una API local (HTTP/WebSocket) para controlar la app desde otras herramientas
+	ControlAPI ControlAPISettings `json:"controlApi"`
+	// Language es el idioma de los mensajes (en, es-ES, es-419); vacío usa el del sistema
+	Language string `json:"language"`
+	// Logging configura el registro JSON en resources/logs: niveles por subsistema y rotación
+	Logging LogSettings `json:"logging"`
+}
+
+// defaultSettings devuelve la configuración usada cuando settings.json no existe
+func defaultSettings() Settings {
+	return Settings{
+		BackupRetention:   10,
+		Backend:           defaultBackendSettings(),
+		AutoApplyOnLockIn: true,
+		Overlay:           defaultOverlayPolicy(),
+		Roulette:          defaultRouletteSettings(),
+		ControlAPI:        ControlAPISettings{Port: DefaultControlAPIPort},
+		Logging:           defaultLogSettings(),
+	}
+}
+
+// normalize corrige valores fuera de rango para que el resto de la app no tenga que validarlos
+func (s *Settings) normalize() {
+	if s.BackupRetention < 1 {
+		s.BackupRetention = 1
+	}
+	s.Backend.normalize()
+	s.Overlay.normalize()
+	s.Roulette.normalize()
+	s.ControlAPI.normalize()
+	s.Logging.normalize()
+	if locale, ok := matchLocale(s.Language); ok {
+		s.Language = string(locale)
+	} else {
+		s.Language = ""
+	}
+}
+
+// loadSettings lee settings.json (o su copia de seguridad) sobre los valores por defecto
+func (a *App) loadSettings() {
+	settings := defaultSettings()
+	data, source, err := readFileWithBackups(absSettingsPath, MetadataBackupCount, validateJSON)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			a.logger(LogSubsystemStorage).Warningf("Could not read %s, using defaults: %v", absSettingsPath, err)
+		}
+		a.setSettings(settings)
+		setLocale(resolveLocale(settings.Language))
+		a.applyLogSettings()
+		return
+	}
+	if source != absSettingsPath {
+		a.logger(LogSubsystemStorage).Warningf("%s is missing or corrupt, using backup %s", absSettingsPath, source)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		a.logger(LogSubsystemStorage).Warningf("Invalid settings in %s, using defaults: %v", source, err)
+		settings = defaultSettings()
+	}
+	settings.normalize()
+	a.setSettings(settings)
+	setLocale(resolveLocale(settings.Language))
+	a.applyLogSettings()
+}
+
+// reloadSettings vuelve a leer settings.json (p. ej. tras restaurar un snapshot) y
+// aplica lo que no se lee en cada uso: idioma, registro y la API de control
+func (a *App) reloadSettings() {
+	previous := a.currentSettings()
+	a.loadSettings() // También aplica idioma y registro
+	if a.currentSettings().ControlAPI != previous.ControlAPI && !a.headless {
+		a.applyControlAPI()
+	}
+}
+
+// currentSettings devuelve una copia de la configuración actual. La leen a la
+// vez el frontend, el watcher del LCU, la API de control y el registro.
+func (a *App) currentSettings() Settings {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.settings
+}
+
+// setSettings reemplaza la configuración en memoria; no la guarda
+func (a *App) setSettings(settings Settings) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.settings = settings
+}
+
+// saveSettings guarda la configuración actual en settings.json
+func (a *App) saveSettings() error {
+	data, err := json.MarshalIndent(a.currentSettings(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling settings: %v", err)
+	}
+	if err := writeFileAtomic(absSettingsPath, data, 0644, MetadataBackupCount); err != nil {
+		return fmt.Errorf("error writing %s: %v", absSettingsPath, err)
+	}
+	return nil
+}
+
+// GetSettings devuelve la configuración actual
+func (a *App) GetSettings() Settings {
+	return a.currentSettings()
+}
+
+// SettingsResult es la respuesta de UpdateSettings
+type SettingsResult struct {
+	Result
+	Settings        *Settings `json:"settings,omitempty"`
+	RestartRequired bool      `json:"restartRequired,omitempty"`
+}
+
+// UpdateSettings reemplaza la configuración y la guarda. Los cambios de Backend
+// se aplican al reiniciar ("restartRequired"), porque la sesión depende del proyecto.
+// Los de la API de control se aplican enseguida.
+func (a *App) UpdateSettings(settings Settings) SettingsResult {
+	settings.normalize()
+	if settings.ControlAPI.Enabled && settings.ControlAPI.Token == "" {
+		settings.ControlAPI.Token = newControlToken()
+	}
+	if _, err := newObjectStore(settings.Backend, nil); err != nil {
+		return SettingsResult{Result: failResult(ErrInvalidArgument, "Invalid backend settings", err)}
+	}
+	previous := a.currentSettings()
+	a.setSettings(settings)
+	if err := a.saveSettings(); err != nil {
+		a.setSettings(previous)
+		a.logger(LogSubsystemStorage).Error(fmt.Sprintf("UpdateSettings: %v", err))
+		return SettingsResult{Result: failResult(ErrStorageFailed, "Could not save settings", err)}
+	}
+	if previous.ControlAPI != settings.ControlAPI && !a.headless {
+		a.applyControlAPI()
+	}
+	setLocale(resolveLocale(settings.Language))
+	a.applyLogSettings()
+	return SettingsResult{
+		Result:          okResult(""),
+		Settings:        &settings,
+		RestartRequired: previous.Backend != settings.Backend,
+	}
+}
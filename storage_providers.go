@@ -0,0 +1,280 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Proveedores de almacenamiento para el catálogo (.fantome y JSON de campeones)
+const (
+	StorageSupabase = "supabase" // Supabase Storage (por defecto)
+	StorageS3       = "s3"       // Cualquier endpoint compatible con S3 (MinIO, R2, B2...)
+	StorageHTTP     = "http"     // Espejo HTTP estático: <baseURL>/<bucket>/<ruta>
+	StorageLocal    = "local"    // Carpeta local: <dir>/<bucket>/<ruta>
+)
+
+// BackendSettings configura el proyecto de Supabase (cuentas y fichas) y de dónde
+// se descargan los archivos del catálogo. Cambiarlo requiere reiniciar la app.
+type BackendSettings struct {
+	SupabaseURL        string     `json:"supabaseUrl"`
+	SupabaseAnonKey    string     `json:"supabaseAnonKey"`
+	StorageProvider    string     `json:"storageProvider"`
+	SkinsBucket        string     `json:"skinsBucket"`
+	ChampionJSONBucket string     `json:"championJsonBucket"`
+	S3                 S3Settings `json:"s3"`
+	HTTPBaseURL        string     `json:"httpBaseUrl"`
+	LocalDir           string     `json:"localDir"`
+}
+
+// S3Settings son los datos de un endpoint compatible con S3. Sin claves las
+// descargas van sin firmar (buckets públicos).
+type S3Settings struct {
+	Endpoint        string `json:"endpoint"` // p. ej. http://127.0.0.1:9000
+	Region          string `json:"region"`
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	PathStyle       bool   `json:"pathStyle"` // <endpoint>/<bucket>/<clave> en vez de <bucket>.<endpoint>
+}
+
+// defaultBackendSettings apunta al proyecto oficial
+func defaultBackendSettings() BackendSettings {
+	return BackendSettings{
+		SupabaseURL:        SupabaseURL,
+		SupabaseAnonKey:    SupabaseKey,
+		StorageProvider:    StorageSupabase,
+		SkinsBucket:        CatalogSkinsBucket,
+		ChampionJSONBucket: ChampionJSONBucket,
+		S3:                 S3Settings{Region: "us-east-1", PathStyle: true},
+	}
+}
+
+// normalize completa los valores vacíos con los de defaultBackendSettings
+func (b *BackendSettings) normalize() {
+	defaults := defaultBackendSettings()
+	b.SupabaseURL = strings.TrimRight(strings.TrimSpace(b.SupabaseURL), "/")
+	if b.SupabaseURL == "" {
+		b.SupabaseURL = defaults.SupabaseURL
+	}
+	if b.SupabaseAnonKey == "" {
+		b.SupabaseAnonKey = defaults.SupabaseAnonKey
+	}
+	switch b.StorageProvider {
+	case StorageSupabase, StorageS3, StorageHTTP, StorageLocal:
+	default:
+		b.StorageProvider = defaults.StorageProvider
+	}
+	if b.SkinsBucket == "" {
+		b.SkinsBucket = defaults.SkinsBucket
+	}
+	if b.ChampionJSONBucket == "" {
+		b.ChampionJSONBucket = defaults.ChampionJSONBucket
+	}
+	if b.S3.Region == "" {
+		b.S3.Region = defaults.S3.Region
+	}
+}
+
+// newObjectStore crea el proveedor elegido en settings
+func newObjectStore(settings BackendSettings, httpClient *http.Client) (ObjectStore, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 60 * time.Second}
+	}
+	switch settings.StorageProvider {
+	case StorageSupabase:
+		return supabaseStorage{NewSupabaseBackend(settings.SupabaseURL, settings.SupabaseAnonKey, httpClient)}, nil
+	case StorageS3:
+		if settings.S3.Endpoint == "" {
+			return nil, fmt.Errorf("S3 storage requires an endpoint")
+		}
+		return NewS3Store(settings.S3, httpClient)
+	case StorageHTTP:
+		if settings.HTTPBaseURL == "" {
+			return nil, fmt.Errorf("HTTP storage requires a base URL")
+		}
+		return NewHTTPMirrorStore(settings.HTTPBaseURL, httpClient), nil
+	case StorageLocal:
+		if settings.LocalDir == "" {
+			return nil, fmt.Errorf("local storage requires a directory")
+		}
+		return NewLocalDirStore(settings.LocalDir), nil
+	}
+	return nil, fmt.Errorf("unknown storage provider %q", settings.StorageProvider)
+}
+
+// LocalDirStore lee los objetos de <root>/<bucket>/<ruta>
+type LocalDirStore struct {
+	root string
+}
+
+// NewLocalDirStore crea el proveedor sobre root
+func NewLocalDirStore(root string) *LocalDirStore {
+	return &LocalDirStore{root: root}
+}
+
+func (s *LocalDirStore) Download(bucket, objectPath string) ([]byte, error) {
+	root, err := filepath.Abs(s.root)
+	if err != nil {
+		return nil, err
+	}
+	full := filepath.Join(root, filepath.FromSlash(bucket), filepath.FromSlash(objectPath))
+	if rel, err := filepath.Rel(root, full); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("invalid object path %s/%s", bucket, objectPath)
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading file: %v", err)
+	}
+	return data, nil
+}
+
+// HTTPMirrorStore descarga de un servidor estático con la misma estructura de carpetas
+type HTTPMirrorStore struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHTTPMirrorStore crea el proveedor para baseURL
+func NewHTTPMirrorStore(baseURL string, httpClient *http.Client) *HTTPMirrorStore {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &HTTPMirrorStore{baseURL: strings.TrimRight(baseURL, "/"), httpClient: httpClient}
+}
+
+func (s *HTTPMirrorStore) Download(bucket, objectPath string) ([]byte, error) {
+	resp, err := s.httpClient.Get(s.baseURL + "/" + escapeObjectPath(bucket) + "/" + escapeObjectPath(objectPath))
+	if err != nil {
+		return nil, fmt.Errorf("error downloading file: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading file: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// S3Store descarga de un endpoint compatible con S3, firmando con AWS Signature V4
+type S3Store struct {
+	endpoint   *url.URL
+	settings   S3Settings
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Store crea el proveedor para settings.Endpoint
+func NewS3Store(settings S3Settings, httpClient *http.Client) (*S3Store, error) {
+	endpoint, err := url.Parse(strings.TrimRight(settings.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", settings.Endpoint)
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &S3Store{endpoint: endpoint, settings: settings, httpClient: httpClient, now: time.Now}, nil
+}
+
+func (s *S3Store) Download(bucket, objectPath string) ([]byte, error) {
+	host := s.endpoint.Host
+	uri := s.endpoint.Path + "/" + awsURIEscape(objectPath)
+	if s.settings.PathStyle {
+		uri = s.endpoint.Path + "/" + awsURIEscape(bucket) + "/" + awsURIEscape(objectPath)
+	} else {
+		host = bucket + "." + host
+	}
+	req, err := http.NewRequest("GET", s.endpoint.Scheme+"://"+host+uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	if s.settings.AccessKeyID != "" {
+		s.sign(req, host, uri)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading file: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading file: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// emptyPayloadHash es el SHA-256 de un cuerpo vacío, usado en los GET firmados
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign agrega los encabezados de AWS Signature V4 a un GET sin query. Se firman
+// host, x-amz-content-sha256, x-amz-date y cualquier otro encabezado ya puesto en req.
+func (s *S3Store) sign(req *http.Request, host, uri string) {
+	t := s.now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	scope := date + "/" + s.settings.Region + "/s3/aws4_request"
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", emptyPayloadHash)
+	canonicalRequest, signedHeaders := s3CanonicalRequest(req, host, uri)
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.settings.SecretAccessKey), date)
+	key = hmacSHA256(key, s.settings.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.settings.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// s3CanonicalRequest arma la petición canónica de SigV4 para un GET sin query ni
+// cuerpo, con los encabezados de req ordenados y en minúsculas
+func s3CanonicalRequest(req *http.Request, host, uri string) (canonical, signedHeaders string) {
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders = strings.Join(names, ";")
+	canonical = strings.Join([]string{"GET", uri, "", canonicalHeaders.String(), signedHeaders, emptyPayloadHash}, "\n")
+	return canonical, signedHeaders
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsURIEscape codifica una ruta como pide SigV4: todo salvo A-Z a-z 0-9 - . _ ~ y '/'
+func awsURIEscape(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+// sign-in-with-login inicia sesión con el nombre de usuario (ver SignInWithLogin en auth.go).
+//
+// El email de la cuenta se busca aquí con la clave de servicio y nunca se devuelve:
+// la respuesta es la de POST /auth/v1/token?grant_type=password tal cual, y un login
+// que no existe se prueba contra un email imposible para que responda igual y tarde
+// lo mismo que una contraseña incorrecta.
+import { createClient } from "jsr:@supabase/supabase-js@2";
+
+const supabaseURL = Deno.env.get("SUPABASE_URL")!;
+const anonKey = Deno.env.get("SUPABASE_ANON_KEY")!;
+const admin = createClient(supabaseURL, Deno.env.get("SUPABASE_SERVICE_ROLE_KEY")!, {
+  auth: { persistSession: false, autoRefreshToken: false },
+});
+
+// Dominio reservado (RFC 2606): ninguna cuenta puede tener este email
+const UNKNOWN_EMAIL = "unknown-login@example.invalid";
+
+async function emailForLogin(login: string): Promise<string> {
+  // ilike sin comodines compara sin distinguir mayúsculas, como users_login_lower_key
+  const pattern = login.replace(/[\\%_]/g, "\\$&");
+  const { data: user, error } = await admin.from("users").select("id").ilike("login", pattern).maybeSingle();
+  if (error || !user) {
+    return UNKNOWN_EMAIL;
+  }
+  const { data } = await admin.auth.admin.getUserById(user.id);
+  return data?.user?.email ?? UNKNOWN_EMAIL;
+}
+
+Deno.serve(async (req) => {
+  if (req.method !== "POST") {
+    return new Response("Method not allowed", { status: 405 });
+  }
+  let login = "";
+  let password = "";
+  try {
+    const body = await req.json();
+    login = typeof body?.login === "string" ? body.login.trim() : "";
+    password = typeof body?.password === "string" ? body.password : "";
+  } catch {
+    // Cuerpo inválido: mismo error que unas credenciales incorrectas
+  }
+  if (login === "" || password === "") {
+    return Response.json(
+      { error: "invalid_grant", error_code: "invalid_credentials", error_description: "Invalid login credentials" },
+      { status: 400 },
+    );
+  }
+
+  // GoTrue limita los intentos por IP; se le pasa la del cliente, no la de la función
+  const headers: Record<string, string> = { apikey: anonKey, "Content-Type": "application/json" };
+  const forwardedFor = req.headers.get("x-forwarded-for");
+  if (forwardedFor) {
+    headers["X-Forwarded-For"] = forwardedFor;
+  }
+  const resp = await fetch(`${supabaseURL}/auth/v1/token?grant_type=password`, {
+    method: "POST",
+    headers,
+    body: JSON.stringify({ email: await emailForLogin(login), password }),
+  });
+  return new Response(await resp.text(), {
+    status: resp.status,
+    headers: { "Content-Type": "application/json" },
+  });
+});
@@ -0,0 +1,68 @@
+-- public.users pasa a depender de Supabase Auth (ver auth.go).
+--
+-- La contraseña ya no se guarda ni se compara aquí: GoTrue la verifica y la fila
+-- la crea el trigger de abajo con el login que Register guarda en user_metadata.
+-- La app solo ve su propia fila y solo las columnas de UserProfile.
+
+alter table public.users enable row level security;
+
+revoke all on public.users from anon, authenticated;
+grant select (id, login, fichasporskin, escomprador) on public.users to authenticated;
+grant update (login) on public.users to authenticated;
+
+drop policy if exists "users_select_own" on public.users;
+create policy "users_select_own" on public.users
+  for select to authenticated
+  using (id = auth.uid());
+
+drop policy if exists "users_update_own" on public.users;
+create policy "users_update_own" on public.users
+  for update to authenticated
+  using (id = auth.uid())
+  with check (id = auth.uid());
+
+create unique index if not exists users_login_lower_key on public.users (lower(login));
+
+-- handle_new_user crea la fila de public.users de cada cuenta nueva de auth.users
+create or replace function public.handle_new_user()
+returns trigger
+language plpgsql
+security definer
+set search_path = public
+as $$
+begin
+  insert into public.users (id, email, login, fichasporskin, escomprador)
+  values (new.id, new.email, nullif(trim(new.raw_user_meta_data ->> 'login'), ''), 0, false);
+  return new;
+end;
+$$;
+
+revoke all on function public.handle_new_user() from public, anon, authenticated;
+
+drop trigger if exists on_auth_user_created on auth.users;
+create trigger on_auth_user_created
+  after insert on auth.users
+  for each row execute function public.handle_new_user();
+
+-- El email de una cuenta nunca sale de la base: la función sign-in-with-login
+-- (supabase/functions) lo resuelve con la clave de servicio e inicia la sesión
+drop function if exists public.email_for_login(text);
+
+-- login_taken indica si login pertenece a otra cuenta que la de quien llama. Es lo
+-- único que se puede saber de un login sin iniciar sesión.
+create or replace function public.login_taken(p_login text)
+returns boolean
+language sql
+stable
+security definer
+set search_path = public
+as $$
+  select exists (
+    select 1 from public.users
+    where lower(login) = lower(trim(p_login))
+      and id is distinct from auth.uid()
+  );
+$$;
+
+revoke all on function public.login_taken(text) from public;
+grant execute on function public.login_taken(text) to anon, authenticated;
@@ -0,0 +1,90 @@
+-- Acceso a las skins decidido en el servidor (ver entitlements.go).
+--
+-- El bucket "campeones" deja de ser público: la app pide authorize_skin_download
+-- y, si está permitido, firma una URL de vida corta con el token del usuario.
+-- Firmar exige poder leer el objeto, así que la política de storage.objects
+-- repite la misma comprobación y un cliente modificado no puede saltársela.
+
+alter table public.users
+  add column if not exists baneado boolean not null default false;
+
+-- Desde la app solo se puede cambiar el login; escomprador, baneado y
+-- fichasporskin los cambian el servidor y las funciones de esta carpeta
+revoke insert, update, delete on public.users from anon, authenticated;
+grant update (login) on public.users to authenticated;
+
+-- skin_download_denial devuelve el motivo (los valores de DenialReason) por el que
+-- el usuario autenticado no puede descargar la skin, o null si puede. Solo mira la
+-- fila de quien llama, así que se puede usar desde las políticas sin exponer a otros.
+create or replace function public.skin_download_denial(p_champion_id text, p_skin_num text)
+returns text
+language plpgsql
+stable
+security definer
+set search_path = public
+as $$
+declare
+  v_uid uuid := auth.uid();
+  v_user public.users%rowtype;
+begin
+  if v_uid is null or coalesce(p_champion_id, '') = '' or coalesce(p_skin_num, '') = '' then
+    return 'not_purchased';
+  end if;
+  select * into v_user from public.users where id = v_uid;
+  if not found then
+    return 'not_purchased';
+  end if;
+  if v_user.baneado then
+    return 'banned';
+  end if;
+  if not exists (select 1 from auth.users where id = v_uid and email_confirmed_at is not null) then
+    return 'email_unverified';
+  end if;
+  if not coalesce(v_user.escomprador, false) then
+    return 'not_purchased';
+  end if;
+  return null;
+end;
+$$;
+
+revoke all on function public.skin_download_denial(text, text) from public, anon;
+grant execute on function public.skin_download_denial(text, text) to authenticated;
+
+-- authorize_skin_download es la RPC que llama SupabaseEntitlements.AuthorizeSkinDownload
+create or replace function public.authorize_skin_download(p_champion_id text, p_skin_num text)
+returns jsonb
+language plpgsql
+stable
+security definer
+set search_path = public
+as $$
+declare
+  v_reason text;
+begin
+  if auth.uid() is null then
+    raise exception 'not authenticated' using errcode = '28000';
+  end if;
+  v_reason := public.skin_download_denial(p_champion_id, p_skin_num);
+  if v_reason is not null then
+    return jsonb_build_object('allowed', false, 'reason', v_reason);
+  end if;
+  return jsonb_build_object('allowed', true);
+end;
+$$;
+
+revoke all on function public.authorize_skin_download(text, text) from public, anon;
+grant execute on function public.authorize_skin_download(text, text) to authenticated;
+
+-- Las rutas son campeones/<campeón>/<skin>.fantome dentro del bucket (catalogSkinPath)
+update storage.buckets set public = false where id = 'campeones';
+
+drop policy if exists "campeones_select_entitled" on storage.objects;
+create policy "campeones_select_entitled" on storage.objects
+  for select to authenticated
+  using (
+    bucket_id = 'campeones'
+    and public.skin_download_denial(
+      split_part(name, '/', 2),
+      regexp_replace(split_part(name, '/', 3), '\.fantome$', '')
+    ) is null
+  );
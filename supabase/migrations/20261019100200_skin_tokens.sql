@@ -0,0 +1,259 @@
+-- Gasto de fichas por skin ("fichasporskin") en tres pasos (ver tokens.go):
+-- reserve_skin_token descuenta una ficha y crea una reserva, authorize_skin_download
+-- la confirma en la misma transacción que autoriza la descarga y refund_skin_token
+-- la devuelve si nunca llegó a autorizarse. Una reserva autorizada ya dio acceso a
+-- la URL firmada, así que no se puede devolver.
+-- La clave de idempotencia es única por usuario, así que reintentar una reserva
+-- devuelve la misma y nunca cobra dos veces.
+
+create table if not exists public.skin_token_reservations (
+  id uuid primary key default gen_random_uuid(),
+  user_id uuid not null references auth.users (id) on delete cascade,
+  idempotency_key text not null,
+  champion_id text not null,
+  skin_num text not null,
+  status text not null default 'reserved' check (status in ('reserved', 'committed', 'refunded')),
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now(),
+  unique (user_id, idempotency_key)
+);
+
+create index if not exists skin_token_reservations_skin_idx
+  on public.skin_token_reservations (user_id, champion_id, skin_num, status);
+
+-- Cada usuario ve sus reservas; solo las funciones de abajo las crean o cambian
+alter table public.skin_token_reservations enable row level security;
+revoke all on public.skin_token_reservations from anon, authenticated;
+grant select on public.skin_token_reservations to authenticated;
+
+drop policy if exists "skin_token_reservations_select_own" on public.skin_token_reservations;
+create policy "skin_token_reservations_select_own" on public.skin_token_reservations
+  for select to authenticated
+  using (user_id = auth.uid());
+
+create or replace function public.reserve_skin_token(p_idempotency_key text, p_champion_id text, p_skin_num text)
+returns jsonb
+language plpgsql
+security definer
+set search_path = public
+as $$
+declare
+  v_uid uuid := auth.uid();
+  v_user public.users%rowtype;
+  v_reservation public.skin_token_reservations%rowtype;
+begin
+  if v_uid is null then
+    raise exception 'not authenticated' using errcode = '28000';
+  end if;
+  if coalesce(p_idempotency_key, '') = '' or coalesce(p_champion_id, '') = '' or coalesce(p_skin_num, '') = '' then
+    raise exception 'idempotency key, champion and skin are required' using errcode = '22023';
+  end if;
+
+  -- La fila del usuario se bloquea para que dos reservas a la vez no gasten la misma ficha
+  select * into v_user from public.users where id = v_uid for update;
+  if not found then
+    return jsonb_build_object('allowed', false, 'reason', 'not_purchased');
+  end if;
+  if v_user.baneado then
+    return jsonb_build_object('allowed', false, 'reason', 'banned');
+  end if;
+
+  -- Reintento con la misma clave, o una skin que ya pagó: no se cobra otra ficha
+  select * into v_reservation from public.skin_token_reservations
+    where user_id = v_uid
+      and (idempotency_key = p_idempotency_key
+        or (champion_id = p_champion_id and skin_num = p_skin_num and status = 'committed'))
+    order by (status = 'committed') desc, created_at desc
+    limit 1;
+  if found and v_reservation.status <> 'refunded' then
+    return jsonb_build_object('allowed', true, 'reservation_id', v_reservation.id, 'remaining', v_user.fichasporskin);
+  end if;
+  if found and v_reservation.idempotency_key = p_idempotency_key then
+    raise exception 'reservation % was already refunded', v_reservation.id using errcode = '22023';
+  end if;
+
+  if coalesce(v_user.fichasporskin, 0) <= 0 then
+    return jsonb_build_object('allowed', false, 'reason', 'out_of_tokens', 'remaining', 0);
+  end if;
+  update public.users set fichasporskin = fichasporskin - 1 where id = v_uid
+    returning * into v_user;
+  insert into public.skin_token_reservations (user_id, idempotency_key, champion_id, skin_num)
+    values (v_uid, p_idempotency_key, p_champion_id, p_skin_num)
+    returning * into v_reservation;
+  return jsonb_build_object('allowed', true, 'reservation_id', v_reservation.id, 'remaining', v_user.fichasporskin);
+end;
+$$;
+
+-- commit_skin_token confirma una reserva propia; confirmar dos veces no hace nada
+create or replace function public.commit_skin_token(p_reservation_id uuid)
+returns void
+language plpgsql
+security definer
+set search_path = public
+as $$
+declare
+  v_status text;
+begin
+  if auth.uid() is null then
+    raise exception 'not authenticated' using errcode = '28000';
+  end if;
+  select status into v_status from public.skin_token_reservations
+    where id = p_reservation_id and user_id = auth.uid()
+    for update;
+  if not found then
+    raise exception 'reservation % not found', p_reservation_id using errcode = 'P0002';
+  end if;
+  if v_status = 'refunded' then
+    raise exception 'reservation % was already refunded', p_reservation_id using errcode = '22023';
+  end if;
+  update public.skin_token_reservations set status = 'committed', updated_at = now()
+    where id = p_reservation_id and status = 'reserved';
+end;
+$$;
+
+-- refund_skin_token devuelve la ficha de una reserva propia que sigue pendiente.
+-- Devolver otra vez no hace nada; una reserva confirmada ya se usó para
+-- autorizar la descarga y se rechaza con 55000 (ver ErrReservationConsumed).
+create or replace function public.refund_skin_token(p_reservation_id uuid)
+returns void
+language plpgsql
+security definer
+set search_path = public
+as $$
+declare
+  v_status text;
+begin
+  if auth.uid() is null then
+    raise exception 'not authenticated' using errcode = '28000';
+  end if;
+  select status into v_status from public.skin_token_reservations
+    where id = p_reservation_id and user_id = auth.uid()
+    for update;
+  if not found then
+    raise exception 'reservation % not found', p_reservation_id using errcode = 'P0002';
+  end if;
+  if v_status = 'committed' then
+    raise exception 'reservation % was already used', p_reservation_id using errcode = '55000';
+  end if;
+  if v_status = 'reserved' then
+    update public.skin_token_reservations set status = 'refunded', updated_at = now()
+      where id = p_reservation_id;
+    update public.users set fichasporskin = fichasporskin + 1 where id = auth.uid();
+  end if;
+end;
+$$;
+
+revoke all on function public.reserve_skin_token(text, text, text) from public, anon;
+revoke all on function public.commit_skin_token(uuid) from public, anon;
+revoke all on function public.refund_skin_token(uuid) from public, anon;
+grant execute on function public.reserve_skin_token(text, text, text) to authenticated;
+grant execute on function public.commit_skin_token(uuid) to authenticated;
+grant execute on function public.refund_skin_token(uuid) to authenticated;
+
+-- skin_account_denial devuelve el motivo por el que la cuenta que llama no puede
+-- descargar ninguna skin, o null. Son las comprobaciones de cuenta de skin_download_denial.
+create or replace function public.skin_account_denial()
+returns text
+language plpgsql
+stable
+security definer
+set search_path = public
+as $$
+declare
+  v_uid uuid := auth.uid();
+  v_user public.users%rowtype;
+begin
+  if v_uid is null then
+    return 'not_purchased';
+  end if;
+  select * into v_user from public.users where id = v_uid;
+  if not found then
+    return 'not_purchased';
+  end if;
+  if v_user.baneado then
+    return 'banned';
+  end if;
+  if not exists (select 1 from auth.users where id = v_uid and email_confirmed_at is not null) then
+    return 'email_unverified';
+  end if;
+  if not coalesce(v_user.escomprador, false) then
+    return 'not_purchased';
+  end if;
+  return null;
+end;
+$$;
+
+revoke all on function public.skin_account_denial() from public, anon, authenticated;
+
+-- Descargar ahora también exige una ficha confirmada en esa skin. Solo
+-- authorize_skin_download confirma reservas, así que la política del bucket no
+-- firma una URL para una reserva que todavía se puede devolver.
+create or replace function public.skin_download_denial(p_champion_id text, p_skin_num text)
+returns text
+language plpgsql
+stable
+security definer
+set search_path = public
+as $$
+declare
+  v_reason text;
+begin
+  if coalesce(p_champion_id, '') = '' or coalesce(p_skin_num, '') = '' then
+    return 'not_purchased';
+  end if;
+  v_reason := public.skin_account_denial();
+  if v_reason is not null then
+    return v_reason;
+  end if;
+  if not exists (
+    select 1 from public.skin_token_reservations
+    where user_id = auth.uid() and champion_id = p_champion_id and skin_num = p_skin_num
+      and status = 'committed'
+  ) then
+    return 'out_of_tokens';
+  end if;
+  return null;
+end;
+$$;
+
+-- authorize_skin_download confirma la reserva pendiente de la skin antes de
+-- permitir la descarga. Ya no es stable: cambia la reserva en la misma
+-- transacción, así que la ficha no se puede devolver después de recibir la URL.
+create or replace function public.authorize_skin_download(p_champion_id text, p_skin_num text)
+returns jsonb
+language plpgsql
+volatile
+security definer
+set search_path = public
+as $$
+declare
+  v_reason text;
+  v_reservation public.skin_token_reservations%rowtype;
+begin
+  if auth.uid() is null then
+    raise exception 'not authenticated' using errcode = '28000';
+  end if;
+  if coalesce(p_champion_id, '') = '' or coalesce(p_skin_num, '') = '' then
+    return jsonb_build_object('allowed', false, 'reason', 'not_purchased');
+  end if;
+  v_reason := public.skin_account_denial();
+  if v_reason is not null then
+    return jsonb_build_object('allowed', false, 'reason', v_reason);
+  end if;
+
+  select * into v_reservation from public.skin_token_reservations
+    where user_id = auth.uid() and champion_id = p_champion_id and skin_num = p_skin_num
+      and status in ('reserved', 'committed')
+    order by (status = 'committed') desc, created_at desc
+    limit 1
+    for update;
+  if not found then
+    return jsonb_build_object('allowed', false, 'reason', 'out_of_tokens');
+  end if;
+  if v_reservation.status = 'reserved' then
+    update public.skin_token_reservations set status = 'committed', updated_at = now()
+      where id = v_reservation.id;
+  end if;
+  return jsonb_build_object('allowed', true, 'reservation_id', v_reservation.id);
+end;
+$$;
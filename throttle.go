@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Límites de intentos de login. Cada cuenta tiene su propio contador y además hay
+// uno para toda la instalación, para frenar a quien prueba muchos logins distintos.
+const (
+	loginFreeAttempts       = 3                // Fallos permitidos antes de empezar a esperar
+	loginBackoffBase        = time.Second      // Espera tras el primer fallo fuera de los libres
+	loginBackoffMax         = 5 * time.Minute  // Tope de la espera exponencial
+	loginLockoutThreshold   = 10               // Fallos seguidos de un login que lo bloquean
+	loginLockoutDuration    = 15 * time.Minute // Duración del bloqueo
+	installFreeAttempts     = 10
+	installLockoutThreshold = 30
+	loginFailureWindow      = time.Hour // Fallos más viejos que esto se olvidan
+	loginAuditMaxEntries    = 500
+)
+
+// Motivos del registro de auditoría
+const (
+	LoginAuditFailed    = "failed"    // Credenciales rechazadas por el servidor
+	LoginAuditThrottled = "throttled" // Intento antes de terminar la espera
+	LoginAuditLocked    = "locked"    // Intento con el login o la instalación bloqueados
+)
+
+// LoginAuditEntry es un intento de login fallido o rechazado
+type LoginAuditEntry struct {
+	Time   string `json:"time"`
+	Login  string `json:"login"`
+	Reason string `json:"reason"`
+}
+
+// loginAttempts es el contador de fallos de una clave
+type loginAttempts struct {
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"lastFailure"`
+	LockedUntil time.Time `json:"lockedUntil,omitempty"`
+}
+
+// LoginThrottle limita los intentos de login con espera exponencial y bloqueo
+// temporal. El estado se guarda en disco para que reiniciar la app no lo borre.
+//
+// Los fallos cuentan por cuenta: el nombre de usuario y el email de una cuenta que
+// ya inició sesión en esta instalación (LinkAccount) comparten contador, así que
+// alternarlos no duplica los intentos. Para una cuenta que nunca entró desde aquí
+// el servidor no dice a quién pertenece un login, y cada identificador cuenta
+// aparte; el contador de la instalación sigue limitando el total.
+//
+// Es solo una defensa del cliente: frena a quien prueba contraseñas desde la app,
+// pero quien llama a Supabase Auth directamente no pasa por aquí. El límite que
+// vale contra eso es el del servidor (rate limits de GoTrue, a los que
+// sign-in-with-login pasa la IP del cliente).
+type LoginThrottle struct {
+	path string
+	now  func() time.Time
+
+	mu       sync.Mutex
+	Attempts map[string]*loginAttempts `json:"attempts"`
+	Accounts map[string]string         `json:"accounts,omitempty"` // Login o email normalizado -> ID de la cuenta
+	Audit    []LoginAuditEntry         `json:"audit"`
+}
+
+// LoginThrottledError indica que hay que esperar RetryAfter antes de volver a intentar
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	if e.Locked {
+		return fmt.Sprintf("Too many failed login attempts. Try again in %d minutes", int(math.Ceil(e.RetryAfter.Minutes())))
+	}
+	return fmt.Sprintf("Too many login attempts. Try again in %d seconds", seconds)
+}
+
+// NewLoginThrottle carga el estado guardado en path
+func NewLoginThrottle(path string) (*LoginThrottle, error) {
+	t := &LoginThrottle{path: path, now: time.Now, Attempts: map[string]*loginAttempts{}}
+	data, _, err := readFileWithBackups(path, 0, validateJSON)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return t, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, t); err != nil {
+		return t, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	if t.Attempts == nil {
+		t.Attempts = map[string]*loginAttempts{}
+	}
+	return t, nil
+}
+
+// Check devuelve *LoginThrottledError si login (o la instalación) debe esperar.
+// El intento rechazado también queda en la auditoría.
+func (t *LoginThrottle) Check(login string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	var blocked *LoginThrottledError
+	for _, key := range []string{t.keyLocked(login), installThrottleKey} {
+		if err := t.waitLocked(key, now); err != nil && (blocked == nil || err.RetryAfter > blocked.RetryAfter) {
+			blocked = err
+		}
+	}
+	if blocked == nil {
+		return nil
+	}
+	reason := LoginAuditThrottled
+	if blocked.Locked {
+		reason = LoginAuditLocked
+	}
+	t.auditLocked(login, reason, now)
+	t.saveLocked()
+	return blocked
+}
+
+// RecordFailure cuenta un fallo de credenciales de login
+func (t *LoginThrottle) RecordFailure(login string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	t.failLocked(t.keyLocked(login), now, loginLockoutThreshold)
+	t.failLocked(installThrottleKey, now, installLockoutThreshold)
+	t.auditLocked(login, LoginAuditFailed, now)
+	t.saveLocked()
+}
+
+// RecordSuccess borra los fallos de login. El contador de la instalación no se
+// toca: si no, acertar con una cuenta propia entre intentos borraría los fallos
+// contra todas las demás. Ese contador se vence solo con loginFailureWindow.
+func (t *LoginThrottle) RecordSuccess(login string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.Attempts, t.keyLocked(login))
+	t.saveLocked()
+}
+
+// LinkAccount recuerda que los identificadores son de la cuenta userId, para que
+// sus fallos cuenten juntos. Se llama después de un login correcto, así que
+// también borra los fallos de la cuenta y de cada identificador.
+func (t *LoginThrottle) LinkAccount(userId string, identifiers ...string) {
+	if userId == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Accounts == nil {
+		t.Accounts = map[string]string{}
+	}
+	for _, identifier := range identifiers {
+		if name := normalizeLogin(identifier); name != "" {
+			delete(t.Attempts, loginThrottleKey(identifier))
+			t.Accounts[name] = userId
+		}
+	}
+	delete(t.Attempts, accountThrottleKey(userId))
+	t.saveLocked()
+}
+
+// AuditLog devuelve los intentos fallidos registrados, del más nuevo al más viejo
+func (t *LoginThrottle) AuditLog() []LoginAuditEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries := make([]LoginAuditEntry, len(t.Audit))
+	for i, e := range t.Audit {
+		entries[len(t.Audit)-1-i] = e
+	}
+	return entries
+}
+
+const installThrottleKey = "installation"
+
+// normalizeLogin hace que "User" y " user" cuenten juntos
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+func loginThrottleKey(login string) string {
+	return "login:" + normalizeLogin(login)
+}
+
+func accountThrottleKey(userId string) string {
+	return "account:" + userId
+}
+
+// keyLocked es el contador de login: el de su cuenta si se conoce, o el del identificador
+func (t *LoginThrottle) keyLocked(login string) string {
+	if userId := t.Accounts[normalizeLogin(login)]; userId != "" {
+		return accountThrottleKey(userId)
+	}
+	return loginThrottleKey(login)
+}
+
+func (t *LoginThrottle) waitLocked(key string, now time.Time) *LoginThrottledError {
+	attempts := t.Attempts[key]
+	if attempts == nil {
+		return nil
+	}
+	if now.Before(attempts.LockedUntil) {
+		return &LoginThrottledError{RetryAfter: attempts.LockedUntil.Sub(now), Locked: true}
+	}
+	if now.Sub(attempts.LastFailure) > loginFailureWindow {
+		delete(t.Attempts, key)
+		return nil
+	}
+	free := loginFreeAttempts
+	if key == installThrottleKey {
+		free = installFreeAttempts
+	}
+	if attempts.Failures < free {
+		return nil
+	}
+	wait := loginBackoffBase * time.Duration(1<<uint(minInt(attempts.Failures-free, 16)))
+	if wait > loginBackoffMax {
+		wait = loginBackoffMax
+	}
+	if retryAt := attempts.LastFailure.Add(wait); now.Before(retryAt) {
+		return &LoginThrottledError{RetryAfter: retryAt.Sub(now)}
+	}
+	return nil
+}
+
+func (t *LoginThrottle) failLocked(key string, now time.Time, lockoutThreshold int) {
+	attempts := t.Attempts[key]
+	if attempts == nil || now.Sub(attempts.LastFailure) > loginFailureWindow {
+		attempts = &loginAttempts{}
+		t.Attempts[key] = attempts
+	}
+	attempts.Failures++
+	attempts.LastFailure = now
+	if attempts.Failures >= lockoutThreshold {
+		attempts.LockedUntil = now.Add(loginLockoutDuration)
+		attempts.Failures = 0
+	}
+}
+
+func (t *LoginThrottle) auditLocked(login, reason string, now time.Time) {
+	t.Audit = append(t.Audit, LoginAuditEntry{Time: now.Format(time.RFC3339), Login: strings.TrimSpace(login), Reason: reason})
+	if len(t.Audit) > loginAuditMaxEntries {
+		t.Audit = t.Audit[len(t.Audit)-loginAuditMaxEntries:]
+	}
+}
+
+// saveLocked guarda el estado; si falla, el límite sigue valiendo en memoria
+func (t *LoginThrottle) saveLocked() {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return
+	}
+	writeFileAtomic(t.path, data, 0644, 0)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
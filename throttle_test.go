@@ -0,0 +1,153 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestThrottle(t *testing.T) (*LoginThrottle, *time.Time) {
+	t.Helper()
+	throttle, err := NewLoginThrottle(filepath.Join(t.TempDir(), "throttle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+func TestLoginThrottleBackoffPerLogin(t *testing.T) {
+	throttle, now := newTestThrottle(t)
+	for i := 0; i < loginFreeAttempts; i++ {
+		if err := throttle.Check("Ahri"); err != nil {
+			t.Fatalf("attempt %d throttled: %v", i, err)
+		}
+		throttle.RecordFailure("Ahri")
+	}
+	if err := throttle.Check(" ahri "); err == nil {
+		t.Error("expected the same login to wait after the free attempts")
+	}
+	if err := throttle.Check("jinx"); err != nil {
+		t.Errorf("another login was throttled: %v", err)
+	}
+	*now = now.Add(loginBackoffBase)
+	if err := throttle.Check("ahri"); err != nil {
+		t.Errorf("still throttled after the backoff: %v", err)
+	}
+}
+
+// Acertar con una cuenta no borra los fallos de la instalación contra las demás
+func TestLoginThrottleSuccessKeepsInstallationCounter(t *testing.T) {
+	throttle, _ := newTestThrottle(t)
+	for i := 0; i < installFreeAttempts; i++ {
+		throttle.RecordFailure("victim" + string(rune('a'+i)))
+	}
+	throttle.RecordSuccess("mine")
+	if err := throttle.Check("another-victim"); err == nil {
+		t.Error("RecordSuccess reset the installation-wide counter")
+	}
+
+	throttle.RecordFailure("mine")
+	throttle.RecordSuccess("mine")
+	if throttle.Attempts[loginThrottleKey("mine")] != nil {
+		t.Error("RecordSuccess kept the per-login failures")
+	}
+}
+
+func TestLoginThrottleLockout(t *testing.T) {
+	throttle, now := newTestThrottle(t)
+	for i := 0; i < loginLockoutThreshold; i++ {
+		throttle.RecordFailure("ahri")
+	}
+	err := throttle.Check("ahri")
+	throttled, ok := err.(*LoginThrottledError)
+	if !ok || !throttled.Locked {
+		t.Fatalf("got %v, want a lockout", err)
+	}
+	*now = now.Add(loginLockoutDuration)
+	if err := throttle.Check("ahri"); err != nil {
+		t.Errorf("still locked after %v: %v", loginLockoutDuration, err)
+	}
+	if audit := throttle.AuditLog(); len(audit) == 0 || audit[0].Reason != LoginAuditLocked {
+		t.Errorf("audit log = %+v", audit)
+	}
+}
+
+// El login y el email de una cuenta conocida comparten contador
+func TestLoginThrottleLinkedAccount(t *testing.T) {
+	throttle, _ := newTestThrottle(t)
+	throttle.RecordFailure("ahri")
+	throttle.LinkAccount(testUserID, "Ahri", "ahri@example.com")
+	if len(throttle.Attempts) != 1 || throttle.Attempts[installThrottleKey] == nil {
+		t.Errorf("LinkAccount kept per-login failures: %+v", throttle.Attempts)
+	}
+
+	identifiers := []string{"ahri", "AHRI@example.com"}
+	for i := 0; i < loginFreeAttempts; i++ {
+		throttle.RecordFailure(identifiers[i%len(identifiers)])
+	}
+	if err := throttle.Check("ahri@example.com"); err == nil {
+		t.Error("alternating login and email avoided the per-account wait")
+	}
+	if err := throttle.Check("jinx"); err != nil {
+		t.Errorf("another login was throttled: %v", err)
+	}
+
+	// Los alias se guardan con el resto del estado
+	reloaded, err := NewLoginThrottle(throttle.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Accounts["ahri"] != testUserID || reloaded.Accounts["ahri@example.com"] != testUserID {
+		t.Errorf("accounts after reload = %+v", reloaded.Accounts)
+	}
+}
+
+// Solo las credenciales incorrectas cuentan como fallo de login
+func TestLoginCountsOnlyCredentialFailures(t *testing.T) {
+	var status int
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	a := newTestApp(t)
+	a.backend = NewMemoryBackend().WithAuth(NewAuthService(server.URL, "anon", server.Client()))
+	a.loginThrottle, _ = NewLoginThrottle(filepath.Join(t.TempDir(), "throttle.json"))
+
+	tests := []struct {
+		name           string
+		status         int
+		body           string
+		login          string
+		code           ErrorCode
+		counted        bool
+		pendingEmail   string
+		verifyRequired bool
+	}{
+		{"wrong password", 400, `{"error_code":"invalid_credentials","msg":"Invalid login credentials"}`, "ahri", ErrAuthInvalidCredentials, true, "", false},
+		{"old invalid_grant", 400, `{"error":"invalid_grant","error_description":"Invalid login credentials"}`, "ahri@example.com", ErrAuthInvalidCredentials, true, "", false},
+		{"rate limited", 429, `{"error_code":"over_request_rate_limit","msg":"Request rate limit reached"}`, "ahri", ErrAuthRateLimited, false, "", false},
+		{"email not confirmed", 400, `{"error_code":"email_not_confirmed","msg":"Email not confirmed"}`, " ahri@example.com ", ErrAuthEmailUnverified, false, "ahri@example.com", true},
+		{"old email not confirmed", 400, `{"error":"invalid_grant","error_description":"Email not confirmed"}`, "ahri", ErrAuthEmailUnverified, false, "", true},
+		{"validation", 422, `{"error_code":"validation_failed","msg":"Unsupported email"}`, "ahri@", ErrInvalidArgument, false, "", false},
+		{"server error", 502, `bad gateway`, "ahri", ErrAuthUnavailable, false, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body = tt.status, tt.body
+			before := len(a.GetLoginAudit())
+			result := a.Login(tt.login, testPassword)
+			if result.Code != tt.code || result.PendingEmail != tt.pendingEmail || result.EmailVerificationRequired != tt.verifyRequired {
+				t.Errorf("Login = %+v", result)
+			}
+			if counted := len(a.GetLoginAudit()) > before; counted != tt.counted {
+				t.Errorf("counted as a failure = %v, want %v", counted, tt.counted)
+			}
+		})
+	}
+}
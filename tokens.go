@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TokenReservation es una ficha apartada en el servidor para una descarga
+type TokenReservation struct {
+	ID        string `json:"reservationId"`
+	Remaining int    `json:"remaining"` // Fichas que quedan después de esta reserva
+}
+
+// ErrReservationConsumed indica que la reserva ya autorizó una descarga y no se puede devolver
+var ErrReservationConsumed = errors.New("token reservation was already used for a download")
+
+// TokenAccounting gasta las fichas por skin ("fichasporskin") en tres pasos:
+// reservar antes de descargar, confirmar y devolver si la descarga nunca se
+// autorizó. El servidor confirma la reserva al autorizar la descarga
+// (EntitlementService), así que después Refund devuelve ErrReservationConsumed.
+// El servidor deduplica por idempotencyKey, así que reintentar con la misma clave
+// devuelve la misma reserva y nunca cobra dos veces.
+type TokenAccounting interface {
+	Reserve(accessToken, idempotencyKey, championId, skinNum string) (*TokenReservation, error)
+	Commit(accessToken, reservationId string) error
+	Refund(accessToken, reservationId string) error
+}
+
+// SupabaseTokenAccounting implementa TokenAccounting con las funciones
+// reserve_skin_token, commit_skin_token y refund_skin_token de la base
+// (supabase/migrations).
+type SupabaseTokenAccounting struct {
+	projectURL string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewSupabaseTokenAccounting crea el servicio para el proyecto en projectURL
+func NewSupabaseTokenAccounting(projectURL, apiKey string, httpClient *http.Client) *SupabaseTokenAccounting {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &SupabaseTokenAccounting{projectURL: strings.TrimRight(projectURL, "/"), apiKey: apiKey, httpClient: httpClient}
+}
+
+// Reserve aparta una ficha. Devuelve *EntitlementDeniedError con DenialOutOfTokens si no quedan.
+func (s *SupabaseTokenAccounting) Reserve(accessToken, idempotencyKey, championId, skinNum string) (*TokenReservation, error) {
+	var result struct {
+		Allowed       bool   `json:"allowed"`
+		Reason        string `json:"reason"`
+		ReservationId string `json:"reservation_id"`
+		Remaining     int    `json:"remaining"`
+	}
+	err := callRPC(s.httpClient, s.projectURL, s.apiKey, accessToken, "reserve_skin_token", map[string]string{
+		"p_idempotency_key": idempotencyKey,
+		"p_champion_id":     championId,
+		"p_skin_num":        skinNum,
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("token reservation failed: %v", err)
+	}
+	if !result.Allowed {
+		reason := DenialReason(result.Reason)
+		if reason == "" {
+			reason = DenialOutOfTokens
+		}
+		return nil, &EntitlementDeniedError{Reason: reason}
+	}
+	return &TokenReservation{ID: result.ReservationId, Remaining: result.Remaining}, nil
+}
+
+// Commit confirma el gasto de una reserva
+func (s *SupabaseTokenAccounting) Commit(accessToken, reservationId string) error {
+	return callRPC(s.httpClient, s.projectURL, s.apiKey, accessToken, "commit_skin_token", map[string]string{
+		"p_reservation_id": reservationId,
+	}, nil)
+}
+
+// Refund devuelve la ficha de una reserva que no se usó
+func (s *SupabaseTokenAccounting) Refund(accessToken, reservationId string) error {
+	err := callRPC(s.httpClient, s.projectURL, s.apiKey, accessToken, "refund_skin_token", map[string]string{
+		"p_reservation_id": reservationId,
+	}, nil)
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == "55000" {
+		return ErrReservationConsumed
+	}
+	return err
+}
+
+// newIdempotencyKey genera una clave aleatoria para una reserva nueva
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Sin aleatoriedad del SO: la hora alcanza para no chocar en un mismo equipo
+		return fmt.Sprintf("t%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
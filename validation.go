@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Códigos de error de validación. El frontend los usa para traducir el mensaje
+// y marcar el campo; Message es solo el texto por defecto en inglés.
+const (
+	ValidationRequired      = "required"
+	ValidationInvalidEmail  = "invalid_email"
+	ValidationEmailTaken    = "email_taken"
+	ValidationInvalidLogin  = "invalid_login"
+	ValidationLoginTaken    = "login_taken"
+	ValidationReservedName  = "reserved_name"
+	ValidationWeakPassword  = "weak_password"
+	ValidationSamePassword  = "same_password"
+	ValidationWrongPassword = "wrong_password"
+	ValidationRateLimited   = "rate_limited"
+)
+
+// FieldError es un error de validación de un campo de un formulario
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	loginMinLength    = 3
+	loginMaxLength    = 24
+	passwordMinLength = 8
+	passwordMaxLength = 72 // Límite de bcrypt en GoTrue
+)
+
+var regexLogin = regexp.MustCompile(`^[\p{L}\p{N}_.\- ]+$`)
+
+// reservedLogins son nombres que no puede usar ninguna cuenta (comparados en minúsculas)
+var reservedLogins = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true, "support": true,
+	"moderator": true, "mod": true, "staff": true, "skinhunter": true, "official": true,
+	"null": true, "undefined": true, "anonymous": true, "api": true,
+}
+
+// commonPasswords son contraseñas que se rechazan aunque cumplan el largo mínimo
+var commonPasswords = map[string]bool{
+	"password": true, "password1": true, "12345678": true, "123456789": true, "1234567890": true,
+	"qwertyuiop": true, "qwerty123": true, "iloveyou": true, "11111111": true, "abc12345": true,
+	"passw0rd": true, "leagueoflegends": true, "skinhunter": true,
+}
+
+// validateEmail revisa que email sea una dirección simple (sin nombre ni <>)
+func validateEmail(field, email string) *FieldError {
+	if email == "" {
+		return &FieldError{Field: field, Code: ValidationRequired, Message: "Email is required"}
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email || !strings.Contains(email[strings.LastIndex(email, "@"):], ".") {
+		return &FieldError{Field: field, Code: ValidationInvalidEmail, Message: "Invalid email address"}
+	}
+	return nil
+}
+
+// validateLogin revisa largo, caracteres y nombres reservados. No consulta si ya existe.
+func validateLogin(field, login string) *FieldError {
+	n := utf8.RuneCountInString(login)
+	switch {
+	case n == 0:
+		return &FieldError{Field: field, Code: ValidationRequired, Message: "Login is required"}
+	case n < loginMinLength || n > loginMaxLength:
+		return &FieldError{Field: field, Code: ValidationInvalidLogin, Message: "Login must be between 3 and 24 characters"}
+	case !regexLogin.MatchString(login):
+		return &FieldError{Field: field, Code: ValidationInvalidLogin, Message: "Login can only contain letters, numbers, spaces, '.', '-' and '_'"}
+	case reservedLogins[strings.ToLower(strings.TrimSpace(login))]:
+		return &FieldError{Field: field, Code: ValidationReservedName, Message: "This name is reserved"}
+	}
+	return nil
+}
+
+// validatePassword exige largo, letras y números, y que no sea común ni contenga el login o el email
+func validatePassword(field, password, email, login string) *FieldError {
+	n := utf8.RuneCountInString(password)
+	if n == 0 {
+		return &FieldError{Field: field, Code: ValidationRequired, Message: "Password is required"}
+	}
+	if n < passwordMinLength || len(password) > passwordMaxLength {
+		return &FieldError{Field: field, Code: ValidationWeakPassword, Message: "Password must be between 8 and 72 characters"}
+	}
+	hasLetter, hasDigit := false, false
+	for _, r := range password {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasLetter || !hasDigit {
+		return &FieldError{Field: field, Code: ValidationWeakPassword, Message: "Password must contain letters and numbers"}
+	}
+	lower := strings.ToLower(password)
+	if commonPasswords[lower] {
+		return &FieldError{Field: field, Code: ValidationWeakPassword, Message: "This password is too common"}
+	}
+	localPart := email
+	if i := strings.Index(email, "@"); i >= 0 {
+		localPart = email[:i]
+	}
+	for _, personal := range []string{strings.ToLower(login), strings.ToLower(localPart)} {
+		if utf8.RuneCountInString(personal) >= loginMinLength && strings.Contains(lower, personal) {
+			return &FieldError{Field: field, Code: ValidationWeakPassword, Message: "Password cannot contain your login or email"}
+		}
+	}
+	return nil
+}
+
+// validateRegistration revisa el formulario de registro completo
+func validateRegistration(email, password, login string) []FieldError {
+	return collectFieldErrors(
+		validateEmail("email", email),
+		validateLogin("login", login),
+		validatePassword("password", password, email, login),
+	)
+}
+
+// collectFieldErrors junta los errores no nulos
+func collectFieldErrors(errs ...*FieldError) []FieldError {
+	fieldErrors := []FieldError{}
+	for _, e := range errs {
+		if e != nil {
+			fieldErrors = append(fieldErrors, *e)
+		}
+	}
+	return fieldErrors
+}
+
+// checkLoginAvailable consulta si login ya pertenece a una cuenta distinta de
+// userId. accessToken y userId van vacíos durante el registro.
+func (a *App) checkLoginAvailable(field, login, accessToken, userId string) (*FieldError, error) {
+	taken, err := a.backend.Users().LoginTaken(accessToken, userId, login)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return &FieldError{Field: field, Code: ValidationLoginTaken, Message: "This login is already taken"}, nil
+	}
+	return nil, nil
+}
+
+// fieldErrorFromAuth traduce los errores de GoTrue que corresponden a un campo
+// (email repetido, contraseña débil, límite de envíos) para no mostrar el texto crudo
+func fieldErrorFromAuth(err error, emailField, passwordField string) *FieldError {
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		return nil
+	}
+	switch {
+	case authErr.Code == "user_already_exists" || authErr.Code == "email_exists":
+		return &FieldError{Field: emailField, Code: ValidationEmailTaken, Message: "An account with this email already exists"}
+	case authErr.Code == "email_address_invalid" || authErr.Code == "validation_failed":
+		return &FieldError{Field: emailField, Code: ValidationInvalidEmail, Message: "Invalid email address"}
+	case authErr.Code == "weak_password":
+		return &FieldError{Field: passwordField, Code: ValidationWeakPassword, Message: "Password is too weak"}
+	case authErr.Code == "same_password":
+		return &FieldError{Field: passwordField, Code: ValidationSamePassword, Message: "New password must be different from the current one"}
+	case authErr.Status == 429 || strings.HasPrefix(authErr.Code, "over_"):
+		return &FieldError{Field: emailField, Code: ValidationRateLimited, Message: "Too many attempts, please try again later"}
+	}
+	return nil
+}
+
+// fieldErrorsResult es la respuesta estándar de un formulario con errores de validación.
+// Cada mensaje se traduce según su código.
+func fieldErrorsResult(fieldErrors []FieldError) AuthResult {
+	for i := range fieldErrors {
+		fieldErrors[i].Message = tr(fieldMessageKey(fieldErrors[i].Code))
+	}
+	code := ErrValidationFailed
+	if fieldErrors[0].Code == ValidationRateLimited {
+		code = ErrAuthRateLimited
+	}
+	return AuthResult{
+		Result:      failResult(code, fieldErrors[0].Message, nil),
+		FieldErrors: fieldErrors,
+	}
+}
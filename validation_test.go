@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// code devuelve el código de e, o "" si no hubo error
+func code(e *FieldError) string {
+	if e == nil {
+		return ""
+	}
+	return e.Code
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"ahri@example.com", ""},
+		{"first.last+tag@sub.example.org", ""},
+		{"", ValidationRequired},
+		{"ahri", ValidationInvalidEmail},
+		{"ahri@localhost", ValidationInvalidEmail},
+		{"ahri@@example.com", ValidationInvalidEmail},
+		{"Ahri <ahri@example.com>", ValidationInvalidEmail},
+		{" ahri@example.com", ValidationInvalidEmail},
+	}
+	for _, tt := range tests {
+		if got := code(validateEmail("email", tt.email)); got != tt.want {
+			t.Errorf("validateEmail(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestValidateLogin(t *testing.T) {
+	tests := []struct {
+		login string
+		want  string
+	}{
+		{"ahri", ""},
+		{"Nine Tailed_Fox-1.0", ""},
+		{"Ñandú", ""},
+		{"", ValidationRequired},
+		{"ab", ValidationInvalidLogin},
+		{strings.Repeat("a", loginMaxLength), ""},
+		{strings.Repeat("a", loginMaxLength+1), ValidationInvalidLogin},
+		{"ahri@rift", ValidationInvalidLogin},
+		{"<script>", ValidationInvalidLogin},
+		{"admin", ValidationReservedName},
+		{"Admin", ValidationReservedName},
+		{"SkinHunter", ValidationReservedName},
+		{"administrators", ""},
+	}
+	for _, tt := range tests {
+		if got := code(validateLogin("login", tt.login)); got != tt.want {
+			t.Errorf("validateLogin(%q) = %q, want %q", tt.login, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     string
+	}{
+		{"valid", "blue-sky-42", ""},
+		{"empty", "", ValidationRequired},
+		{"too short", "abc123", ValidationWeakPassword},
+		{"too long", strings.Repeat("a1", passwordMaxLength/2+1), ValidationWeakPassword},
+		{"letters only", "onlyletters", ValidationWeakPassword},
+		{"digits only", "1234509876", ValidationWeakPassword},
+		{"common", "Password1", ValidationWeakPassword},
+		{"contains login", "ahri2024x", ValidationWeakPassword},
+		{"contains email name", "xx-foxy99", ValidationWeakPassword},
+	}
+	for _, tt := range tests {
+		if got := code(validatePassword("password", tt.password, "foxy@example.com", "Ahri")); got != tt.want {
+			t.Errorf("%s: validatePassword(%q) = %q, want %q", tt.name, tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRegistrationCollectsEveryField(t *testing.T) {
+	errs := validateRegistration("bad", "short", "admin")
+	fields := map[string]string{}
+	for _, e := range errs {
+		fields[e.Field] = e.Code
+	}
+	want := map[string]string{"email": ValidationInvalidEmail, "login": ValidationReservedName, "password": ValidationWeakPassword}
+	for field, c := range want {
+		if fields[field] != c {
+			t.Errorf("%s = %q, want %q (all: %+v)", field, fields[field], c, errs)
+		}
+	}
+	if errs := validateRegistration("ahri@example.com", "blue-sky-42", "ahri"); len(errs) != 0 {
+		t.Errorf("valid form: %+v", errs)
+	}
+}
+
+func TestFieldErrorFromAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantField string
+		wantCode  string
+	}{
+		{"email taken", &AuthError{Status: 422, Code: "user_already_exists"}, "email", ValidationEmailTaken},
+		{"email exists", &AuthError{Status: 422, Code: "email_exists"}, "email", ValidationEmailTaken},
+		{"invalid email", &AuthError{Status: 400, Code: "email_address_invalid"}, "email", ValidationInvalidEmail},
+		{"weak password", &AuthError{Status: 422, Code: "weak_password"}, "newPassword", ValidationWeakPassword},
+		{"same password", &AuthError{Status: 422, Code: "same_password"}, "newPassword", ValidationSamePassword},
+		{"rate limited", &AuthError{Status: 429}, "email", ValidationRateLimited},
+		{"email rate limit", &AuthError{Status: 400, Code: "over_email_send_rate_limit"}, "email", ValidationRateLimited},
+		{"parsed from gotrue-go", parseAuthError(errors.New(`response status code 422: {"error_code":"weak_password","msg":"Password is too weak"}`)), "newPassword", ValidationWeakPassword},
+		{"unrelated auth error", &AuthError{Status: 400, Code: "invalid_credentials"}, "", ""},
+		{"server error", &AuthError{Status: 500}, "", ""},
+		{"not an auth error", errors.New("connection refused"), "", ""},
+	}
+	for _, tt := range tests {
+		got := fieldErrorFromAuth(tt.err, "email", "newPassword")
+		if tt.wantCode == "" {
+			if got != nil {
+				t.Errorf("%s: got %+v, want nil", tt.name, got)
+			}
+			continue
+		}
+		if got == nil || got.Field != tt.wantField || got.Code != tt.wantCode {
+			t.Errorf("%s: got %+v, want %s/%s", tt.name, got, tt.wantField, tt.wantCode)
+		}
+	}
+}
+
+func TestCheckLoginAvailable(t *testing.T) {
+	a := newTestApp(t)
+	backend := NewMemoryBackend()
+	backend.AddUser(UserProfile{ID: "u1", Login: "Ahri"}, "ahri@example.com", "")
+	a.backend = backend
+
+	if taken, err := a.checkLoginAvailable("login", "ahri", "", ""); err != nil || code(taken) != ValidationLoginTaken {
+		t.Errorf("registering an existing login: %+v, %v", taken, err)
+	}
+	if taken, err := a.checkLoginAvailable("displayName", "AHRI", "token", "u1"); err != nil || taken != nil {
+		t.Errorf("keeping your own login: %+v, %v", taken, err)
+	}
+	if taken, err := a.checkLoginAvailable("login", "jinx", "", ""); err != nil || taken != nil {
+		t.Errorf("free login: %+v, %v", taken, err)
+	}
+}